@@ -0,0 +1,81 @@
+// cmd/genai-stub/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"camunda-workers/internal/common/genaistub"
+)
+
+// genai-stub serves /api/ai/parse-intent and /api/ai/generate so the AI
+// workers can run without the GenAI service. Flags override the faults in
+// the config file; faults can also be changed at runtime with
+// PUT /_stub/faults.
+func main() {
+	addr := flag.String("addr", ":8085", "Listen address")
+	configPath := flag.String("config", "", "Path to a JSON stub config (script, intent rules, faults)")
+	latency := flag.Duration("latency", 0, "Delay added to every response")
+	jitter := flag.Duration("jitter", 0, "Random extra delay, up to this much")
+	errorRate := flag.Float64("error-rate", 0, "Share of requests (0-1) answered with -error-status")
+	errorStatus := flag.Int("error-status", http.StatusServiceUnavailable, "Status code of injected errors")
+	malformedRate := flag.Float64("malformed-rate", 0, "Share of requests (0-1) answered with truncated JSON")
+	seed := flag.Int64("seed", 0, "Seed for fault injection (0 uses the clock)")
+	flag.Parse()
+
+	var cfg genaistub.Config
+	if *configPath != "" {
+		var err error
+		if cfg, err = genaistub.LoadConfig(*configPath); err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "latency":
+			cfg.Faults.LatencyMs = int(latency.Milliseconds())
+		case "jitter":
+			cfg.Faults.JitterMs = int(jitter.Milliseconds())
+		case "error-rate":
+			cfg.Faults.ErrorRate = *errorRate
+		case "error-status":
+			cfg.Faults.ErrorStatus = *errorStatus
+		case "malformed-rate":
+			cfg.Faults.MalformedRate = *malformedRate
+		case "seed":
+			cfg.Seed = *seed
+		}
+	})
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           genaistub.New(cfg).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		fmt.Printf("GenAI stub listening on %s (faults: %+v, %d scripted responses)\n", *addr, cfg.Faults, len(cfg.Script))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Error serving: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		fmt.Printf("Error shutting down: %v\n", err)
+	}
+}
@@ -0,0 +1,444 @@
+// cmd/tools/bpmn-lint/main.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"camunda-workers/pkg/bpmn"
+	"camunda-workers/pkg/registry"
+)
+
+// Finding kinds, printed in front of each message.
+const (
+	checkUnknownTaskType = "unknown-task-type"
+	checkErrorCode       = "error-code"
+	checkBoundary        = "boundary"
+	checkGatewayVariable = "gateway-variable"
+	checkDanglingFlow    = "dangling-flow"
+	checkCondition       = "condition"
+)
+
+type finding struct {
+	check   string
+	process string
+	element string
+	message string
+}
+
+func main() {
+	registryPath := flag.String("registry", "configs/activity-registry.json", "Path to registry file")
+	bpmnDir := flag.String("dir", "bpmn", "Directory containing the BPMN files")
+	flag.Parse()
+
+	reg, err := registry.LoadRegistry(*registryPath)
+	if err != nil {
+		fmt.Printf("Error loading registry: %v\n", err)
+		os.Exit(1)
+	}
+
+	files, err := bpmn.ParseDir(*bpmnDir)
+	if err != nil {
+		fmt.Printf("Error parsing BPMN: %v\n", err)
+		os.Exit(1)
+	}
+
+	l := newLinter(reg)
+	total := 0
+	for _, defs := range files {
+		findings := l.lint(defs)
+		if len(findings) == 0 {
+			continue
+		}
+		total += len(findings)
+
+		fmt.Printf("%s:\n", defs.File)
+		for _, f := range findings {
+			fmt.Printf("  [%s] %s/%s: %s\n", f.check, f.process, f.element, f.message)
+		}
+	}
+
+	if total > 0 {
+		fmt.Printf("\n%d findings in %d BPMN files.\n", total, len(files))
+		os.Exit(1)
+	}
+	fmt.Printf("%d BPMN files checked, no findings.\n", len(files))
+}
+
+type linter struct {
+	reg *registry.ActivityRegistry
+
+	// throwable holds every error code some activity declares.
+	throwable map[string]bool
+}
+
+func newLinter(reg *registry.ActivityRegistry) *linter {
+	throwable := make(map[string]bool)
+	for _, a := range reg.Activities {
+		for _, code := range a.ErrorCodes {
+			throwable[code] = true
+		}
+	}
+	return &linter{reg: reg, throwable: throwable}
+}
+
+func (l *linter) lint(defs *bpmn.Definitions) []finding {
+	var findings []finding
+	findings = append(findings, l.checkErrorDefinitions(defs)...)
+	for _, p := range defs.Processes {
+		findings = append(findings, l.checkTaskTypes(p)...)
+		findings = append(findings, l.checkBoundaries(defs, p)...)
+		findings = append(findings, l.checkGateways(p)...)
+		findings = append(findings, checkFlows(p)...)
+		findings = append(findings, checkConditions(p)...)
+	}
+	return findings
+}
+
+// checkErrorDefinitions reports bpmn:error codes that no activity declares.
+func (l *linter) checkErrorDefinitions(defs *bpmn.Definitions) []finding {
+	ids := make([]string, 0, len(defs.Errors))
+	for id := range defs.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var findings []finding
+	for _, id := range ids {
+		e := defs.Errors[id]
+		if e.Code == "" || l.throwable[e.Code] {
+			continue
+		}
+		findings = append(findings, finding{
+			check:   checkErrorCode,
+			process: "definitions",
+			element: id,
+			message: fmt.Sprintf("error code %q is not thrown by any activity", e.Code),
+		})
+	}
+	return findings
+}
+
+// checkTaskTypes reports service tasks whose type matches no activity or alias.
+func (l *linter) checkTaskTypes(p *bpmn.Process) []finding {
+	var findings []finding
+	for _, id := range p.Order {
+		el := p.Elements[id]
+		if el.TaskType == "" || strings.HasPrefix(el.TaskType, "=") {
+			continue
+		}
+		if _, ok := l.reg.Resolve(el.TaskType); !ok {
+			findings = append(findings, finding{
+				check:   checkUnknownTaskType,
+				process: p.ID,
+				element: el.Label(),
+				message: fmt.Sprintf("task type %q has no matching activity or alias", el.TaskType),
+			})
+		}
+	}
+	return findings
+}
+
+// checkBoundaries compares the boundary events on each service task with the
+// registry entry of the activity that serves it: caught error codes must be
+// declared by the activity, timers must not fire before a single attempt can
+// time out, and retries or timeouts set in the model must match the registry.
+func (l *linter) checkBoundaries(defs *bpmn.Definitions, p *bpmn.Process) []finding {
+	var findings []finding
+	report := func(el *bpmn.Element, format string, args ...interface{}) {
+		findings = append(findings, finding{
+			check:   checkBoundary,
+			process: p.ID,
+			element: el.Label(),
+			message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, id := range p.Order {
+		task := p.Elements[id]
+		boundaries := p.Boundaries(task.ID)
+		if task.TaskType == "" || len(boundaries) == 0 {
+			continue
+		}
+		activity, ok := l.reg.Resolve(task.TaskType)
+		if !ok {
+			continue // already reported as unknown
+		}
+		timeout, timeoutErr := time.ParseDuration(activity.Timeout)
+
+		if task.Retries > 0 && task.Retries != activity.Retries {
+			report(task, "retries %d in model, registry %s has %d", task.Retries, activity.ID, activity.Retries)
+		}
+		if header, ok := task.Headers["timeout"]; ok && timeoutErr == nil {
+			if d, err := bpmn.ParseDuration(header); err == nil && d != timeout {
+				report(task, "timeout header %s, registry %s has %s", header, activity.ID, activity.Timeout)
+			}
+		}
+
+		for _, b := range boundaries {
+			switch b.EventKind {
+			case bpmn.EventError:
+				e, ok := defs.Errors[b.ErrorRef]
+				if !ok || e.Code == "" || !l.throwable[e.Code] {
+					continue // catch-all or already reported on the definition
+				}
+				if !contains(activity.ErrorCodes, e.Code) {
+					report(b, "catches %q but %s never throws it", e.Code, activity.ID)
+				}
+			case bpmn.EventTimer:
+				if timeoutErr != nil {
+					continue
+				}
+				d, err := bpmn.ParseDuration(b.TimerDuration)
+				if err != nil {
+					report(b, "timer duration %q: %v", b.TimerDuration, err)
+					continue
+				}
+				if d < timeout {
+					report(b, "timer %s fires before %s's %s job timeout", b.TimerDuration, activity.ID, activity.Timeout)
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// checkGateways reports gateway conditions that read variables no upstream
+// element produces. Upstream elements with open-ended outputs (unknown tasks,
+// user tasks, call activities without output mappings) make the check skip
+// the gateway.
+func (l *linter) checkGateways(p *bpmn.Process) []finding {
+	var findings []finding
+	for _, id := range p.Order {
+		gw := p.Elements[id]
+		if gw.Kind != bpmn.KindExclusiveGateway && gw.Kind != "inclusiveGateway" {
+			continue
+		}
+
+		produced, open := l.upstreamVariables(p, gw)
+		if open {
+			continue
+		}
+
+		missing := make(map[string]bool)
+		for _, flow := range p.OutgoingFlows(gw.ID) {
+			if flow.Condition == "" {
+				continue
+			}
+			for _, name := range bpmn.ConditionVariables(flow.Condition) {
+				if !produced[name] {
+					missing[name] = true
+				}
+			}
+		}
+
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			findings = append(findings, finding{
+				check:   checkGatewayVariable,
+				process: p.ID,
+				element: gw.Label(),
+				message: fmt.Sprintf("condition reads %q, which no upstream activity produces", name),
+			})
+		}
+	}
+	return findings
+}
+
+// upstreamVariables collects the variables produced by every element that can
+// run before el. open is true when one of them can produce arbitrary variables.
+func (l *linter) upstreamVariables(p *bpmn.Process, el *bpmn.Element) (produced map[string]bool, open bool) {
+	produced = make(map[string]bool)
+	visited := map[string]bool{el.ID: true}
+	queue := predecessors(p, el)
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if visited[cur.ID] {
+			continue
+		}
+		visited[cur.ID] = true
+
+		for _, m := range cur.Outputs {
+			produced[m.Target] = true
+		}
+		switch {
+		case cur.TaskType != "" && len(cur.Outputs) == 0:
+			activity, ok := l.reg.Resolve(cur.TaskType)
+			if !ok {
+				return produced, true
+			}
+			props, _ := activity.OutputSchema["properties"].(map[string]interface{})
+			for name := range props {
+				produced[name] = true
+			}
+		case (cur.Kind == bpmn.KindUserTask || cur.Kind == bpmn.KindCallActivity) && len(cur.Outputs) == 0:
+			return produced, true
+		}
+
+		queue = append(queue, predecessors(p, cur)...)
+	}
+	return produced, false
+}
+
+// predecessors returns the sources of el's incoming flows and, for a boundary
+// event, the activity it is attached to.
+func predecessors(p *bpmn.Process, el *bpmn.Element) []*bpmn.Element {
+	var prev []*bpmn.Element
+	for _, flow := range p.Flows {
+		if flow.Target != el.ID {
+			continue
+		}
+		if src, ok := p.Elements[flow.Source]; ok {
+			prev = append(prev, src)
+		}
+	}
+	if host, ok := p.Elements[el.AttachedTo]; ok {
+		prev = append(prev, host)
+	}
+	return prev
+}
+
+// checkFlows reports sequence flows that do not connect two elements and
+// element or gateway references to flows that do not exist.
+func checkFlows(p *bpmn.Process) []finding {
+	var findings []finding
+	report := func(element, format string, args ...interface{}) {
+		findings = append(findings, finding{
+			check:   checkDanglingFlow,
+			process: p.ID,
+			element: element,
+			message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, id := range p.FlowIDs() {
+		flow := p.Flows[id]
+		if src, ok := p.Elements[flow.Source]; !ok {
+			report(id, "source %q does not exist", flow.Source)
+		} else if len(src.Outgoing) > 0 && !contains(src.Outgoing, id) {
+			report(id, "not listed as outgoing of %s", flow.Source)
+		}
+		if dst, ok := p.Elements[flow.Target]; !ok {
+			report(id, "target %q does not exist", flow.Target)
+		} else if len(dst.Incoming) > 0 && !contains(dst.Incoming, id) {
+			report(id, "not listed as incoming of %s", flow.Target)
+		}
+	}
+
+	for _, elID := range p.Order {
+		el := p.Elements[elID]
+		for _, ref := range el.Incoming {
+			if _, ok := p.Flows[ref]; !ok {
+				report(el.Label(), "incoming flow %q does not exist", ref)
+			}
+		}
+		for _, ref := range el.Outgoing {
+			if _, ok := p.Flows[ref]; !ok {
+				report(el.Label(), "outgoing flow %q does not exist", ref)
+			}
+		}
+		if el.DefaultFlow != "" {
+			if flow, ok := p.Flows[el.DefaultFlow]; !ok || flow.Source != el.ID {
+				report(el.Label(), "default flow %q is not one of its outgoing flows", el.DefaultFlow)
+			}
+		}
+	}
+	return findings
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyOperators are JUEL operators that FEEL spells differently.
+var legacyOperators = []struct{ op, feel string }{
+	{"==", "="}, {"&&", "and"}, {"||", "or"}, {"!", "not()"},
+}
+
+// checkConditions reports sequence flow conditions Zeebe will not evaluate:
+// conditions without the FEEL "=" prefix, the JUEL "${...}" wrapper and
+// JUEL operators outside string literals.
+func checkConditions(p *bpmn.Process) []finding {
+	var findings []finding
+	for _, id := range p.FlowIDs() {
+		cond := strings.TrimSpace(p.Flows[id].Condition)
+		if cond == "" {
+			continue
+		}
+		report := func(format string, args ...interface{}) {
+			findings = append(findings, finding{
+				check:   checkCondition,
+				process: p.ID,
+				element: id,
+				message: fmt.Sprintf("condition %q ", cond) + fmt.Sprintf(format, args...),
+			})
+		}
+
+		if !strings.HasPrefix(cond, "=") {
+			report("is not a FEEL expression, which starts with \"=\"")
+		}
+		code := withoutStrings(cond)
+		if strings.Contains(code, "${") {
+			report("uses the JUEL ${...} wrapper")
+		}
+		for _, legacy := range legacyOperators {
+			if containsOperator(code, legacy.op) {
+				report("uses %s, which is %s in FEEL", legacy.op, legacy.feel)
+			}
+		}
+	}
+	return findings
+}
+
+// withoutStrings blanks the string literals of a condition.
+func withoutStrings(cond string) string {
+	var sb strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range cond {
+		switch {
+		case quote == 0:
+			if r == '"' || r == '\'' {
+				quote = r
+			}
+			sb.WriteRune(r)
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == quote:
+			quote = 0
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// containsOperator reports whether code uses op; a "!" only counts when it
+// does not start "!=".
+func containsOperator(code, op string) bool {
+	if op != "!" {
+		return strings.Contains(code, op)
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] == '!' && (i+1 == len(code) || code[i+1] != '=') {
+			return true
+		}
+	}
+	return false
+}
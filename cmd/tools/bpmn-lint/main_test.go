@@ -0,0 +1,99 @@
+// cmd/tools/bpmn-lint/main_test.go
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"camunda-workers/pkg/bpmn"
+	"camunda-workers/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintFixture lints testdata/<name>.bpmn against testdata/registry.json and
+// returns the findings as printed by the tool.
+func lintFixture(t *testing.T, name string) []string {
+	reg, err := registry.LoadRegistry(filepath.Join("testdata", "registry.json"))
+	require.NoError(t, err)
+	defs, err := bpmn.ParseFile(filepath.Join("testdata", name+".bpmn"))
+	require.NoError(t, err)
+
+	printed := []string{}
+	for _, f := range newLinter(reg).lint(defs) {
+		printed = append(printed, fmt.Sprintf("[%s] %s/%s: %s", f.check, f.process, f.element, f.message))
+	}
+	return printed
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected []string
+	}{
+		{
+			fixture:  "clean",
+			expected: []string{},
+		},
+		{
+			fixture: "unknown-task-type",
+			expected: []string{
+				`[unknown-task-type] unknown-task-type/Score (Check Score): task type "check-scroe" has no matching activity or alias`,
+			},
+		},
+		{
+			fixture: "error-code",
+			expected: []string{
+				`[error-code] definitions/Error_NameOnly: error code "NAME_ONLY_CODE" is not thrown by any activity`,
+				`[error-code] definitions/Error_Undeclared: error code "NEVER_THROWN" is not thrown by any activity`,
+			},
+		},
+		{
+			fixture: "boundary",
+			expected: []string{
+				`[boundary] boundary/Score (Check Score): retries 5 in model, registry check-score has 3`,
+				`[boundary] boundary/Score (Check Score): timeout header PT20S, registry check-score has 10s`,
+				`[boundary] boundary/Score_Timeout: timer PT5S fires before check-score's 10s job timeout`,
+				`[boundary] boundary/Score_NotifyFailed: catches "NOTIFY_FAILED" but check-score never throws it`,
+				`[boundary] boundary/Score_BadTimer: timer duration "30 seconds": invalid ISO 8601 duration "30 seconds"`,
+			},
+		},
+		{
+			fixture: "gateway-variable",
+			expected: []string{
+				`[gateway-variable] gateway-variable/Route (Route): condition reads "priority", which no upstream activity produces`,
+				`[gateway-variable] gateway-variable/Route (Route): condition reads "sent", which no upstream activity produces`,
+			},
+		},
+		{
+			fixture: "dangling-flow",
+			expected: []string{
+				`[dangling-flow] dangling-flow/Flow_Elsewhere: not listed as outgoing of Start`,
+				`[dangling-flow] dangling-flow/Flow_Elsewhere: not listed as incoming of End`,
+				`[dangling-flow] dangling-flow/Flow_ToEnd: target "Ending" does not exist`,
+				`[dangling-flow] dangling-flow/Score: outgoing flow "Flow_Missing" does not exist`,
+				`[dangling-flow] dangling-flow/Route: default flow "Flow_Elsewhere" is not one of its outgoing flows`,
+			},
+		},
+		{
+			fixture: "condition",
+			expected: []string{
+				`[condition] condition/Flow_Juel: condition "${sent == true}" is not a FEEL expression, which starts with "="`,
+				`[condition] condition/Flow_Juel: condition "${sent == true}" uses the JUEL ${...} wrapper`,
+				`[condition] condition/Flow_Juel: condition "${sent == true}" uses ==, which is = in FEEL`,
+				`[condition] condition/Flow_Or: condition "=sent = false || sent = null" uses ||, which is or in FEEL`,
+				`[condition] condition/Flow_Wrapped: condition "=${sent && !false}" uses the JUEL ${...} wrapper`,
+				`[condition] condition/Flow_Wrapped: condition "=${sent && !false}" uses &&, which is and in FEEL`,
+				`[condition] condition/Flow_Wrapped: condition "=${sent && !false}" uses !, which is not() in FEEL`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			assert.Equal(t, tt.expected, lintFixture(t, tt.fixture))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// cmd/tools/rag-reindex/main.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/database"
+	"camunda-workers/internal/common/retrieval"
+	"camunda-workers/internal/common/usage"
+)
+
+// rag-reindex chunks the franchise descriptions, outlets and FDD documents
+// in Postgres, embeds the chunks with the configured provider and writes
+// them to the retrieval index query-internal-data searches.
+func main() {
+	franchiseID := flag.String("franchise", "", "Reindex this franchise only")
+	recreate := flag.Bool("recreate", false, "Drop and recreate the index, e.g. after changing the embedding model")
+	batch := flag.Int("batch", 32, "Chunks embedded per provider call")
+	dryRun := flag.Bool("dry-run", false, "Only print the documents and chunks that would be indexed")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fail("Error loading config: %v", err)
+	}
+	if *batch <= 0 {
+		fail("-batch must be positive")
+	}
+	ctx := context.Background()
+
+	pg, err := database.NewPostgres(cfg.Database.Postgres)
+	if err != nil {
+		fail("Error connecting to PostgreSQL: %v", err)
+	}
+	defer pg.Close()
+
+	docs, err := retrieval.LoadCorpus(ctx, pg.DB, *franchiseID)
+	if err != nil {
+		fail("Error loading documents: %v", err)
+	}
+	var chunks []retrieval.Chunk
+	franchises := make(map[string]bool)
+	for _, doc := range docs {
+		chunks = append(chunks, retrieval.Split(doc, cfg.Retrieval.ChunkSize, cfg.Retrieval.ChunkOverlap)...)
+		franchises[doc.FranchiseID] = true
+	}
+	fmt.Printf("%d documents of %d franchises, %d chunks.\n", len(docs), len(franchises), len(chunks))
+	if *dryRun || len(chunks) == 0 {
+		return
+	}
+
+	es, err := database.NewElasticsearch(cfg.Database.Elasticsearch)
+	if err != nil {
+		fail("Error connecting to Elasticsearch: %v", err)
+	}
+	embedder, err := retrieval.NewEmbedder(cfg)
+	if err != nil {
+		fail("Error creating embedder: %v", err)
+	}
+	embedder = usage.Embedder(embedder, usage.New(cfg.Usage, pg.DB, nil))
+	ctx = usage.WithScope(ctx, usage.Scope{TaskType: "rag-reindex"})
+	index := retrieval.NewESIndex(es.Client, cfg.Retrieval)
+
+	for start := 0; start < len(chunks); start += *batch {
+		end := min(start+*batch, len(chunks))
+		part := chunks[start:end]
+
+		texts := make([]string, len(part))
+		for i, c := range part {
+			texts[i] = c.Title + "\n" + c.Text
+		}
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			fail("Error embedding chunks %d-%d: %v", start, end, err)
+		}
+		for i := range part {
+			part[i].Embedding = vectors[i]
+		}
+
+		// The first batch tells the embedding size the index is created
+		// with. Existing passages of the franchises are replaced as a whole
+		// so that chunks of deleted or shortened documents go away.
+		if start == 0 {
+			if err := index.EnsureIndex(ctx, len(vectors[0]), *recreate); err != nil {
+				fail("Error preparing index: %v", err)
+			}
+			if !*recreate {
+				for id := range franchises {
+					if err := index.DeleteFranchise(ctx, id); err != nil {
+						fail("Error removing old passages: %v", err)
+					}
+				}
+			}
+		}
+
+		if err := index.Index(ctx, part); err != nil {
+			fail("Error indexing chunks %d-%d: %v", start, end, err)
+		}
+		fmt.Printf("Indexed %d/%d chunks.\n", end, len(chunks))
+	}
+	fmt.Printf("Index %s is up to date.\n", cfg.Retrieval.Index)
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+	os.Exit(1)
+}
@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"camunda-workers/pkg/bpmn"
 	"camunda-workers/pkg/registry"
 )
 
@@ -19,6 +22,7 @@ func main() {
 	addCmd := flag.NewFlagSet("add", flag.ExitOnError)
 	updateCmd := flag.NewFlagSet("update", flag.ExitOnError)
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
+	lintCmd := flag.NewFlagSet("lint-bpmn", flag.ExitOnError)
 
 	// Add command flags
 	idAdd := addCmd.String("id", "", "Activity ID (e.g., validate-subscription)")
@@ -37,6 +41,10 @@ func main() {
 	// Validate command flags
 	validateCmd.StringVar(&registryPath, "path", "configs/activity-registry.json", "Path to registry file")
 
+	// Lint command flags
+	lintCmd.StringVar(&registryPath, "path", "configs/activity-registry.json", "Path to registry file")
+	bpmnDir := lintCmd.String("dir", "bpmn", "Directory containing the BPMN files")
+
 	if len(os.Args) < 2 {
 		help()
 		os.Exit(1)
@@ -96,6 +104,19 @@ func main() {
 		}
 		fmt.Println("Registry validation passed.")
 
+	case "lint-bpmn":
+		lintCmd.Parse(os.Args[2:])
+		unknown, err := lintBPMN(*bpmnDir)
+		if err != nil {
+			fmt.Printf("BPMN lint failed: %v\n", err)
+			os.Exit(1)
+		}
+		if unknown > 0 {
+			fmt.Printf("Found %d task types with no matching activity or alias.\n", unknown)
+			os.Exit(1)
+		}
+		fmt.Println("All BPMN task types match an activity.")
+
 	case "help":
 		fallthrough
 	default:
@@ -156,6 +177,8 @@ func updateActivity(id, field, value string) error {
 				reg.Activities[i].Category = value
 			case "taskType":
 				reg.Activities[i].TaskType = value
+			case "aliases":
+				reg.Activities[i].Aliases = splitList(value)
 			case "timeout":
 				reg.Activities[i].Timeout = value
 			case "retries":
@@ -210,10 +233,75 @@ func validateRegistry() error {
 		}
 	}
 
+	if err := duplicateJobTypesError(reg.DuplicateJobTypes()); err != nil {
+		return err
+	}
+
 	fmt.Printf("Registry validation passed. Found %d activities.\n", len(reg.Activities))
 	return nil
 }
 
+// duplicateJobTypesError reports every task type claimed by more than one
+// activity in a single error, sorted by task type so runs are comparable.
+func duplicateJobTypesError(duplicates map[string][]string) error {
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(duplicates))
+	for name := range duplicates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("  %s: %s", name, strings.Join(duplicates[name], ", "))
+	}
+	return fmt.Errorf("task types claimed by several activities:\n%s", strings.Join(lines, "\n"))
+}
+
+// lintBPMN reports every task type used in dir that no activity serves by
+// ID, task type or alias, and returns how many there are.
+func lintBPMN(dir string) (int, error) {
+	reg, err := registry.LoadRegistry(registryPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	usage, err := bpmn.ScanTaskTypes(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan BPMN: %w", err)
+	}
+
+	taskTypes := make([]string, 0, len(usage))
+	for taskType := range usage {
+		taskTypes = append(taskTypes, taskType)
+	}
+	sort.Strings(taskTypes)
+
+	unknown := 0
+	for _, taskType := range taskTypes {
+		if _, ok := reg.Resolve(taskType); ok {
+			continue
+		}
+		unknown++
+		fmt.Printf("  %s: no matching activity or alias (used in %s)\n", taskType, strings.Join(usage[taskType], ", "))
+	}
+	return unknown, nil
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // saveRegistry handles saving the registry to file
 func saveRegistry(reg *registry.ActivityRegistry, path string) error {
 	data, err := json.MarshalIndent(reg, "", "  ")
@@ -236,19 +324,22 @@ func saveRegistry(reg *registry.ActivityRegistry, path string) error {
 }
 
 func help() {
-	fmt.Println(`
+	fmt.Print(`
 Usage: registry-updater <command> [flags]
 
 Commands:
-  add     Add a new activity to the registry
-  update  Update an existing activity's field
-  validate Validate the registry file
-  help    Show this help message
+  add       Add a new activity to the registry
+  update    Update an existing activity's field
+  validate  Validate the registry file
+  lint-bpmn Report BPMN task types with no matching activity or alias
+  help      Show this help message
 
 Examples:
   registry-updater add -id validate-subscription -displayName "Validate Subscription" -description "Validates user subscription status" -category infrastructure -taskType validate-subscription
   registry-updater update -id validate-subscription -field status -value completed
+  registry-updater update -id email-send -field aliases -value email.send,notification.email.send
   registry-updater validate -path configs/activity-registry.json
+  registry-updater lint-bpmn -path configs/activity-registry.json -dir bpmn
 
 Use 'registry-updater <command> -h' for more information about a command.
 `)
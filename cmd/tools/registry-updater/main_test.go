@@ -0,0 +1,26 @@
+// cmd/tools/registry-updater/main_test.go
+package main
+
+import (
+	"testing"
+
+	"camunda-workers/pkg/registry"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateJobTypesError(t *testing.T) {
+	reg := &registry.ActivityRegistry{Activities: []registry.Activity{
+		{ID: "query-postgresql", TaskType: "query-postgresql", Aliases: []string{"query.data"}},
+		{ID: "query-elasticsearch", TaskType: "search", Aliases: []string{"query.data"}},
+		{ID: "search", TaskType: "search-franchises"},
+		{ID: "build-response", TaskType: "build-response"},
+	}}
+
+	err := duplicateJobTypesError(reg.DuplicateJobTypes())
+
+	assert.EqualError(t, err, "task types claimed by several activities:\n"+
+		"  query.data: query-postgresql, query-elasticsearch\n"+
+		"  search: query-elasticsearch, search")
+	assert.NoError(t, duplicateJobTypesError(nil))
+}
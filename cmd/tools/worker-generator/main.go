@@ -2,8 +2,10 @@
 package main
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,6 +16,7 @@ import (
 
 // WorkerData holds data for templates
 type WorkerData struct {
+	ID                   string                 `json:"id"`
 	Name                 string                 `json:"name"`
 	PackageName          string                 `json:"packageName"`
 	TaskType             string                 `json:"taskType"`
@@ -104,15 +107,10 @@ const handlerTemplate = `package {{ .PackageName }}
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"time"
 
 	"camunda-workers/internal/common/errors"
 	"camunda-workers/internal/common/logger"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const TaskType = "{{ .TaskType }}"
@@ -134,109 +132,21 @@ func NewHandler(config *Config, logger logger.Logger) *Handler {
 	}
 }
 
-// Handle processes a single Zeebe job for the {{ .Name }} task.
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	ctx := context.Background()
-	startTime := time.Now()
-
-	h.logger.Info("Processing job",
-		"taskType", TaskType,
-		"jobKey", job.Key,
-		"processInstanceKey", job.ProcessInstanceKey,
-	)
-
-	// Parse input variables
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "INVALID_INPUT", "Failed to parse input variables: " + err.Error())
-		return
-	}
-
-	// Validate input
+// Execute validates the decoded input and runs the business logic.
+// Variable decoding, timeouts, logging, metrics and job completion are
+// handled by the camunda.Pipeline the handler is registered with.
+func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	if err := input.Validate(); err != nil {
-		h.failJob(client, job, "VALIDATION_FAILED", "Input validation failed: " + err.Error())
-		return
-	}
-
-	// Execute business logic
-	output, err := h.service.Execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "EXECUTION_FAILED", "Service execution failed: " + err.Error())
-		return
-	}
-
-	// Complete job with output
-	h.completeJob(client, job, output)
-
-	duration := time.Since(startTime)
-	h.logger.Info("Job completed successfully",
-		"taskType", TaskType,
-		"jobKey", job.Key,
-		"duration", duration.Milliseconds(),
-	)
-}
-
-// completeJob sends a completion command to Zeebe with the output variables.
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	variables, err := json.Marshal(output)
-	if err != nil {
-		h.logger.Error("Failed to marshal output", "error", err)
-		h.failJob(client, job, "MARSHAL_ERROR", "Failed to marshal output: " + err.Error())
-		return
-	}
-
-	request := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromString(string(variables))
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	_, err = request.Send(ctx)
-	if err != nil {
-		h.logger.Error("Failed to complete job",
-			"jobKey", job.Key,
-			"error", err,
-		)
-		return
-	}
-
-	h.logger.Info("Job completed successfully",
-		"jobKey", job.Key,
-		"processInstanceKey", job.ProcessInstanceKey,
-	)
-}
-
-// failJob sends a failure command to Zeebe with error information.
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode string, errorMessage string) {
-	retries := int32(0)
-	
-	request := client.NewFailJobCommand().
-		JobKey(job.Key).
-		Retries(retries).
-		ErrorMessage(errorMessage)
-
-	if errorCode != "" {
-		request = request.ErrorCode(errorCode)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	_, sendErr := request.Send(ctx)
-	if sendErr != nil {
-		h.logger.Error("Failed to send fail command",
-			"jobKey", job.Key,
-			"error", sendErr,
-		)
+		return nil, &errors.StandardError{
+			Code:      "VALIDATION_FAILED",
+			Message:   "Input validation failed",
+			Details:   err.Error(),
+			Retryable: false,
+			Timestamp: time.Now().UTC(),
+		}
 	}
 
-	h.logger.Error("Job failed",
-		"jobKey", job.Key,
-		"errorCode", errorCode,
-		"errorMessage", errorMessage,
-		"retries", retries,
-	)
+	return h.service.Execute(ctx, input)
 }
 `
 
@@ -444,22 +354,11 @@ No specific error codes defined.
 
 ### Register in Worker Manager
 
-` + "```go" + `
-import "camunda-workers/internal/workers/{{ .Category }}/{{ .PackageName }}"
-
-// In registerWorkers function:
-handler := {{ .PackageName }}.NewHandler(
-    &config.Workers.{{ upperFirst .PackageName }},
-    logger,
-)
+The worker registers itself from register.go. Link the package into the
+worker manager by adding a blank import to internal/workers/all/all.go:
 
-client.NewJobWorker().
-    JobType({{ .PackageName }}.TaskType).
-    Handler(handler.Handle).
-    MaxJobsActive(config.Workers.{{ upperFirst .PackageName }}.MaxJobsActive).
-    Timeout(config.Workers.{{ upperFirst .PackageName }}.Timeout).
-    Name("{{ .Name }}-worker").
-    Open()
+` + "```go" + `
+_ "camunda-workers/internal/workers/{{ .Category }}/{{ .PackageName }}"
 ` + "```" + `
 
 ### Configuration in config.yaml
@@ -481,6 +380,27 @@ go test ./internal/workers/{{ .Category }}/{{ .PackageName }}/...
 ` + "```" + `
 `
 
+const registerTemplate = `package {{ .PackageName }}
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				Timeout: config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+			},
+			deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("EXECUTION_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
+`
+
 const validationTemplate = `package {{ .PackageName }}
 
 // Validate validates the input data.
@@ -496,16 +416,70 @@ func (i *Input) Validate() error {
 }
 `
 
+// contractTestTemplate is regenerated whenever the registry entry changes.
+const contractTestTemplate = `// Code generated by worker-generator -contract from the activity registry. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// TestContract_Schemas round-trips a sample input generated from the
+// {{ .ID }} inputSchema through the handler and checks the output
+// against its outputSchema.
+func TestContract_Schemas(t *testing.T) {
+	contract.RoundTrip(t, "{{ .ID }}", newContractFixture(t))
+}
+
+// TestContract_ErrorCodes checks that every errorCode {{ .ID }} declares is reachable.
+func TestContract_ErrorCodes(t *testing.T) {
+	contract.ErrorCodes(t, "{{ .ID }}", newContractFixture(t){{ range .ErrorCodes }}, "{{ . }}"{{ end }})
+}
+`
+
+// contractFixtureTemplate is written once and then maintained by hand.
+const contractFixtureTemplate = `package {{ .PackageName }}
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+	"camunda-workers/internal/common/logger"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return NewHandler(&Config{}, logger.NewTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+{{- range .ErrorCodes }}
+			// "{{ . }}": func(t *testing.T, ctx context.Context, input *Input) error { ... },
+{{- end }}
+		},
+		Fallback: "EXECUTION_FAILED",
+	}
+}
+`
+
 func main() {
 	activity := flag.String("activity", "", "Activity ID from registry (e.g., validate-subscription)")
 	outputDir := flag.String("output", "./internal/workers/", "Output directory for the generated worker")
 	registryPath := flag.String("registry", "configs/activity-registry.json", "Path to the activity registry JSON file")
+	contractOnly := flag.Bool("contract", false, "Only generate contract tests for an existing worker")
 	flag.Parse()
 
 	if *activity == "" {
-		fmt.Println("Usage: worker-generator --activity <id> --output <dir> [--registry <path>]")
+		fmt.Println("Usage: worker-generator --activity <id> --output <dir> [--registry <path>] [--contract]")
 		fmt.Println("\nExample:")
 		fmt.Println("  go run cmd/tools/worker-generator/main.go --activity validate-subscription")
+		fmt.Println("  go run cmd/tools/worker-generator/main.go --activity validate-subscription --contract")
 		os.Exit(1)
 	}
 
@@ -532,6 +506,7 @@ func main() {
 
 	// Prepare data for templates
 	data := WorkerData{
+		ID:                   foundActivity.ID,
 		Name:                 foundActivity.DisplayName,
 		PackageName:          strings.ReplaceAll(foundActivity.ID, "-", ""),
 		TaskType:             foundActivity.TaskType,
@@ -549,6 +524,20 @@ func main() {
 	categoryDir := mapCategoryToDirectory(data.Category)
 	workerDir := filepath.Join(*outputDir, categoryDir, foundActivity.ID)
 
+	if *contractOnly {
+		workerDir, err = findWorkerDir(*outputDir, workerDir, foundActivity.ID)
+		if err != nil {
+			fmt.Printf("No worker found for %s: %v\n", foundActivity.ID, err)
+			os.Exit(1)
+		}
+		if err := generateContractTests(workerDir, data); err != nil {
+			fmt.Printf("Error generating contract tests: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n✅ Contract tests generated at: %s\n", workerDir)
+		return
+	}
+
 	if err := os.MkdirAll(workerDir, 0755); err != nil {
 		fmt.Printf("Error creating directory: %v\n", err)
 		os.Exit(1)
@@ -574,6 +563,7 @@ func main() {
 		"service.go":      serviceTemplate,
 		"config.go":       configTemplate,
 		"models.go":       modelsTemplate,
+		"register.go":     registerTemplate,
 		"handler_test.go": testTemplate,
 		"validation.go":   validationTemplate,
 		"README.md":       readmeTemplate,
@@ -601,15 +591,79 @@ func main() {
 		fmt.Printf("✓ Generated %s\n", filePath)
 	}
 
+	if err := generateContractTests(workerDir, data); err != nil {
+		fmt.Printf("Error generating contract tests: %v\n", err)
+	}
+
 	fmt.Printf("\n✅ Worker scaffold generated successfully at: %s\n", workerDir)
 	fmt.Printf("\nNext steps:\n")
 	fmt.Printf("  1. Implement business logic in service.go\n")
 	fmt.Printf("  2. Add validation in validation.go\n")
-	fmt.Printf("  3. Write tests in handler_test.go\n")
-	fmt.Printf("  4. Register worker in cmd/worker-manager/main.go\n")
+	fmt.Printf("  3. Write tests in handler_test.go and error cases in contract_fixture_test.go\n")
+	fmt.Printf("  4. Import the package in internal/workers/all/all.go\n")
 	fmt.Printf("  5. Add configuration to configs/config.yaml\n")
 }
 
+// generateContractTests writes contract_test.go for the activity and, unless
+// it already exists, the contract_fixture_test.go skeleton it depends on.
+func generateContractTests(workerDir string, data WorkerData) error {
+	files := []struct {
+		name      string
+		tmpl      string
+		overwrite bool
+	}{
+		{"contract_test.go", contractTestTemplate, true},
+		{"contract_fixture_test.go", contractFixtureTemplate, false},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(workerDir, f.name)
+		if _, err := os.Stat(path); err == nil && !f.overwrite {
+			fmt.Printf("• Kept existing %s\n", path)
+			continue
+		}
+
+		tmpl, err := template.New(f.name).Parse(f.tmpl)
+		if err != nil {
+			return fmt.Errorf("parse template %s: %w", f.name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("execute template %s: %w", f.name, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("format %s: %w", f.name, err)
+		}
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Generated %s\n", path)
+	}
+	return nil
+}
+
+// findWorkerDir returns the directory of an existing worker: the one its
+// category maps to or, for workers placed elsewhere, the directory named
+// after the activity under outputDir.
+func findWorkerDir(outputDir, workerDir, activityID string) (string, error) {
+	if _, err := os.Stat(filepath.Join(workerDir, "handler.go")); err == nil {
+		return workerDir, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(outputDir, "*", activityID, "handler.go"))
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s/handler.go under %s", activityID, outputDir)
+	case 1:
+		return filepath.Dir(matches[0]), nil
+	default:
+		return "", fmt.Errorf("several workers named %s: %v", activityID, matches)
+	}
+}
+
 // mapCategoryToDirectory maps registry categories to directory names
 func mapCategoryToDirectory(category string) string {
 	switch category {
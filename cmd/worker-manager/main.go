@@ -3,63 +3,31 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"sort"
 	"syscall"
 	"time"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 
 	"camunda-workers/internal/common/auth"
+	"camunda-workers/internal/common/camunda"
 	"camunda-workers/internal/common/config"
 	"camunda-workers/internal/common/database"
+	"camunda-workers/internal/common/health"
 	"camunda-workers/internal/common/logger"
 	"camunda-workers/internal/common/observability"
 	"camunda-workers/internal/common/zoho"
+	"camunda-workers/pkg/bpmn"
+	"camunda-workers/pkg/registry"
 
-	// Infrastructure Workers (3)
-	br "camunda-workers/internal/workers/infrastructure/build-response"
-	st "camunda-workers/internal/workers/infrastructure/select-template"
-	vs "camunda-workers/internal/workers/infrastructure/validate-subscription"
-
-	// Data Access Workers (2)
-	qe "camunda-workers/internal/workers/data-access/query-elasticsearch"
-	qp "camunda-workers/internal/workers/data-access/query-postgresql"
-
-	// Business Logic Workers (5 from franchise + 5 from application = 10)
-	arr "camunda-workers/internal/workers/franchise/apply-relevance-ranking"
-	cms "camunda-workers/internal/workers/franchise/calculate-match-score"
-	psf "camunda-workers/internal/workers/franchise/parse-search-filters"
-
-	cpr "camunda-workers/internal/workers/application/check-priority-routing"
-	crs "camunda-workers/internal/workers/application/check-readiness-score"
-	car "camunda-workers/internal/workers/application/create-application-record"
-	sn "camunda-workers/internal/workers/application/send-notification"
-	vad "camunda-workers/internal/workers/application/validate-application-data"
-
-	// AI/ML Workers (4)
-	ews "camunda-workers/internal/workers/ai-conversation/enrich-web-search"
-	llm "camunda-workers/internal/workers/ai-conversation/llm-synthesis"
-	pui "camunda-workers/internal/workers/ai-conversation/parse-user-intent"
-	qid "camunda-workers/internal/workers/ai-conversation/query-internal-data"
-
-	// Authentication & Utility Workers (8)
-	alo "camunda-workers/internal/workers/auth/auth-logout"
-	asig "camunda-workers/internal/workers/auth/auth-signin-google"
-	asil "camunda-workers/internal/workers/auth/auth-signin-linkedin"
-	asug "camunda-workers/internal/workers/auth/auth-signup-google"
-	asul "camunda-workers/internal/workers/auth/auth-signup-linkedin"
-	cv "camunda-workers/internal/workers/auth/captcha-verify"
-	es "camunda-workers/internal/workers/communication/email-send"
-	cuc "camunda-workers/internal/workers/crm/crm-user-create"
+	// Registers every worker factory with the camunda worker registry
+	_ "camunda-workers/internal/workers/all"
 )
 
 // retryWithBackoff attempts to execute a function with exponential backoff
@@ -108,12 +76,14 @@ func main() {
 	ctx := context.Background()
 
 	// --- Init Zeebe Client with retry ---
-	var zeebeClient zbc.Client
+	var camundaClient *camunda.Client
 	err = retryWithBackoff(func() error {
 		var err error
-		zeebeClient, err = zbc.NewClient(&zbc.ClientConfig{
+		camundaClient, err = camunda.NewClientWithConfig(&camunda.ClientConfig{
 			GatewayAddress:         cfg.Camunda.BrokerAddress,
 			UsePlaintextConnection: true,
+			ConnectionTimeout:      10 * time.Second,
+			RequestTimeout:         config.GetDuration(cfg.Camunda.RequestTimeout),
 		})
 		return err
 	}, 10, 2*time.Second, zapLog, "Zeebe client initialization")
@@ -121,6 +91,7 @@ func main() {
 	if err != nil {
 		zapLog.Fatal("zeebe client failed after retries", zap.Error(err))
 	}
+	zeebeClient := camundaClient.GetClient()
 	zapLog.Info("Zeebe client connected successfully")
 
 	// --- Init PostgreSQL with retry ---
@@ -138,7 +109,6 @@ func main() {
 	if err != nil {
 		zapLog.Fatal("postgres failed after retries", zap.Error(err))
 	}
-	defer pg.Close()
 	zapLog.Info("PostgreSQL connected successfully")
 
 	// --- Init Elasticsearch with retry ---
@@ -173,329 +143,68 @@ func main() {
 	if err != nil {
 		zapLog.Fatal("redis failed after retries", zap.Error(err))
 	}
-	defer redis.Close()
 	zapLog.Info("Redis connected successfully")
 
 	// --- Init External Service Clients ---
-	_ = auth.NewKeycloakClient(
+	keycloakClient := auth.NewKeycloakClient(
 		cfg.Auth.Keycloak.URL,
 		cfg.Auth.Keycloak.Realm,
 		cfg.Auth.Keycloak.ClientID,
 		cfg.Auth.Keycloak.ClientSecret,
 	)
 
-	_ = zoho.NewCRMClient(cfg.Integrations.Zoho.APIKey, cfg.Integrations.Zoho.AuthToken)
+	zohoClient := zoho.NewCRMClient(cfg.Integrations.Zoho.APIKey, cfg.Integrations.Zoho.AuthToken)
 
 	zapLog.Info("All external service clients initialized")
 
-	// --- START: Register ALL 25 Workers ---
-
-	// --- 1. Infrastructure Workers (3) ---
-	if cfg.Workers[vs.TaskType].Enabled {
-		handler := vs.NewHandler(
-			&vs.Config{
-				Timeout: time.Duration(cfg.Workers[vs.TaskType].Timeout) * time.Millisecond,
-			},
-			pg.DB, redis.Client, log,
-		)
-		startWorker(zeebeClient, vs.TaskType, cfg.Workers[vs.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[br.TaskType].Enabled {
-		handler := br.NewHandler(
-			&br.Config{
-				TemplateRegistry: cfg.Template.RegistryPath,
-				AppVersion:       cfg.App.Version,
-			},
-			log,
-		)
-		startWorker(zeebeClient, br.TaskType, cfg.Workers[br.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[st.TaskType].Enabled {
-		handler := st.NewHandler(
-			&st.Config{
-				TemplateRules: map[string]map[string]string{
-					"route": cfg.Template.TemplateRules.Route,
-					"flow":  cfg.Template.TemplateRules.Flow,
-				},
-			},
-			log,
-		)
-		startWorker(zeebeClient, st.TaskType, cfg.Workers[st.TaskType], handler.Handle, zapLog)
-	}
-
-	// --- 2. Data Access Workers (2) ---
-	if cfg.Workers[qp.TaskType].Enabled {
-		handler := qp.NewHandler(
-			&qp.Config{
-				Timeout: time.Duration(cfg.Workers[qp.TaskType].Timeout) * time.Millisecond,
-			},
-			pg.DB, log,
-		)
-		startWorker(zeebeClient, qp.TaskType, cfg.Workers[qp.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[qe.TaskType].Enabled {
-		handler := qe.NewHandler(
-			&qe.Config{
-				Timeout: time.Duration(cfg.Workers[qe.TaskType].Timeout) * time.Millisecond,
-			},
-			esClient.Client, log,
-		)
-		startWorker(zeebeClient, qe.TaskType, cfg.Workers[qe.TaskType], handler.Handle, zapLog)
-	}
-
-	// --- 3. Business Logic Workers (5 + 5 = 10) ---
-	if cfg.Workers[psf.TaskType].Enabled {
-		handler := psf.NewHandler(&psf.Config{}, log)
-		startWorker(zeebeClient, psf.TaskType, cfg.Workers[psf.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[arr.TaskType].Enabled {
-		handler := arr.NewHandler(
-			&arr.Config{
-				MaxItems: 100,
-				Timeout:  time.Duration(cfg.Workers[arr.TaskType].Timeout) * time.Millisecond,
-			},
-			log,
-		)
-		startWorker(zeebeClient, arr.TaskType, cfg.Workers[arr.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[cms.TaskType].Enabled {
-		handler := cms.NewHandler(
-			&cms.Config{
-				CacheTTL: 10 * time.Minute,
-			},
-			pg.DB, redis.Client, log,
-		)
-		startWorker(zeebeClient, cms.TaskType, cfg.Workers[cms.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[vad.TaskType].Enabled {
-		handler := vad.NewHandler(&vad.Config{}, log)
-		startWorker(zeebeClient, vad.TaskType, cfg.Workers[vad.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[crs.TaskType].Enabled {
-		handler := crs.NewHandler(&crs.Config{}, log)
-		startWorker(zeebeClient, crs.TaskType, cfg.Workers[crs.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[cpr.TaskType].Enabled {
-		handler := cpr.NewHandler(
-			&cpr.Config{
-				CacheTTL: 30 * time.Minute,
-			},
-			pg.DB, redis.Client, log,
-		)
-		startWorker(zeebeClient, cpr.TaskType, cfg.Workers[cpr.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[car.TaskType].Enabled {
-		handler := car.NewHandler(&car.Config{}, pg.DB, log)
-		startWorker(zeebeClient, car.TaskType, cfg.Workers[car.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[sn.TaskType].Enabled {
-		handler, err := sn.NewHandler(
-			&sn.Config{
-				Timeout: time.Duration(cfg.Workers[sn.TaskType].Timeout) * time.Millisecond,
-			},
-			pg.DB, log,
-		)
-		if err != nil {
-			zapLog.Fatal("failed to create send-notification handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, sn.TaskType, cfg.Workers[sn.TaskType], handler.Handle, zapLog)
-	}
-
-	// --- 4. AI/ML Workers (4) ---
-	// Create adapters for AI workers
-	puiLogAdapter := &parseUserIntentLoggerAdapter{log}
-	qidLogAdapter := &queryInternalDataLoggerAdapter{log}
-	ewsLogAdapter := &enrichWebSearchLoggerAdapter{log}
-	llmLogAdapter := &llmSynthesisLoggerAdapter{log}
-
-	if cfg.Workers[pui.TaskType].Enabled {
-		handler := pui.NewHandler(
-			&pui.Config{
-				GenAIBaseURL: cfg.APIs.GenAI.BaseURL,
-				Timeout:      30 * time.Second,
-				MaxRetries:   2,
-			},
-			puiLogAdapter,
-		)
-		startWorker(zeebeClient, pui.TaskType, cfg.Workers[pui.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[qid.TaskType].Enabled {
-		handler := qid.NewHandler(
-			&qid.Config{
-				Timeout:    2 * time.Second,
-				CacheTTL:   5 * time.Minute,
-				MaxResults: 10,
-			},
-			pg.DB, esClient.Client, redis.Client, qidLogAdapter,
-		)
-		startWorker(zeebeClient, qid.TaskType, cfg.Workers[qid.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[ews.TaskType].Enabled {
-		handler := ews.NewHandler(
-			&ews.Config{
-				SearchAPIBaseURL: cfg.APIs.WebSearch.BaseURL,
-				SearchAPIKey:     cfg.APIs.WebSearch.APIKey,
-				SearchEngineID:   cfg.APIs.WebSearch.EngineID,
-				Timeout:          3 * time.Second,
-				MaxResults:       5,
-				MinRelevance:     0.5,
-			},
-			ewsLogAdapter,
-		)
-		startWorker(zeebeClient, ews.TaskType, cfg.Workers[ews.TaskType], handler.Handle, zapLog)
-	}
-
-	if cfg.Workers[llm.TaskType].Enabled {
-		handler := llm.NewHandler(
-			&llm.Config{
-				GenAIBaseURL: cfg.APIs.GenAI.BaseURL,
-				Timeout:      5 * time.Second,
-				MaxRetries:   1,
-				MaxTokens:    500,
-				Temperature:  0.7,
-			},
-			llmLogAdapter,
-		)
-		startWorker(zeebeClient, llm.TaskType, cfg.Workers[llm.TaskType], handler.Handle, zapLog)
-	}
-
-	// --- 5. Authentication & Utility Workers (8) ---
-
-	// Auth Signin Google
-	if taskType := "auth-signin-google"; cfg.Workers[taskType].Enabled {
-		handler, err := asig.NewHandler(asig.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create auth-signin-google handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
-
-	// Auth Signin LinkedIn
-	if taskType := "auth-signin-linkedin"; cfg.Workers[taskType].Enabled {
-		handler, err := asil.NewHandler(asil.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create auth-signin-linkedin handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
-
-	// Auth Signup Google
-	if taskType := "auth-signup-google"; cfg.Workers[taskType].Enabled {
-		handler, err := asug.NewHandler(asug.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create auth-signup-google handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
-
-	// Auth Signup LinkedIn
-	if taskType := "auth-signup-linkedin"; cfg.Workers[taskType].Enabled {
-		handler, err := asul.NewHandler(asul.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create auth-signup-linkedin handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
-
-	// Auth Logout
-	if taskType := "auth-logout"; cfg.Workers[taskType].Enabled {
-		handler, err := alo.NewHandler(alo.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create auth-logout handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
+	// --- Register Workers from the activity registry ---
+	activities, err := registry.LoadRegistry(cfg.Registry.Path)
+	if err != nil {
+		zapLog.Fatal("activity registry load failed", zap.String("path", cfg.Registry.Path), zap.Error(err))
 	}
 
-	// Captcha Verify
-	if taskType := "captcha-verify"; cfg.Workers[taskType].Enabled {
-		handler, err := cv.NewHandler(cv.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create captcha-verify handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
+	deps := &camunda.Dependencies{
+		Config:        cfg,
+		Camunda:       camundaClient,
+		Postgres:      pg,
+		Redis:         redis,
+		Elasticsearch: esClient,
+		Keycloak:      keycloakClient,
+		Zoho:          zohoClient,
+		Logger:        log,
+		Registry:      activities,
 	}
 
-	// CRM User Create
-	if taskType := "crm-user-create"; cfg.Workers[taskType].Enabled {
-		handler, err := cuc.NewHandler(cuc.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create crm-user-create handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
+	lifecycle := camunda.NewLifecycle(zeebeClient, zapLog)
+	lifecycle.OnShutdown("postgres", pg.Close)
+	lifecycle.OnShutdown("redis", redis.Close)
+	lifecycle.OnShutdown("zeebe", camundaClient.Close)
 
-	// Email Send
-	if taskType := "email-send"; cfg.Workers[taskType].Enabled {
-		handler, err := es.NewHandler(es.HandlerOptions{
-			AppConfig: cfg,
-			Camunda:   nil,
-			Logger:    log,
-		})
-		if err != nil {
-			zapLog.Fatal("failed to create email-send handler", zap.Error(err))
-		}
-		startWorker(zeebeClient, taskType, cfg.Workers[taskType], handler.Handle, zapLog)
-	}
-	zapLog.Info("All 25 workers registered successfully")
+	started := startRegisteredWorkers(lifecycle, deps, activities, zapLog)
+	zapLog.Info("Workers registered successfully",
+		zap.Int("started", started),
+		zap.Int("registered", len(camunda.RegisteredTaskTypes())),
+	)
+	warnUnhandledTaskTypes(cfg.Registry.BPMNDir, lifecycle, zapLog)
 
 	// --- Health & Metrics Server ---
+	checker := health.NewReadinessChecker(health.Clients{
+		Zeebe:    health.PingFunc(deps.Camunda.HealthCheck),
+		Postgres: deps.Postgres,
+		Redis:    deps.Redis,
+		Elasticsearch: health.PingFunc(func(ctx context.Context) error {
+			return deps.Elasticsearch.Ping()
+		}),
+	}, lifecycle)
 	go func() {
 		http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(map[string]string{
+			health.WriteJSON(w, http.StatusOK, map[string]string{
 				"status": "healthy",
 				"time":   time.Now().Format(time.RFC3339),
 			})
 		})
-		http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			json.NewEncoder(w).Encode(map[string]string{
-				"status": "ready",
-				"time":   time.Now().Format(time.RFC3339),
-			})
-		})
+		http.HandleFunc("/ready", checker.ReadyHandler())
+		http.HandleFunc("/health/workers", health.WorkersHandler(deps.Config, lifecycle, camunda.RegisteredTaskTypes()))
 		http.Handle("/metrics", promhttp.Handler())
 		zapLog.Info("Health/Metrics server listening on :8080")
 		if err := http.ListenAndServe(":8080", nil); err != nil {
@@ -508,70 +217,96 @@ func main() {
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 	<-sigCh
 
-	zapLog.Info("Shutdown signal received, stopping workers...")
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	drainTimeout := config.GetDuration(cfg.Camunda.DrainTimeout)
+	zapLog.Info("Shutdown signal received, draining workers...", zap.Duration("drainTimeout", drainTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 	defer cancel()
 
-	_ = shutdownCtx
-
-	if err := zeebeClient.Close(); err != nil {
-		zapLog.Error("Error closing Zeebe client", zap.Error(err))
-	}
+	lifecycle.Shutdown(shutdownCtx)
 
 	zapLog.Info("Worker manager stopped gracefully")
 }
 
-// Logger adapters for AI workers that have their own Logger interfaces
-type parseUserIntentLoggerAdapter struct {
-	logger.Logger
-}
+// startRegisteredWorkers starts every registered worker whose activity is listed
+// in the activity registry and enabled in the `workers:` config section. Each
+// worker is also subscribed to its activity's declared task type and aliases.
+func startRegisteredWorkers(lifecycle *camunda.Lifecycle, deps *camunda.Dependencies, activities *registry.ActivityRegistry, log *zap.Logger) int {
+	for name, ids := range activities.DuplicateJobTypes() {
+		log.Warn("task type claimed by several activities, jobs will be split between their workers",
+			zap.String("taskType", name),
+			zap.Strings("activities", ids),
+		)
+	}
 
-func (a *parseUserIntentLoggerAdapter) With(fields map[string]interface{}) pui.Logger {
-	return &parseUserIntentLoggerAdapter{a.Logger.With(fields)}
-}
+	listed := make(map[string]registry.Activity, len(activities.Activities))
+	for _, activity := range activities.Activities {
+		listed[activity.ID] = activity
+	}
 
-type queryInternalDataLoggerAdapter struct {
-	logger.Logger
-}
+	started := 0
+	for _, taskType := range camunda.RegisteredTaskTypes() {
+		activity, ok := listed[taskType]
+		if !ok {
+			log.Warn("worker not listed in activity registry, skipping", zap.String("taskType", taskType))
+			continue
+		}
+		if activity.ImplementationStatus == "planned" {
+			log.Info("activity not implemented yet, skipping", zap.String("taskType", taskType))
+			continue
+		}
 
-func (a *queryInternalDataLoggerAdapter) With(fields map[string]interface{}) qid.Logger {
-	return &queryInternalDataLoggerAdapter{a.Logger.With(fields)}
-}
+		wcfg := deps.Config.Workers[taskType]
+		if !wcfg.Enabled {
+			log.Info("worker disabled", zap.String("taskType", taskType))
+			continue
+		}
 
-type enrichWebSearchLoggerAdapter struct {
-	logger.Logger
-}
+		w, err := camunda.BuildWorker(taskType, deps)
+		if err != nil {
+			log.Fatal("failed to create worker", zap.String("taskType", taskType), zap.Error(err))
+		}
+		lifecycle.Start(w, wcfg, activity.JobTypes()...)
+		started++
+	}
 
-func (a *enrichWebSearchLoggerAdapter) With(fields map[string]interface{}) ews.Logger {
-	return &enrichWebSearchLoggerAdapter{a.Logger.With(fields)}
+	return started
 }
 
-type llmSynthesisLoggerAdapter struct {
-	logger.Logger
-}
+// warnUnhandledTaskTypes logs every task type used by the BPMN in dir that no
+// started worker subscribes to. Process instances reaching such a task wait
+// there until a worker for it is deployed.
+func warnUnhandledTaskTypes(dir string, lifecycle *camunda.Lifecycle, log *zap.Logger) {
+	usage, err := bpmn.ScanTaskTypes(dir)
+	if err != nil {
+		log.Warn("could not scan BPMN for task types", zap.String("dir", dir), zap.Error(err))
+		return
+	}
 
-func (a *llmSynthesisLoggerAdapter) With(fields map[string]interface{}) llm.Logger {
-	return &llmSynthesisLoggerAdapter{a.Logger.With(fields)}
-}
+	subscribed := make(map[string]bool)
+	for _, jobType := range lifecycle.JobTypes() {
+		subscribed[jobType] = true
+	}
 
-func startWorker(client zbc.Client, taskType string, wcfg config.WorkerConfig, handlerFunc func(worker.JobClient, entities.Job), log *zap.Logger) {
-	if !wcfg.Enabled {
-		log.Info("worker disabled", zap.String("taskType", taskType))
-		return
+	var unhandled []string
+	for taskType := range usage {
+		if !subscribed[taskType] {
+			unhandled = append(unhandled, taskType)
+		}
 	}
+	sort.Strings(unhandled)
 
-	client.NewJobWorker().
-		JobType(taskType).
-		Handler(handlerFunc).
-		MaxJobsActive(wcfg.MaxJobsActive).
-		Timeout(time.Duration(wcfg.Timeout) * time.Millisecond).
-		Open()
-
-	log.Info("worker started",
-		zap.String("taskType", taskType),
-		zap.Int("maxJobsActive", wcfg.MaxJobsActive),
-		zap.Int("timeout_ms", wcfg.Timeout),
-	)
+	for _, taskType := range unhandled {
+		log.Warn("BPMN task type has no running worker",
+			zap.String("taskType", taskType),
+			zap.Strings("files", usage[taskType]),
+		)
+	}
+	if len(unhandled) > 0 {
+		log.Warn("process instances will wait at tasks without a worker",
+			zap.Int("unhandledTaskTypes", len(unhandled)),
+			zap.Int("bpmnTaskTypes", len(usage)),
+		)
+	}
 }
 
 // // cmd/worker-manager/main.go
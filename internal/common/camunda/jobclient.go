@@ -0,0 +1,80 @@
+// internal/common/camunda/jobclient.go
+package camunda
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/commands"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// completionClient wraps a JobClient and reports every complete command the
+// broker accepted, so the lifecycle can tell when a worker last succeeded.
+type completionClient struct {
+	worker.JobClient
+	onComplete func()
+}
+
+func (c *completionClient) NewCompleteJobCommand() commands.CompleteJobCommandStep1 {
+	return &completeStep1{next: c.JobClient.NewCompleteJobCommand(), onComplete: c.onComplete}
+}
+
+type completeStep1 struct {
+	next       commands.CompleteJobCommandStep1
+	onComplete func()
+}
+
+func (s *completeStep1) JobKey(key int64) commands.CompleteJobCommandStep2 {
+	return &completeStep2{next: s.next.JobKey(key), onComplete: s.onComplete}
+}
+
+type completeStep2 struct {
+	next       commands.CompleteJobCommandStep2
+	onComplete func()
+}
+
+func (s *completeStep2) Send(ctx context.Context) (*pb.CompleteJobResponse, error) {
+	return (&completeDispatch{next: s.next, onComplete: s.onComplete}).Send(ctx)
+}
+
+func (s *completeStep2) VariablesFromString(v string) (commands.DispatchCompleteJobCommand, error) {
+	return s.wrap(s.next.VariablesFromString(v))
+}
+
+func (s *completeStep2) VariablesFromStringer(v fmt.Stringer) (commands.DispatchCompleteJobCommand, error) {
+	return s.wrap(s.next.VariablesFromStringer(v))
+}
+
+func (s *completeStep2) VariablesFromMap(v map[string]interface{}) (commands.DispatchCompleteJobCommand, error) {
+	return s.wrap(s.next.VariablesFromMap(v))
+}
+
+func (s *completeStep2) VariablesFromObject(v interface{}) (commands.DispatchCompleteJobCommand, error) {
+	return s.wrap(s.next.VariablesFromObject(v))
+}
+
+func (s *completeStep2) VariablesFromObjectIgnoreOmitempty(v interface{}) (commands.DispatchCompleteJobCommand, error) {
+	return s.wrap(s.next.VariablesFromObjectIgnoreOmitempty(v))
+}
+
+func (s *completeStep2) wrap(next commands.DispatchCompleteJobCommand, err error) (commands.DispatchCompleteJobCommand, error) {
+	if err != nil {
+		return next, err
+	}
+	return &completeDispatch{next: next, onComplete: s.onComplete}, nil
+}
+
+type completeDispatch struct {
+	next       commands.DispatchCompleteJobCommand
+	onComplete func()
+}
+
+func (d *completeDispatch) Send(ctx context.Context) (*pb.CompleteJobResponse, error) {
+	resp, err := d.next.Send(ctx)
+	if err == nil {
+		d.onComplete()
+	}
+	return resp, err
+}
@@ -0,0 +1,274 @@
+// internal/common/camunda/lifecycle.go
+package camunda
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/metrics"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
+	"go.uber.org/zap"
+)
+
+// Lifecycle owns every JobWorker opened by the worker manager and the
+// resources that must outlive them. On shutdown it stops job activation,
+// drains in-flight handlers up to a deadline and then releases resources
+// in the order they were registered.
+type Lifecycle struct {
+	client zbc.Client
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	workers  []*managedWorker
+	closers  []namedCloser
+	inFlight int64
+	stopping bool
+}
+
+// managedWorker pairs a registered Worker with the JobWorkers polling for it,
+// one per job type it subscribes to. slots holds one token per running job
+// so that all job types share the worker's MaxJobsActive; nil means no limit.
+type managedWorker struct {
+	worker      Worker
+	jobTypes    []string
+	jobWorkers  []worker.JobWorker
+	slots       chan struct{}
+	active      int64
+	lastSuccess int64 // unix nanoseconds, 0 until the first completed job
+}
+
+// WorkerStats is a point-in-time view of a started worker.
+type WorkerStats struct {
+	ActiveJobs  int64
+	LastSuccess time.Time
+}
+
+type namedCloser struct {
+	name  string
+	close func() error
+}
+
+// NewLifecycle creates a lifecycle manager that opens job workers on client.
+func NewLifecycle(client zbc.Client, logger *zap.Logger) *Lifecycle {
+	return &Lifecycle{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Start opens a JobWorker for w's task type and for each alias using its
+// `workers:` config entry, and keeps them so that they can be closed on
+// shutdown. Aliases let BPMN refer to the same activity under other names.
+// MaxJobsActive is one budget for the task type and its aliases: the job
+// types split it for activation and share it for running handlers.
+func (l *Lifecycle) Start(w Worker, wcfg config.WorkerConfig, aliases ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stopping {
+		l.logger.Warn("lifecycle is stopping, worker not started", zap.String("taskType", w.TaskType()))
+		return
+	}
+
+	mw := &managedWorker{worker: w, jobTypes: jobTypes(w.TaskType(), aliases)}
+	if wcfg.MaxJobsActive > 0 {
+		mw.slots = make(chan struct{}, wcfg.MaxJobsActive)
+	}
+	handler := l.track(mw)
+	perType := jobsPerType(wcfg.MaxJobsActive, len(mw.jobTypes))
+	for _, jobType := range mw.jobTypes {
+		mw.jobWorkers = append(mw.jobWorkers, l.client.NewJobWorker().
+			JobType(jobType).
+			Handler(handler).
+			MaxJobsActive(perType).
+			Timeout(config.GetDuration(wcfg.Timeout)).
+			Open())
+	}
+
+	l.workers = append(l.workers, mw)
+
+	l.logger.Info("worker started",
+		zap.String("taskType", w.TaskType()),
+		zap.Strings("jobTypes", mw.jobTypes),
+		zap.Int("maxJobsActive", wcfg.MaxJobsActive),
+		zap.Int("timeout_ms", wcfg.Timeout),
+	)
+}
+
+// OnShutdown registers a resource to close once all workers have drained.
+// Resources are closed in registration order.
+func (l *Lifecycle) OnShutdown(name string, closeFn func() error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closers = append(l.closers, namedCloser{name: name, close: closeFn})
+}
+
+// InFlight returns the number of jobs currently being handled.
+func (l *Lifecycle) InFlight() int64 {
+	return atomic.LoadInt64(&l.inFlight)
+}
+
+// Stopping reports whether Shutdown has been called.
+func (l *Lifecycle) Stopping() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stopping
+}
+
+// Workers returns the started workers keyed by task type.
+func (l *Lifecycle) Workers() map[string]Worker {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	workers := make(map[string]Worker, len(l.workers))
+	for _, mw := range l.workers {
+		workers[mw.worker.TaskType()] = mw.worker
+	}
+	return workers
+}
+
+// JobTypes returns every job type a started worker is subscribed to.
+func (l *Lifecycle) JobTypes() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var jobTypes []string
+	for _, mw := range l.workers {
+		jobTypes = append(jobTypes, mw.jobTypes...)
+	}
+	return jobTypes
+}
+
+// Stats returns active-job counts and last-success times keyed by task type.
+func (l *Lifecycle) Stats() map[string]WorkerStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make(map[string]WorkerStats, len(l.workers))
+	for _, mw := range l.workers {
+		ws := WorkerStats{ActiveJobs: atomic.LoadInt64(&mw.active)}
+		if ns := atomic.LoadInt64(&mw.lastSuccess); ns > 0 {
+			ws.LastSuccess = time.Unix(0, ns)
+		}
+		stats[mw.worker.TaskType()] = ws
+	}
+	return stats
+}
+
+// jobTypes returns taskType followed by its aliases, without duplicates.
+func jobTypes(taskType string, aliases []string) []string {
+	seen := map[string]bool{taskType: true}
+	names := []string{taskType}
+	for _, alias := range aliases {
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		names = append(names, alias)
+	}
+	return names
+}
+
+// jobsPerType splits maxJobsActive across n job types, giving each at
+// least one. A non-positive maxJobsActive leaves the client default.
+func jobsPerType(maxJobsActive, n int) int {
+	if maxJobsActive <= 0 || n <= 1 {
+		return maxJobsActive
+	}
+	return (maxJobsActive + n - 1) / n
+}
+
+// track wraps a worker's handler so in-flight jobs and completions are
+// recorded. Jobs activated beyond the worker's budget wait for a slot.
+func (l *Lifecycle) track(mw *managedWorker) worker.JobHandler {
+	taskType := mw.worker.TaskType()
+	return func(client worker.JobClient, job entities.Job) {
+		atomic.AddInt64(&l.inFlight, 1)
+		defer atomic.AddInt64(&l.inFlight, -1)
+		if mw.slots != nil {
+			mw.slots <- struct{}{}
+			defer func() { <-mw.slots }()
+		}
+
+		atomic.AddInt64(&mw.active, 1)
+		metrics.WorkerJobsActive.WithLabelValues(taskType).Inc()
+		defer func() {
+			metrics.WorkerJobsActive.WithLabelValues(taskType).Dec()
+			atomic.AddInt64(&mw.active, -1)
+		}()
+
+		mw.worker.Handle(&completionClient{
+			JobClient: client,
+			onComplete: func() {
+				atomic.StoreInt64(&mw.lastSuccess, time.Now().UnixNano())
+			},
+		}, job)
+	}
+}
+
+// Shutdown stops activating new jobs, waits for in-flight handlers until ctx
+// expires, then closes every worker and registered resource. Jobs still
+// running when the deadline passes are left to their Zeebe timeout.
+func (l *Lifecycle) Shutdown(ctx context.Context) {
+	l.mu.Lock()
+	l.stopping = true
+	workers := l.workers
+	closers := l.closers
+	l.mu.Unlock()
+
+	start := time.Now()
+	l.logger.Info("draining workers",
+		zap.Int("workers", len(workers)),
+		zap.Int64("inFlight", l.InFlight()),
+	)
+
+	// JobWorker.Close stops the poller and blocks until its handlers return,
+	// so close all of them concurrently and race the group against ctx.
+	var wg sync.WaitGroup
+	for _, mw := range workers {
+		for _, jw := range mw.jobWorkers {
+			wg.Add(1)
+			go func(jw worker.JobWorker) {
+				defer wg.Done()
+				jw.Close()
+			}(jw)
+		}
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		l.logger.Info("all workers drained", zap.Duration("elapsed", time.Since(start)))
+	case <-ctx.Done():
+		l.logger.Warn("drain deadline exceeded, abandoning in-flight jobs",
+			zap.Int64("inFlight", l.InFlight()),
+			zap.Duration("elapsed", time.Since(start)),
+		)
+	}
+
+	for _, mw := range workers {
+		if err := mw.worker.Close(); err != nil {
+			l.logger.Error("error closing worker", zap.String("taskType", mw.worker.TaskType()), zap.Error(err))
+		}
+	}
+
+	for _, c := range closers {
+		if err := c.close(); err != nil {
+			l.logger.Error("error closing resource", zap.String("resource", c.name), zap.Error(err))
+			continue
+		}
+		l.logger.Info("resource closed", zap.String("resource", c.name))
+	}
+}
@@ -0,0 +1,196 @@
+// internal/common/camunda/lifecycle_test.go
+package camunda_test
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+	"camunda-workers/internal/common/config"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// ==========================
+// Concurrency budget
+// ==========================
+
+func TestLifecycle_AliasesShareMaxJobsActive(t *testing.T) {
+	client := testkit.NewClient()
+	release := make(chan struct{})
+	var running, peak int64
+	w := camunda.NewHandlerWorker("error-handler", func(jc worker.JobClient, job entities.Job) {
+		n := atomic.AddInt64(&running, 1)
+		for p := atomic.LoadInt64(&peak); n > p && !atomic.CompareAndSwapInt64(&peak, p, n); p = atomic.LoadInt64(&peak) {
+		}
+		<-release
+		atomic.AddInt64(&running, -1)
+		testkit.Completes(nil)(jc, job)
+	})
+
+	aliases := []string{"handle-search-error", "handle-llm-error", "handle-crm-error"}
+	for _, jobType := range append([]string{"error-handler"}, aliases...) {
+		for i := 0; i < 5; i++ {
+			client.Queue(testkit.NewJob(jobType, nil))
+		}
+	}
+
+	lc := camunda.NewLifecycle(client, zap.NewNop())
+	lc.Start(w, config.WorkerConfig{MaxJobsActive: 2, Timeout: 1000}, aliases...)
+
+	require.Eventually(t, func() bool { return atomic.LoadInt64(&running) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(300 * time.Millisecond) // every poller has activated by now
+	assert.EqualValues(t, 2, atomic.LoadInt64(&running), "task type and aliases run at most MaxJobsActive jobs")
+	assert.EqualValues(t, 2, lc.Stats()["error-handler"].ActiveJobs)
+	for _, a := range client.Activations() {
+		assert.LessOrEqual(t, a.MaxJobs, int32(1), "MaxJobsActive is split across the %d job types", len(aliases)+1)
+	}
+
+	close(release)
+	require.Eventually(t, func() bool { return len(client.Commands()) == 20 }, 5*time.Second, 10*time.Millisecond)
+	lc.Shutdown(context.Background())
+	assert.EqualValues(t, 2, atomic.LoadInt64(&peak))
+	assert.Zero(t, lc.InFlight())
+}
+
+// ==========================
+// Shutdown
+// ==========================
+
+// events records what happened during a shutdown, in order.
+type events struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (e *events) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.list = append(e.list, event)
+}
+
+func (e *events) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.list...)
+}
+
+// blockingWorker completes its jobs once release is closed and records when
+// each one finishes.
+func blockingWorker(taskType string, release <-chan struct{}, log *events, opts ...camunda.WorkerOption) camunda.Worker {
+	return camunda.NewHandlerWorker(taskType, func(jc worker.JobClient, job entities.Job) {
+		<-release
+		testkit.Completes(nil)(jc, job)
+		log.add("job " + job.Type)
+	}, opts...)
+}
+
+// startBlocked starts w with one queued job and waits until its handler runs.
+func startBlocked(t *testing.T, w camunda.Worker) (*testkit.Client, *camunda.Lifecycle) {
+	t.Helper()
+	client := testkit.NewClient()
+	client.Queue(testkit.NewJob(w.TaskType(), nil))
+
+	lc := camunda.NewLifecycle(client, zap.NewNop())
+	lc.Start(w, config.WorkerConfig{MaxJobsActive: 1, Timeout: 1000})
+	require.Eventually(t, func() bool { return lc.InFlight() == 1 }, time.Second, 10*time.Millisecond)
+	return client, lc
+}
+
+func TestLifecycle_ShutdownDrainsInFlightJobs(t *testing.T) {
+	release := make(chan struct{})
+	log := &events{}
+	client, lc := startBlocked(t, blockingWorker("send-email", release, log))
+	lc.OnShutdown("smtp", func() error { log.add("close smtp"); return nil })
+
+	done := make(chan struct{})
+	go func() {
+		lc.Shutdown(context.Background())
+		close(done)
+	}()
+
+	require.Eventually(t, lc.Stopping, time.Second, 10*time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("Shutdown returned while a job was in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Empty(t, log.get(), "nothing is closed before the job finishes")
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return after the job finished")
+	}
+	assert.Equal(t, []string{"job send-email", "close smtp"}, log.get())
+	assert.Len(t, client.Commands(), 1, "the in-flight job completed")
+	assert.Zero(t, lc.InFlight())
+}
+
+func TestLifecycle_ShutdownHonoursDeadline(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	log := &events{}
+	client, lc := startBlocked(t, blockingWorker("send-email", release, log))
+	lc.OnShutdown("smtp", func() error { log.add("close smtp"); return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	lc.Shutdown(ctx)
+
+	assert.Less(t, time.Since(start), time.Second, "Shutdown stops waiting at the deadline")
+	assert.Equal(t, []string{"close smtp"}, log.get(), "resources are closed even if jobs are abandoned")
+	assert.EqualValues(t, 1, lc.InFlight(), "the abandoned job is still running")
+	assert.Empty(t, client.Commands())
+}
+
+func TestLifecycle_ShutdownClosesInRegistrationOrder(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	log := &events{}
+	w := blockingWorker("send-email", release, log, camunda.WithCloser(func() error {
+		log.add("close worker")
+		return nil
+	}))
+
+	lc := camunda.NewLifecycle(testkit.NewClient(), zap.NewNop())
+	lc.Start(w, config.WorkerConfig{MaxJobsActive: 1, Timeout: 1000})
+	lc.OnShutdown("redis", func() error { log.add("close redis"); return nil })
+	lc.OnShutdown("elasticsearch", func() error {
+		log.add("close elasticsearch")
+		return stderrors.New("connection reset")
+	})
+	lc.OnShutdown("postgres", func() error { log.add("close postgres"); return nil })
+
+	lc.Shutdown(context.Background())
+
+	assert.Equal(t, []string{
+		"close worker", "close redis", "close elasticsearch", "close postgres",
+	}, log.get(), "workers close first, then resources in order; an error does not stop the rest")
+}
+
+func TestLifecycle_StartAfterShutdownIsIgnored(t *testing.T) {
+	client := testkit.NewClient()
+	client.Queue(testkit.NewJob("send-email", nil))
+	lc := camunda.NewLifecycle(client, zap.NewNop())
+	lc.Shutdown(context.Background())
+
+	lc.Start(camunda.NewHandlerWorker("send-email", testkit.Completes(nil)), config.WorkerConfig{MaxJobsActive: 1, Timeout: 1000})
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, lc.Workers())
+	assert.Empty(t, lc.JobTypes())
+	assert.Empty(t, client.Activations(), "no JobWorker was opened")
+	assert.Equal(t, 1, client.Queued("send-email"))
+}
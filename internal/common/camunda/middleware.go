@@ -0,0 +1,356 @@
+// internal/common/camunda/middleware.go
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/metrics"
+	"camunda-workers/internal/common/usage"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "camunda-workers/camunda"
+
+// Job carries one activated job through the middleware chain.
+type Job struct {
+	entities.Job
+	Client   worker.JobClient
+	TaskType string
+	Logger   logger.Logger
+
+	// Output is completed as the job's variables once the chain returns nil.
+	Output interface{}
+}
+
+// JobFunc processes a single job.
+type JobFunc func(ctx context.Context, job *Job) error
+
+// Middleware decorates a JobFunc with cross-cutting behaviour.
+type Middleware func(next JobFunc) JobFunc
+
+// Chain wraps fn with the given middleware; the first middleware is outermost.
+func Chain(fn JobFunc, middleware ...Middleware) JobFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		fn = middleware[i](fn)
+	}
+	return fn
+}
+
+// Typed adapts business logic of the form func(ctx, *Input) (Output, error)
+// to a JobFunc. Job variables are decoded into a new Input and the returned
+// Output becomes the job's completion variables.
+func Typed[In any, Out any](fn func(ctx context.Context, input *In) (Out, error)) JobFunc {
+	return func(ctx context.Context, job *Job) error {
+		input := new(In)
+		if err := json.Unmarshal([]byte(job.Variables), input); err != nil {
+			return errors.NewInputParsingFailedError(err)
+		}
+
+		output, err := fn(ctx, input)
+		if err != nil {
+			return err
+		}
+		job.Output = output
+		return nil
+	}
+}
+
+// Logging attaches a job-scoped logger to the job and logs its start and outcome.
+func Logging(log logger.Logger) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			job.Logger = log.WithFields(map[string]interface{}{
+				"taskType":           job.TaskType,
+				"jobKey":             job.Key,
+				"processInstanceKey": job.ProcessInstanceKey,
+				"bpmnProcessId":      job.BpmnProcessId,
+				"elementId":          job.ElementId,
+				"retries":            job.Retries,
+			})
+			job.Logger.Info("processing job", nil)
+
+			start := time.Now()
+			err := next(ctx, job)
+			if err != nil {
+				job.Logger.Warn("job returned error", map[string]interface{}{
+					"error":       err.Error(),
+					"duration_ms": time.Since(start).Milliseconds(),
+				})
+				return err
+			}
+
+			job.Logger.Info("job processed", map[string]interface{}{
+				"duration_ms": time.Since(start).Milliseconds(),
+			})
+			return nil
+		}
+	}
+}
+
+// Tracing runs the job inside a consumer span named after its task type.
+func Tracing() Middleware {
+	tracer := otel.Tracer(tracerName)
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			ctx, span := tracer.Start(ctx, job.TaskType,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("zeebe.task_type", job.TaskType),
+					attribute.Int64("zeebe.job_key", job.Key),
+					attribute.Int64("zeebe.process_instance_key", job.ProcessInstanceKey),
+					attribute.String("zeebe.bpmn_process_id", job.BpmnProcessId),
+					attribute.String("zeebe.element_id", job.ElementId),
+				),
+			)
+			defer span.End()
+
+			err := next(ctx, job)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+}
+
+// Metrics records job duration and completed/failed counters per task type.
+func Metrics() Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			start := time.Now()
+			err := next(ctx, job)
+			metrics.WorkerJobDuration.WithLabelValues(job.TaskType).Observe(time.Since(start).Seconds())
+
+			if err != nil {
+				metrics.WorkerJobsFailed.WithLabelValues(job.TaskType, errorCode(err)).Inc()
+				return err
+			}
+			metrics.WorkerJobsCompleted.WithLabelValues(job.TaskType).Inc()
+			return nil
+		}
+	}
+}
+
+// UsageScope puts the user, conversation and process instance of the job in
+// ctx, so that the AI calls the handler makes are recorded under them.
+func UsageScope() Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			var vars struct {
+				UserID         string `json:"userId"`
+				ConversationID string `json:"conversationId"`
+			}
+			// Variables that do not decode are left to the handler to report.
+			_ = json.Unmarshal([]byte(job.Variables), &vars)
+			ctx = usage.WithScope(ctx, usage.Scope{
+				UserID:             vars.UserID,
+				ConversationID:     vars.ConversationID,
+				ProcessInstanceKey: job.ProcessInstanceKey,
+				TaskType:           job.TaskType,
+			})
+			return next(ctx, job)
+		}
+	}
+}
+
+// Recover turns a panic in the handler into an INTERNAL_ERROR so the job is
+// reported to the broker instead of crashing the worker process.
+func Recover() Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					if job.Logger != nil {
+						job.Logger.Error("handler panicked", map[string]interface{}{
+							"panic": fmt.Sprint(r),
+							"stack": string(debug.Stack()),
+						})
+					}
+					err = errors.NewInternalError(fmt.Sprintf("panic: %v", r))
+				}
+			}()
+			return next(ctx, job)
+		}
+	}
+}
+
+// Timeout bounds the handler with the given duration. A handler that gives up
+// because the deadline passed is reported as a retryable TIMEOUT_ERROR.
+func Timeout(d time.Duration) Middleware {
+	return func(next JobFunc) JobFunc {
+		if d <= 0 {
+			return next
+		}
+		return func(ctx context.Context, job *Job) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			err := next(ctx, job)
+			if err != nil && stderrors.Is(ctx.Err(), context.DeadlineExceeded) && asStandardError(err) == nil {
+				return errors.NewTimeoutError(job.TaskType, err)
+			}
+			return err
+		}
+	}
+}
+
+// ErrorMapping converts a worker's sentinel error into a StandardError code.
+type ErrorMapping struct {
+	Err       error
+	Code      errors.ErrorCode
+	Retryable bool
+}
+
+// MapErrors converts errors returned by the handler into StandardErrors.
+// StandardErrors pass through unchanged, errors matching a mapping (errors.Is)
+// get its code, and anything else becomes a non-retryable fallback error.
+// An unmapped error returned after the job's deadline passed is reported as
+// a retryable TIMEOUT_ERROR instead, as Timeout would, since MapErrors runs
+// inside it and would otherwise hide the timeout behind the fallback.
+func MapErrors(fallback errors.ErrorCode, mappings ...ErrorMapping) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			err := next(ctx, job)
+			if err == nil || asStandardError(err) != nil {
+				return err
+			}
+
+			for _, m := range mappings {
+				if stderrors.Is(err, m.Err) {
+					return mappedError(m.Code, m.Retryable, err)
+				}
+			}
+			if stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.NewTimeoutError(job.TaskType, err)
+			}
+			return mappedError(fallback, false, err)
+		}
+	}
+}
+
+func mappedError(code errors.ErrorCode, retryable bool, err error) *errors.StandardError {
+	return &errors.StandardError{
+		Code:      code,
+		Message:   err.Error(),
+		Details:   err.Error(),
+		Retryable: retryable,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Pipeline turns business logic into a job handler with the standard
+// middleware stack: logging, tracing, metrics, panic recovery and a per-task
+// timeout. Errors are routed through errors.ErrorHandler, which fails
+// transient errors with a retry backoff and throws business errors as BPMN
+// errors.
+type Pipeline struct {
+	taskType   string
+	logger     logger.Logger
+	errors     *errors.ErrorHandler
+	middleware []Middleware
+}
+
+// NewPipeline builds the standard pipeline for a task type from its
+// `workers:` config entry. Extra middleware runs inside the timeout, closest
+// to the business logic.
+func NewPipeline(taskType string, wcfg config.WorkerConfig, log logger.Logger, extra ...Middleware) *Pipeline {
+	middleware := []Middleware{
+		Logging(log),
+		Tracing(),
+		Metrics(),
+		UsageScope(),
+		Recover(),
+		Timeout(config.GetDuration(wcfg.Timeout)),
+	}
+
+	policy := errors.DefaultRetryPolicy
+	if wcfg.MaxRetries > 0 {
+		policy.MaxRetries = wcfg.MaxRetries
+	}
+	if wcfg.RetryBackoff > 0 {
+		policy.BaseDelay = config.GetDuration(wcfg.RetryBackoff)
+	}
+
+	return &Pipeline{
+		taskType:   taskType,
+		logger:     log,
+		errors:     errors.NewErrorHandlerWithPolicy(log, policy),
+		middleware: append(middleware, extra...),
+	}
+}
+
+// Handler returns a HandlerFunc that runs fn through the pipeline and then
+// completes the job or reports its error.
+func (p *Pipeline) Handler(fn JobFunc) HandlerFunc {
+	chain := Chain(fn, p.middleware...)
+	return func(client worker.JobClient, job entities.Job) {
+		j := &Job{
+			Job:      job,
+			Client:   client,
+			TaskType: p.taskType,
+			Logger:   p.logger,
+		}
+
+		if err := chain(context.Background(), j); err != nil {
+			p.errors.HandleJobError(context.Background(), client, job, err)
+			return
+		}
+		p.complete(j)
+	}
+}
+
+func (p *Pipeline) complete(job *Job) {
+	step := job.Client.NewCompleteJobCommand().JobKey(job.Key)
+
+	var err error
+	if job.Output == nil {
+		_, err = step.Send(context.Background())
+	} else {
+		cmd, cmdErr := step.VariablesFromObject(job.Output)
+		if cmdErr != nil {
+			job.Logger.Error("failed to create complete job command", map[string]interface{}{
+				"error": cmdErr.Error(),
+			})
+			p.errors.HandleJobError(context.Background(), job.Client, job.Job, errors.NewInternalError(cmdErr.Error()))
+			return
+		}
+		_, err = cmd.Send(context.Background())
+	}
+
+	if err != nil {
+		job.Logger.Error("failed to send complete job command", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// asStandardError returns the StandardError in err's chain, if any.
+func asStandardError(err error) *errors.StandardError {
+	var stdErr *errors.StandardError
+	if stderrors.As(err, &stdErr) {
+		return stdErr
+	}
+	return nil
+}
+
+func errorCode(err error) string {
+	if stdErr := asStandardError(err); stdErr != nil {
+		return string(stdErr.Code)
+	}
+	return "UNKNOWN_ERROR"
+}
@@ -0,0 +1,294 @@
+// internal/common/camunda/middleware_test.go
+package camunda_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/validation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTaskType = "test-task"
+
+// run passes a fresh job through fn wrapped in middleware and returns the
+// chain's error.
+func run(fn camunda.JobFunc, middleware ...camunda.Middleware) error {
+	job := &camunda.Job{Job: testkit.NewJob(testTaskType, nil), TaskType: testTaskType}
+	return camunda.Chain(fn, middleware...)(context.Background(), job)
+}
+
+// requireStandardError asserts that err carries a StandardError and returns it.
+func requireStandardError(t *testing.T, err error) *errors.StandardError {
+	t.Helper()
+	var stdErr *errors.StandardError
+	require.True(t, stderrors.As(err, &stdErr), "expected a StandardError, got %v", err)
+	return stdErr
+}
+
+// waitForDeadline blocks until ctx is done and returns its error wrapped the
+// way handlers usually report it.
+func waitForDeadline(ctx context.Context, job *camunda.Job) error {
+	<-ctx.Done()
+	return fmt.Errorf("query franchises: %w", ctx.Err())
+}
+
+func TestRecover(t *testing.T) {
+	err := run(func(ctx context.Context, job *camunda.Job) error {
+		var scores map[string]int
+		scores["franchise-1"] = 1 // assignment to a nil map panics
+		return nil
+	}, camunda.Recover())
+
+	stdErr := requireStandardError(t, err)
+	assert.Equal(t, errors.ErrorCode("INTERNAL_ERROR"), stdErr.Code)
+	assert.False(t, stdErr.Retryable)
+	assert.Contains(t, stdErr.Details, "panic: assignment to entry in nil map")
+}
+
+func TestPipeline_PanicFailsJob(t *testing.T) {
+	client := testkit.NewJobClient()
+	job := testkit.NewJob(testTaskType, nil)
+
+	handler := camunda.NewPipeline(testTaskType, config.WorkerConfig{}, logger.NewTestLogger(t)).
+		Handler(func(ctx context.Context, job *camunda.Job) error {
+			panic("boom")
+		})
+	require.NotPanics(t, func() { handler(client, job) })
+
+	cmds := client.ForJob(job.Key)
+	require.Len(t, cmds, 1)
+	assert.Equal(t, testkit.ThrowError, cmds[0].Kind, "a non-retryable internal error fails the job without retries")
+	assert.Equal(t, "INTERNAL_ERROR", cmds[0].Variables["originalErrorCode"])
+}
+
+func TestTimeout(t *testing.T) {
+	handlerErr := stderrors.New("upstream unavailable")
+	businessErr := errors.NewInternalError("already classified")
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		fn        camunda.JobFunc
+		expectErr error
+		timedOut  bool
+	}{
+		{
+			name:     "deadline maps to the timeout code",
+			timeout:  20 * time.Millisecond,
+			fn:       waitForDeadline,
+			timedOut: true,
+		},
+		{
+			name:    "errors before the deadline pass through",
+			timeout: time.Second,
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				return handlerErr
+			},
+			expectErr: handlerErr,
+		},
+		{
+			name:    "standard errors after the deadline keep their code",
+			timeout: 20 * time.Millisecond,
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				<-ctx.Done()
+				return businessErr
+			},
+			expectErr: businessErr,
+		},
+		{
+			name:    "success after the deadline is not an error",
+			timeout: 20 * time.Millisecond,
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				<-ctx.Done()
+				return nil
+			},
+		},
+		{
+			name:    "no timeout leaves the context unbounded",
+			timeout: 0,
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				if _, ok := ctx.Deadline(); ok {
+					return stderrors.New("unexpected deadline")
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(tt.fn, camunda.Timeout(tt.timeout))
+
+			switch {
+			case tt.timedOut:
+				stdErr := requireStandardError(t, err)
+				assert.Equal(t, errors.ErrorCode("TIMEOUT_ERROR"), stdErr.Code)
+				assert.True(t, stdErr.Retryable)
+				assert.Contains(t, stdErr.Details, context.DeadlineExceeded.Error())
+			case tt.expectErr != nil:
+				assert.Same(t, tt.expectErr, err)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPipeline_DeadlineRetriesJob(t *testing.T) {
+	errQueryTimeout := stderrors.New("query timed out")
+
+	tests := []struct {
+		name          string
+		extra         []camunda.Middleware
+		fn            camunda.JobFunc
+		expectedKind  testkit.CommandKind
+		expectedError string
+	}{
+		{
+			name:          "without error mapping",
+			fn:            waitForDeadline,
+			expectedKind:  testkit.Fail,
+			expectedError: "[TIMEOUT_ERROR]",
+		},
+		{
+			name:  "raw context error behind a fallback mapping",
+			extra: []camunda.Middleware{camunda.MapErrors("MATCH_SCORE_FAILED")},
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			expectedKind:  testkit.Fail,
+			expectedError: "[TIMEOUT_ERROR]",
+		},
+		{
+			name:          "wrapped context error behind schema validation and mapping",
+			extra:         []camunda.Middleware{camunda.SchemaValidation(nil, nil, validation.ModeOff, validation.ModeOff), camunda.MapErrors("RANKING_FAILED")},
+			fn:            waitForDeadline,
+			expectedKind:  testkit.Fail,
+			expectedError: "[TIMEOUT_ERROR]",
+		},
+		{
+			name: "worker timeout sentinel keeps its code",
+			extra: []camunda.Middleware{camunda.MapErrors("QUERY_EXECUTION_FAILED",
+				camunda.ErrorMapping{Err: errQueryTimeout, Code: "QUERY_TIMEOUT", Retryable: true})},
+			fn: func(ctx context.Context, job *camunda.Job) error {
+				<-ctx.Done()
+				return fmt.Errorf("%w: %v", errQueryTimeout, ctx.Err())
+			},
+			expectedKind:  testkit.Fail,
+			expectedError: "[QUERY_TIMEOUT]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := testkit.NewJobClient()
+			job := testkit.NewJob(testTaskType, nil)
+
+			handler := camunda.NewPipeline(testTaskType, config.WorkerConfig{Timeout: 20}, logger.NewTestLogger(t), tt.extra...).
+				Handler(tt.fn)
+			handler(client, job)
+
+			cmds := client.ForJob(job.Key)
+			require.Len(t, cmds, 1)
+			assert.Equal(t, tt.expectedKind, cmds[0].Kind, "a timeout is retried")
+			assert.Greater(t, cmds[0].Retries, int32(0))
+			assert.Contains(t, cmds[0].ErrorMessage, tt.expectedError)
+		})
+	}
+}
+
+func TestMapErrors(t *testing.T) {
+	errNotFound := stderrors.New("franchise not found")
+	errUnavailable := stderrors.New("search unavailable")
+	mappings := []camunda.ErrorMapping{
+		{Err: errNotFound, Code: "FRANCHISE_NOT_FOUND", Retryable: false},
+		{Err: errUnavailable, Code: "SEARCH_UNAVAILABLE", Retryable: true},
+	}
+	passthrough := errors.NewTimeoutError(testTaskType, stderrors.New("slow"))
+
+	tests := []struct {
+		name              string
+		err               error
+		expectedCode      errors.ErrorCode
+		expectedRetryable bool
+	}{
+		{
+			name:         "sentinel",
+			err:          errNotFound,
+			expectedCode: "FRANCHISE_NOT_FOUND",
+		},
+		{
+			name:              "wrapped retryable sentinel",
+			err:               fmt.Errorf("search franchises: %w", errUnavailable),
+			expectedCode:      "SEARCH_UNAVAILABLE",
+			expectedRetryable: true,
+		},
+		{
+			name:         "doubly wrapped sentinel",
+			err:          fmt.Errorf("load detail: %w", fmt.Errorf("franchise-1: %w", errNotFound)),
+			expectedCode: "FRANCHISE_NOT_FOUND",
+		},
+		{
+			name:         "joined sentinels take the first mapping",
+			err:          stderrors.Join(errUnavailable, errNotFound),
+			expectedCode: "FRANCHISE_NOT_FOUND",
+		},
+		{
+			name:         "unmapped error falls back",
+			err:          stderrors.New("something else"),
+			expectedCode: "UNKNOWN_ERROR",
+		},
+		{
+			name:              "standard error passes through",
+			err:               fmt.Errorf("search: %w", passthrough),
+			expectedCode:      "TIMEOUT_ERROR",
+			expectedRetryable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(func(ctx context.Context, job *camunda.Job) error {
+				return tt.err
+			}, camunda.MapErrors("UNKNOWN_ERROR", mappings...))
+
+			stdErr := requireStandardError(t, err)
+			assert.Equal(t, tt.expectedCode, stdErr.Code)
+			assert.Equal(t, tt.expectedRetryable, stdErr.Retryable)
+		})
+	}
+
+	t.Run("unmapped error after the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+
+		job := &camunda.Job{Job: testkit.NewJob(testTaskType, nil), TaskType: testTaskType}
+		err := camunda.MapErrors("UNKNOWN_ERROR", mappings...)(func(ctx context.Context, job *camunda.Job) error {
+			return ctx.Err()
+		})(ctx, job)
+
+		stdErr := requireStandardError(t, err)
+		assert.Equal(t, errors.ErrorCode("TIMEOUT_ERROR"), stdErr.Code)
+		assert.True(t, stdErr.Retryable)
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		err := run(func(ctx context.Context, job *camunda.Job) error {
+			return nil
+		}, camunda.MapErrors("UNKNOWN_ERROR", mappings...))
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,229 @@
+// internal/common/camunda/registry.go
+package camunda
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"camunda-workers/internal/common/auth"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/database"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/usage"
+	"camunda-workers/internal/common/validation"
+	"camunda-workers/internal/common/zoho"
+	"camunda-workers/pkg/registry"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// Worker is the contract every job worker exposes to the worker manager.
+type Worker interface {
+	TaskType() string
+	Handle(client worker.JobClient, job entities.Job)
+	HealthCheck(ctx context.Context) error
+	Close() error
+}
+
+// Dependencies is the shared container handed to every worker factory.
+// Clients are created once by the worker manager and reused by all workers.
+type Dependencies struct {
+	Config        *config.Config
+	Camunda       *Client
+	Postgres      *database.PostgresClient
+	Redis         *database.RedisClient
+	Elasticsearch *database.ElasticsearchClient
+	Keycloak      *auth.KeycloakClient
+	Zoho          *zoho.CRMClient
+	Logger        logger.Logger
+
+	// Registry supplies the input/output schemas enforced by Pipeline.
+	// Without it jobs are not schema-validated.
+	Registry *registry.ActivityRegistry
+}
+
+// WorkerConfig returns the `workers:` config entry for the given task type.
+func (d *Dependencies) WorkerConfig(taskType string) config.WorkerConfig {
+	return config.GetWorkerConfig(d.Config, taskType)
+}
+
+// Usage returns the meter of the `usage:` config, nil when usage is
+// disabled. Calls are written to ai_usage when Postgres is connected.
+func (d *Dependencies) Usage() *usage.Meter {
+	var db *sql.DB
+	if d.Postgres != nil {
+		db = d.Postgres.DB
+	}
+	return usage.New(d.Config.Usage, db, d.Logger)
+}
+
+// Pipeline builds the standard job pipeline for a task type from its
+// `workers:` config entry. When the task type's activity is in the registry,
+// job variables and output are validated against its schemas first.
+func (d *Dependencies) Pipeline(taskType string, extra ...Middleware) *Pipeline {
+	if mw := d.schemaValidation(taskType); mw != nil {
+		extra = append([]Middleware{mw}, extra...)
+	}
+	return NewPipeline(taskType, d.WorkerConfig(taskType), d.Logger, extra...)
+}
+
+func (d *Dependencies) schemaValidation(taskType string) Middleware {
+	if d.Registry == nil {
+		return nil
+	}
+	activity, ok := d.Registry.Resolve(taskType)
+	if !ok {
+		return nil
+	}
+
+	input, err := validation.SchemaFromMap(activity.InputSchema)
+	if err != nil {
+		d.Logger.Warn("invalid inputSchema in activity registry, input not validated", map[string]interface{}{
+			"taskType": taskType,
+			"error":    err.Error(),
+		})
+		input = nil
+	}
+	output, err := validation.SchemaFromMap(activity.OutputSchema)
+	if err != nil {
+		d.Logger.Warn("invalid outputSchema in activity registry, output not validated", map[string]interface{}{
+			"taskType": taskType,
+			"error":    err.Error(),
+		})
+		output = nil
+	}
+	if input == nil && output == nil {
+		return nil
+	}
+
+	return SchemaValidation(input, output,
+		validation.Mode(d.Config.Registry.InputValidation),
+		validation.Mode(d.Config.Registry.OutputValidation),
+	)
+}
+
+// Factory builds a Worker from the shared dependencies.
+type Factory func(deps *Dependencies) (Worker, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// RegisterWorker makes a worker factory available under the given task type.
+// Worker packages call it from init(); registering the same task type twice panics.
+func RegisterWorker(taskType string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("camunda: RegisterWorker factory is nil for " + taskType)
+	}
+	if _, dup := factories[taskType]; dup {
+		panic("camunda: RegisterWorker called twice for " + taskType)
+	}
+	factories[taskType] = factory
+}
+
+// LookupWorker returns the factory registered for a task type.
+func LookupWorker(taskType string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	factory, ok := factories[taskType]
+	return factory, ok
+}
+
+// RegisteredTaskTypes returns all registered task types in sorted order.
+func RegisteredTaskTypes() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	taskTypes := make([]string, 0, len(factories))
+	for taskType := range factories {
+		taskTypes = append(taskTypes, taskType)
+	}
+	sort.Strings(taskTypes)
+	return taskTypes
+}
+
+// BuildWorker looks up the factory for a task type and builds the worker.
+func BuildWorker(taskType string, deps *Dependencies) (Worker, error) {
+	factory, ok := LookupWorker(taskType)
+	if !ok {
+		return nil, fmt.Errorf("no worker registered for task type %q", taskType)
+	}
+
+	w, err := factory(deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s worker: %w", taskType, err)
+	}
+	return w, nil
+}
+
+// HandlerFunc is the job handling signature shared by all worker handlers.
+type HandlerFunc func(client worker.JobClient, job entities.Job)
+
+// WorkerOption customises a worker built by NewHandlerWorker.
+type WorkerOption func(*handlerWorker)
+
+// WithHealthCheck sets the health check used by the worker.
+func WithHealthCheck(check func(ctx context.Context) error) WorkerOption {
+	return func(w *handlerWorker) {
+		w.healthCheck = check
+	}
+}
+
+// WithCloser sets the function called when the worker is closed.
+func WithCloser(closer func() error) WorkerOption {
+	return func(w *handlerWorker) {
+		w.closer = closer
+	}
+}
+
+// handlerWorker adapts a plain Handle method to the Worker interface.
+type handlerWorker struct {
+	taskType    string
+	handle      HandlerFunc
+	healthCheck func(ctx context.Context) error
+	closer      func() error
+}
+
+// NewHandlerWorker wraps a handler's Handle method as a Worker.
+// Health checks and Close are no-ops unless provided through options.
+func NewHandlerWorker(taskType string, handle HandlerFunc, opts ...WorkerOption) Worker {
+	w := &handlerWorker{
+		taskType: taskType,
+		handle:   handle,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func (w *handlerWorker) TaskType() string {
+	return w.taskType
+}
+
+func (w *handlerWorker) Handle(client worker.JobClient, job entities.Job) {
+	w.handle(client, job)
+}
+
+func (w *handlerWorker) HealthCheck(ctx context.Context) error {
+	if w.healthCheck == nil {
+		return nil
+	}
+	return w.healthCheck(ctx)
+}
+
+func (w *handlerWorker) Close() error {
+	if w.closer == nil {
+		return nil
+	}
+	return w.closer()
+}
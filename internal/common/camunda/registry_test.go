@@ -0,0 +1,110 @@
+// internal/common/camunda/registry_test.go
+package camunda_test
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The factory registry is process-wide, so every test registers task types
+// of its own under the "registry-test-" prefix.
+
+// ==========================
+// RegisterWorker
+// ==========================
+
+func TestRegisterWorker_MakesFactoryAvailable(t *testing.T) {
+	const taskType = "registry-test-build"
+	var got *camunda.Dependencies
+	camunda.RegisterWorker(taskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		got = deps
+		return camunda.NewHandlerWorker(taskType, testkit.Completes(nil)), nil
+	})
+
+	_, ok := camunda.LookupWorker(taskType)
+	assert.True(t, ok)
+	assert.Contains(t, camunda.RegisteredTaskTypes(), taskType)
+	assert.IsIncreasing(t, camunda.RegisteredTaskTypes())
+
+	deps := &camunda.Dependencies{}
+	w, err := camunda.BuildWorker(taskType, deps)
+	require.NoError(t, err)
+	assert.Equal(t, taskType, w.TaskType())
+	assert.Same(t, deps, got, "the factory receives the shared dependencies")
+}
+
+func TestRegisterWorker_DuplicatePanics(t *testing.T) {
+	const taskType = "registry-test-duplicate"
+	factory := func(*camunda.Dependencies) (camunda.Worker, error) { return nil, nil }
+	camunda.RegisterWorker(taskType, factory)
+
+	assert.PanicsWithValue(t, "camunda: RegisterWorker called twice for "+taskType, func() {
+		camunda.RegisterWorker(taskType, factory)
+	})
+}
+
+func TestRegisterWorker_NilFactoryPanics(t *testing.T) {
+	const taskType = "registry-test-nil"
+	assert.PanicsWithValue(t, "camunda: RegisterWorker factory is nil for "+taskType, func() {
+		camunda.RegisterWorker(taskType, nil)
+	})
+	_, ok := camunda.LookupWorker(taskType)
+	assert.False(t, ok)
+}
+
+// ==========================
+// BuildWorker
+// ==========================
+
+func TestBuildWorker_UnknownTaskType(t *testing.T) {
+	_, ok := camunda.LookupWorker("registry-test-unknown")
+	assert.False(t, ok)
+
+	w, err := camunda.BuildWorker("registry-test-unknown", &camunda.Dependencies{})
+	assert.Nil(t, w)
+	assert.EqualError(t, err, `no worker registered for task type "registry-test-unknown"`)
+}
+
+func TestBuildWorker_FactoryError(t *testing.T) {
+	const taskType = "registry-test-failing"
+	errNoSMTP := stderrors.New("smtp host not configured")
+	camunda.RegisterWorker(taskType, func(*camunda.Dependencies) (camunda.Worker, error) {
+		return nil, errNoSMTP
+	})
+
+	w, err := camunda.BuildWorker(taskType, &camunda.Dependencies{})
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, errNoSMTP)
+	assert.EqualError(t, err, "failed to create registry-test-failing worker: smtp host not configured")
+}
+
+// ==========================
+// NewHandlerWorker
+// ==========================
+
+func TestNewHandlerWorker_Options(t *testing.T) {
+	plain := camunda.NewHandlerWorker("registry-test-plain", testkit.Completes(nil))
+	assert.NoError(t, plain.HealthCheck(context.Background()))
+	assert.NoError(t, plain.Close())
+
+	errDown := stderrors.New("down")
+	closed := false
+	w := camunda.NewHandlerWorker("registry-test-options", testkit.Completes(map[string]interface{}{"ok": true}),
+		camunda.WithHealthCheck(func(context.Context) error { return errDown }),
+		camunda.WithCloser(func() error { closed = true; return nil }),
+	)
+	assert.ErrorIs(t, w.HealthCheck(context.Background()), errDown)
+	assert.NoError(t, w.Close())
+	assert.True(t, closed)
+
+	client := testkit.NewJobClient()
+	w.Handle(client, testkit.NewJob("registry-test-options", nil))
+	require.Len(t, client.Commands(), 1, "Handle calls the wrapped handler")
+}
@@ -0,0 +1,117 @@
+// internal/common/camunda/schema.go
+package camunda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/metrics"
+	"camunda-workers/internal/common/validation"
+)
+
+// Schema validation error codes.
+const (
+	ErrCodeValidationFailed       errors.ErrorCode = "VALIDATION_FAILED"
+	ErrCodeOutputValidationFailed errors.ErrorCode = "OUTPUT_VALIDATION_FAILED"
+)
+
+// SchemaValidation checks job variables against the activity's input schema
+// before the handler runs and the handler's output against its output schema
+// afterwards. A nil schema or ModeOff skips that side.
+//
+// Job variables include every variable visible in the process scope, so
+// properties not declared in the input schema are always allowed.
+//
+// In ModeStrict an invalid input fails the job with a VALIDATION_FAILED BPMN
+// error and an invalid output with OUTPUT_VALIDATION_FAILED; both carry the
+// field errors in the fieldErrors metadata. ModeWarn only logs the violations.
+func SchemaValidation(input, output *validation.JSONSchema, inputMode, outputMode validation.Mode) Middleware {
+	if input != nil {
+		open := *input
+		open.AdditionalProperties = true
+		input = &open
+	}
+
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context, job *Job) error {
+			if input != nil && inputMode != validation.ModeOff {
+				variables := make(map[string]interface{})
+				if err := json.Unmarshal([]byte(job.Variables), &variables); err != nil {
+					return errors.NewInputParsingFailedError(err)
+				}
+				result := validation.ValidateInput(variables, *input)
+				if err := schemaViolation(job, "input", result, inputMode); err != nil {
+					return err
+				}
+			}
+
+			if err := next(ctx, job); err != nil {
+				return err
+			}
+
+			if output != nil && outputMode != validation.ModeOff && job.Output != nil {
+				variables, err := outputVariables(job.Output)
+				if err != nil {
+					return errors.NewInternalError(fmt.Sprintf("encode output: %v", err))
+				}
+				result := validation.ValidateInput(variables, *output)
+				if err := schemaViolation(job, "output", result, outputMode); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+}
+
+// schemaViolation records a failed validation and, in strict mode, returns
+// the error that fails the job.
+func schemaViolation(job *Job, direction string, result *validation.ValidationResult, mode validation.Mode) error {
+	if result.Valid {
+		return nil
+	}
+	metrics.WorkerSchemaViolations.WithLabelValues(job.TaskType, direction).Inc()
+
+	messages := result.GetErrorMessages()
+	if mode != validation.ModeStrict {
+		if job.Logger != nil {
+			job.Logger.Warn(direction+" schema violation", map[string]interface{}{
+				"fieldErrors": messages,
+			})
+		}
+		return nil
+	}
+
+	code, message := ErrCodeValidationFailed, "Input validation failed"
+	if direction == "output" {
+		code, message = ErrCodeOutputValidationFailed, "Output validation failed"
+	}
+	return &errors.StandardError{
+		Code:      code,
+		Message:   message,
+		Details:   strings.Join(messages, "; "),
+		Retryable: false,
+		Metadata: map[string]interface{}{
+			"fieldErrors": result.Errors,
+		},
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// outputVariables returns the handler output as the variable map it will be
+// completed with.
+func outputVariables(output interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	variables := make(map[string]interface{})
+	if err := json.Unmarshal(data, &variables); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
@@ -0,0 +1,175 @@
+// internal/common/camunda/schema_test.go
+package camunda
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/validation"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+var (
+	testInputSchema = &validation.JSONSchema{
+		Type:       "object",
+		Properties: map[string]validation.Property{"franchiseId": {Type: "string"}},
+		Required:   []string{"franchiseId"},
+	}
+	testOutputSchema = &validation.JSONSchema{
+		Type:       "object",
+		Properties: map[string]validation.Property{"score": {Type: "number"}},
+		Required:   []string{"score"},
+	}
+)
+
+// runSchemaValidation passes a job with variables through SchemaValidation
+// to a handler completing it with output, and returns the warnings logged
+// and the chain's error.
+func runSchemaValidation(t *testing.T, variables string, output interface{}, inputMode, outputMode validation.Mode) ([]observer.LoggedEntry, error) {
+	core, logs := observer.New(zap.WarnLevel)
+	job := &Job{
+		Job:      entities.Job{ActivatedJob: &pb.ActivatedJob{Type: "test-task", Variables: variables}},
+		TaskType: "test-task",
+		Logger:   logger.NewZapAdapter(zap.New(core)),
+	}
+
+	handler := func(ctx context.Context, job *Job) error {
+		job.Output = output
+		return nil
+	}
+	err := SchemaValidation(testInputSchema, testOutputSchema, inputMode, outputMode)(handler)(context.Background(), job)
+	return logs.All(), err
+}
+
+func TestSchemaValidation_Modes(t *testing.T) {
+	validOutput := map[string]interface{}{"score": 0.8}
+	invalidOutput := map[string]interface{}{"score": "high"}
+
+	tests := []struct {
+		name         string
+		variables    string
+		output       interface{}
+		inputMode    validation.Mode
+		outputMode   validation.Mode
+		expectedCode errors.ErrorCode
+		expectedWarn string
+	}{
+		{
+			name:       "valid input and output",
+			variables:  `{"franchiseId": "f-1"}`,
+			output:     validOutput,
+			inputMode:  validation.ModeStrict,
+			outputMode: validation.ModeStrict,
+		},
+		{
+			name:       "undeclared process variables are allowed",
+			variables:  `{"franchiseId": "f-1", "userId": "u-1"}`,
+			output:     validOutput,
+			inputMode:  validation.ModeStrict,
+			outputMode: validation.ModeStrict,
+		},
+		{
+			name:         "strict input fails the job",
+			variables:    `{}`,
+			output:       validOutput,
+			inputMode:    validation.ModeStrict,
+			outputMode:   validation.ModeStrict,
+			expectedCode: ErrCodeValidationFailed,
+		},
+		{
+			name:         "warn input logs and continues",
+			variables:    `{}`,
+			output:       validOutput,
+			inputMode:    validation.ModeWarn,
+			outputMode:   validation.ModeStrict,
+			expectedWarn: "input schema violation",
+		},
+		{
+			name:       "off input skips validation",
+			variables:  `{"franchiseId": 42}`,
+			output:     validOutput,
+			inputMode:  validation.ModeOff,
+			outputMode: validation.ModeStrict,
+		},
+		{
+			name:         "strict output fails the job",
+			variables:    `{"franchiseId": "f-1"}`,
+			output:       invalidOutput,
+			inputMode:    validation.ModeStrict,
+			outputMode:   validation.ModeStrict,
+			expectedCode: ErrCodeOutputValidationFailed,
+		},
+		{
+			name:         "warn output logs and completes",
+			variables:    `{"franchiseId": "f-1"}`,
+			output:       invalidOutput,
+			inputMode:    validation.ModeStrict,
+			outputMode:   validation.ModeWarn,
+			expectedWarn: "output schema violation",
+		},
+		{
+			name:       "off output skips validation",
+			variables:  `{"franchiseId": "f-1"}`,
+			output:     invalidOutput,
+			inputMode:  validation.ModeStrict,
+			outputMode: validation.ModeOff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := runSchemaValidation(t, tt.variables, tt.output, tt.inputMode, tt.outputMode)
+
+			if tt.expectedCode == "" {
+				require.NoError(t, err)
+			} else {
+				var stdErr *errors.StandardError
+				require.True(t, stderrors.As(err, &stdErr), "expected a StandardError, got %v", err)
+				assert.Equal(t, tt.expectedCode, stdErr.Code)
+				assert.False(t, stdErr.Retryable)
+				assert.NotEmpty(t, stdErr.Metadata["fieldErrors"])
+			}
+
+			if tt.expectedWarn == "" {
+				assert.Empty(t, warnings)
+			} else {
+				require.Len(t, warnings, 1)
+				assert.Equal(t, tt.expectedWarn, warnings[0].Message)
+			}
+		})
+	}
+}
+
+func TestSchemaValidation_StrictInputSkipsHandler(t *testing.T) {
+	called := false
+	job := &Job{
+		Job:      entities.Job{ActivatedJob: &pb.ActivatedJob{Type: "test-task", Variables: `{}`}},
+		TaskType: "test-task",
+	}
+	handler := func(ctx context.Context, job *Job) error {
+		called = true
+		return nil
+	}
+
+	err := SchemaValidation(testInputSchema, nil, validation.ModeStrict, validation.ModeOff)(handler)(context.Background(), job)
+
+	require.Error(t, err)
+	assert.False(t, called, "the handler must not run on invalid input")
+}
+
+func TestSchemaValidation_InvalidVariables(t *testing.T) {
+	_, err := runSchemaValidation(t, `not json`, nil, validation.ModeWarn, validation.ModeOff)
+
+	var stdErr *errors.StandardError
+	require.True(t, stderrors.As(err, &stdErr))
+	assert.Equal(t, errors.ErrorCode("INPUT_PARSING_FAILED"), stdErr.Code)
+}
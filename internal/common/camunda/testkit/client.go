@@ -0,0 +1,193 @@
+// internal/common/camunda/testkit/client.go
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/commands"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"google.golang.org/grpc"
+)
+
+// CommandKind is the job command a handler sent.
+type CommandKind string
+
+const (
+	Complete   CommandKind = "complete"
+	Fail       CommandKind = "fail"
+	ThrowError CommandKind = "throwError"
+)
+
+// Command is one job command as the broker would have received it.
+type Command struct {
+	Kind         CommandKind
+	JobKey       int64
+	Variables    map[string]interface{}
+	Retries      int32         // Fail only
+	RetryBackoff time.Duration // Fail only
+	ErrorCode    string        // ThrowError only
+	ErrorMessage string        // Fail and ThrowError
+}
+
+// Recorder captures the commands sent through a JobClient.
+type Recorder struct {
+	mu       sync.Mutex
+	commands []Command
+	reject   error
+}
+
+// Commands returns every recorded command in the order it was sent.
+func (r *Recorder) Commands() []Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Command(nil), r.commands...)
+}
+
+// ForJob returns the commands sent for the given job key.
+func (r *Recorder) ForJob(jobKey int64) []Command {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var cmds []Command
+	for _, c := range r.commands {
+		if c.JobKey == jobKey {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+// Last returns the most recent command, or false if none was sent.
+func (r *Recorder) Last() (Command, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.commands) == 0 {
+		return Command{}, false
+	}
+	return r.commands[len(r.commands)-1], true
+}
+
+// Reset forgets all recorded commands.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = nil
+}
+
+// Reject makes the broker reject every following command with err, as when
+// the job has already timed out or been cancelled. Pass nil to accept again.
+// Rejected commands are still recorded.
+func (r *Recorder) Reject(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reject = err
+}
+
+func (r *Recorder) record(c Command) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, c)
+	return r.reject
+}
+
+// JobClient is an in-memory worker.JobClient. It builds commands with the
+// Zeebe client's own command builders and records what they would send to
+// the gateway, so handlers are exercised exactly as against a broker.
+type JobClient struct {
+	*Recorder
+	gateway *gateway
+}
+
+var _ worker.JobClient = (*JobClient)(nil)
+
+// NewJobClient creates a JobClient with an empty recorder.
+func NewJobClient() *JobClient {
+	rec := &Recorder{}
+	return &JobClient{Recorder: rec, gateway: &gateway{recorder: rec}}
+}
+
+// noRetry is the retry predicate for commands sent to the fake gateway.
+func noRetry(context.Context, error) bool { return false }
+
+func (c *JobClient) NewCompleteJobCommand() commands.CompleteJobCommandStep1 {
+	return commands.NewCompleteJobCommand(c.gateway, noRetry)
+}
+
+func (c *JobClient) NewFailJobCommand() commands.FailJobCommandStep1 {
+	return commands.NewFailJobCommand(c.gateway, noRetry)
+}
+
+func (c *JobClient) NewThrowErrorCommand() commands.ThrowErrorCommandStep1 {
+	return commands.NewThrowErrorCommand(c.gateway, noRetry)
+}
+
+// gateway implements the job RPCs of pb.GatewayClient. Every other RPC is
+// left to the embedded nil interface and panics if called.
+type gateway struct {
+	pb.GatewayClient
+	recorder *Recorder
+}
+
+func (g *gateway) CompleteJob(_ context.Context, in *pb.CompleteJobRequest, _ ...grpc.CallOption) (*pb.CompleteJobResponse, error) {
+	vars, err := decodeVariables(in.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.recorder.record(Command{Kind: Complete, JobKey: in.JobKey, Variables: vars}); err != nil {
+		return nil, err
+	}
+	return &pb.CompleteJobResponse{}, nil
+}
+
+func (g *gateway) FailJob(_ context.Context, in *pb.FailJobRequest, _ ...grpc.CallOption) (*pb.FailJobResponse, error) {
+	vars, err := decodeVariables(in.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.recorder.record(Command{
+		Kind:         Fail,
+		JobKey:       in.JobKey,
+		Variables:    vars,
+		Retries:      in.Retries,
+		RetryBackoff: time.Duration(in.RetryBackOff) * time.Millisecond,
+		ErrorMessage: in.ErrorMessage,
+	}); err != nil {
+		return nil, err
+	}
+	return &pb.FailJobResponse{}, nil
+}
+
+func (g *gateway) ThrowError(_ context.Context, in *pb.ThrowErrorRequest, _ ...grpc.CallOption) (*pb.ThrowErrorResponse, error) {
+	vars, err := decodeVariables(in.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.recorder.record(Command{
+		Kind:         ThrowError,
+		JobKey:       in.JobKey,
+		Variables:    vars,
+		ErrorCode:    in.ErrorCode,
+		ErrorMessage: in.ErrorMessage,
+	}); err != nil {
+		return nil, err
+	}
+	return &pb.ThrowErrorResponse{}, nil
+}
+
+// decodeVariables parses a command's variable document. Like the broker it
+// only accepts a JSON object.
+func decodeVariables(doc string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{})
+	if doc == "" {
+		return vars, nil
+	}
+	if err := json.Unmarshal([]byte(doc), &vars); err != nil {
+		return nil, fmt.Errorf("variables must be a JSON object: %w", err)
+	}
+	return vars, nil
+}
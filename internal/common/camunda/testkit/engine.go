@@ -0,0 +1,829 @@
+// internal/common/camunda/testkit/engine.go
+package testkit
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/pkg/bpmn"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// DefaultMaxActivations bounds the elements a single process instance may
+// enter, so that a model that loops forever fails the test instead.
+const DefaultMaxActivations = 1000
+
+// Clock is the virtual time of an Engine. Timers fire when it is advanced
+// past their due time; nothing in the engine sleeps.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock creates a clock set to start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the current virtual time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d. Handlers call it to simulate work
+// that takes time.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// InstanceState is the state a process instance ended in.
+type InstanceState string
+
+const (
+	StateCompleted InstanceState = "completed"
+	// StateIncident means a job ran out of retries, a mapping or condition
+	// could not be evaluated or a BPMN error was not caught.
+	StateIncident InstanceState = "incident"
+	// StateTerminated means an interrupting boundary event on the calling
+	// call activity ended the instance.
+	StateTerminated InstanceState = "terminated"
+)
+
+// Incident describes why an instance stopped.
+type Incident struct {
+	ElementID string
+	Message   string
+	// Command is the Fail command that used up the job's retries, if any.
+	Command *Command
+}
+
+// Instance is a replayed process instance.
+type Instance struct {
+	ProcessID string
+	Key       int64
+	State     InstanceState
+	Variables map[string]interface{}
+	// Path lists the elements in the order tokens entered them. A joining
+	// parallel gateway appears once per arriving token; boundary events
+	// appear when they trigger.
+	Path     []string
+	Steps    []Step
+	Incident *Incident
+	// Children are the instances started by call activities, in order.
+	Children []*Instance
+}
+
+// Visited reports whether a token entered the element.
+func (i *Instance) Visited(elementID string) bool {
+	for _, id := range i.Path {
+		if id == elementID {
+			return true
+		}
+	}
+	return false
+}
+
+// Step returns the last service task step of the element.
+func (i *Instance) Step(elementID string) (Step, bool) {
+	for n := len(i.Steps) - 1; n >= 0; n-- {
+		if i.Steps[n].ElementID == elementID {
+			return i.Steps[n], true
+		}
+	}
+	return Step{}, false
+}
+
+// Engine replays BPMN processes in memory, driving the registered handlers
+// through a JobClient. It supports service tasks, exclusive, inclusive
+// (split only) and parallel gateways with FEEL-lite conditions, error and
+// timer boundary events, timer catch events, error end events and call
+// activities, with zeebe:ioMapping inputs and outputs.
+//
+// Tokens run one at a time in a fixed order, so replays are deterministic.
+// Unlike the broker, an incident stops the whole instance.
+type Engine struct {
+	Client *JobClient
+	Clock  *Clock
+	// MaxAttempts bounds the activations of a single job. Defaults to
+	// DefaultMaxAttempts.
+	MaxAttempts int
+	// MaxActivations bounds the elements one instance enters. Defaults to
+	// DefaultMaxActivations.
+	MaxActivations int
+
+	handlers  map[string]func(worker.JobClient, entities.Job)
+	latency   map[string]time.Duration
+	processes map[string]deployment
+}
+
+type deployment struct {
+	defs    *bpmn.Definitions
+	process *bpmn.Process
+}
+
+// NewEngine creates an engine with no processes or handlers and a clock set
+// to 2024-01-01 UTC.
+func NewEngine() *Engine {
+	return &Engine{
+		Client:         NewJobClient(),
+		Clock:          NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		MaxAttempts:    DefaultMaxAttempts,
+		MaxActivations: DefaultMaxActivations,
+		handlers:       make(map[string]func(worker.JobClient, entities.Job)),
+		latency:        make(map[string]time.Duration),
+		processes:      make(map[string]deployment),
+	}
+}
+
+// Deploy makes the processes of the given definitions available to Run and
+// to call activities.
+func (e *Engine) Deploy(defs ...*bpmn.Definitions) *Engine {
+	for _, d := range defs {
+		for _, p := range d.Processes {
+			e.processes[p.ID] = deployment{defs: d, process: p}
+		}
+	}
+	return e
+}
+
+// DeployFiles parses and deploys BPMN files.
+func (e *Engine) DeployFiles(paths ...string) error {
+	for _, path := range paths {
+		defs, err := bpmn.ParseFile(path)
+		if err != nil {
+			return err
+		}
+		e.Deploy(defs)
+	}
+	return nil
+}
+
+// Register sets the handler for a task type.
+func (e *Engine) Register(taskType string, handler func(worker.JobClient, entities.Job)) *Engine {
+	e.handlers[taskType] = handler
+	return e
+}
+
+// RegisterWorker registers a worker under its task type.
+func (e *Engine) RegisterWorker(w camunda.Worker) *Engine {
+	return e.Register(w.TaskType(), w.Handle)
+}
+
+// SetLatency advances the clock by d after every job of the task type, so
+// that real handlers can be made slow enough for timer boundaries to fire.
+func (e *Engine) SetLatency(taskType string, d time.Duration) *Engine {
+	e.latency[taskType] = d
+	return e
+}
+
+// Run starts an instance of the process with variables and runs it until no
+// token is left. Incidents and uncaught BPMN errors are reported on the
+// instance; an error is returned when the model uses an unsupported element,
+// a task type has no handler, a handler sends no command or a limit is hit.
+func (e *Engine) Run(processID string, variables map[string]interface{}) (*Instance, error) {
+	dep, ok := e.processes[processID]
+	if !ok {
+		return nil, fmt.Errorf("process %q is not deployed", processID)
+	}
+
+	ex := e.newExecution(dep, nil, variables)
+	err := ex.run()
+
+	var thrown *thrownError
+	if stderrors.As(err, &thrown) {
+		incident := &Incident{
+			ElementID: thrown.element,
+			Message:   fmt.Sprintf("unhandled error event %q: %s", thrown.code, thrown.message),
+		}
+		for x := thrown.origin; x != nil; x = x.parent {
+			x.inst.State = StateIncident
+			x.inst.Incident = incident
+		}
+		return ex.inst, nil
+	}
+	var incident *incidentError
+	if stderrors.As(err, &incident) {
+		return ex.inst, nil
+	}
+	return ex.inst, err
+}
+
+// flowToken is a token waiting to enter an element, over the given flow.
+type flowToken struct {
+	element string
+	flow    string
+}
+
+// timer is a running timer boundary event.
+type timer struct {
+	host     string
+	boundary *bpmn.Element
+	due      time.Time
+}
+
+// execution runs one process instance.
+type execution struct {
+	engine  *Engine
+	parent  *execution
+	defs    *bpmn.Definitions
+	process *bpmn.Process
+	inst    *Instance
+
+	queue       []flowToken
+	arrived     map[string]map[string]int // parallel gateway ID to arrivals per flow
+	timers      []*timer
+	activations int
+}
+
+// interruption unwinds executions up to the owner of an interrupting timer.
+type interruption struct {
+	owner *execution
+	timer *timer
+}
+
+func (i *interruption) Error() string {
+	return fmt.Sprintf("timer %s interrupted %s", i.timer.boundary.ID, i.timer.host)
+}
+
+// thrownError propagates a BPMN error up to a catching boundary event.
+type thrownError struct {
+	origin    *execution
+	element   string
+	code      string
+	message   string
+	variables map[string]interface{}
+}
+
+func (t *thrownError) Error() string {
+	return fmt.Sprintf("error %s thrown at %s", t.code, t.element)
+}
+
+// incidentError stops every execution up to the root.
+type incidentError struct {
+	incident *Incident
+}
+
+func (i *incidentError) Error() string {
+	return fmt.Sprintf("incident at %s: %s", i.incident.ElementID, i.incident.Message)
+}
+
+func (e *Engine) newExecution(dep deployment, parent *execution, variables map[string]interface{}) *execution {
+	return &execution{
+		engine:  e,
+		parent:  parent,
+		defs:    dep.defs,
+		process: dep.process,
+		arrived: make(map[string]map[string]int),
+		inst: &Instance{
+			ProcessID: dep.process.ID,
+			Key:       nextKey(),
+			Variables: merge(nil, variables),
+		},
+	}
+}
+
+func (ex *execution) run() error {
+	start := ex.startEvent()
+	if start == nil {
+		return fmt.Errorf("process %s has no start event", ex.process.ID)
+	}
+	ex.queue = append(ex.queue, flowToken{element: start.ID})
+
+	for len(ex.queue) > 0 {
+		tok := ex.queue[0]
+		ex.queue = ex.queue[1:]
+
+		if err := ex.enter(tok); err != nil {
+			var intr *interruption
+			if stderrors.As(err, &intr) && intr.owner == ex {
+				ex.interrupt(intr.timer)
+				continue
+			}
+			return ex.stop(err)
+		}
+	}
+	ex.inst.State = StateCompleted
+	return nil
+}
+
+// stop records how the instance ended before err unwinds it.
+func (ex *execution) stop(err error) error {
+	var incident *incidentError
+	switch {
+	case stderrors.As(err, &incident):
+		ex.inst.State = StateIncident
+		ex.inst.Incident = incident.incident
+	case stderrors.As(err, new(*thrownError)), stderrors.As(err, new(*interruption)):
+		ex.inst.State = StateTerminated
+	}
+	return err
+}
+
+// startEvent returns the process's none start event.
+func (ex *execution) startEvent() *bpmn.Element {
+	var first *bpmn.Element
+	for _, id := range ex.process.Order {
+		el := ex.process.Elements[id]
+		if el.Kind != bpmn.KindStartEvent {
+			continue
+		}
+		if el.EventKind == "" {
+			return el
+		}
+		if first == nil {
+			first = el
+		}
+	}
+	return first
+}
+
+func (ex *execution) enter(tok flowToken) error {
+	el, ok := ex.process.Elements[tok.element]
+	if !ok {
+		return fmt.Errorf("%s: flow %s leads to unknown element %q", ex.process.ID, tok.flow, tok.element)
+	}
+	ex.activations++
+	if max := ex.engine.maxActivations(); ex.activations > max {
+		return fmt.Errorf("%s: more than %d element activations, does the model loop?", ex.process.ID, max)
+	}
+	ex.inst.Path = append(ex.inst.Path, el.ID)
+
+	switch el.Kind {
+	case bpmn.KindStartEvent, bpmn.KindBoundaryEvent, "task":
+		return ex.leave(el)
+
+	case bpmn.KindIntermediateThrowEvent:
+		if el.EventKind != "" {
+			return ex.unsupported(el)
+		}
+		return ex.leave(el)
+
+	case bpmn.KindIntermediateCatchEvent:
+		if el.EventKind != bpmn.EventTimer {
+			return ex.unsupported(el)
+		}
+		d, err := bpmn.ParseDuration(el.TimerDuration)
+		if err != nil {
+			return ex.incident(el, err.Error(), nil)
+		}
+		ex.engine.Clock.Advance(d)
+		if err := ex.fireTimers(); err != nil {
+			return err
+		}
+		return ex.leave(el)
+
+	case bpmn.KindEndEvent:
+		switch el.EventKind {
+		case "":
+			return nil
+		case bpmn.EventError:
+			thrown := &thrownError{origin: ex, element: el.ID, message: "error end event " + el.Label()}
+			if e, ok := ex.defs.Errors[el.ErrorRef]; ok {
+				thrown.code = e.Code
+			}
+			return thrown
+		}
+		return ex.unsupported(el)
+
+	case bpmn.KindServiceTask, "sendTask", "businessRuleTask", "scriptTask":
+		if el.TaskType == "" {
+			return ex.unsupported(el)
+		}
+		return ex.runJob(el)
+
+	case bpmn.KindExclusiveGateway, "inclusiveGateway":
+		if len(ex.incoming(el)) > 1 && el.Kind == "inclusiveGateway" {
+			return ex.unsupported(el)
+		}
+		return ex.choose(el, el.Kind == bpmn.KindExclusiveGateway)
+
+	case bpmn.KindParallelGateway:
+		return ex.join(el, tok)
+
+	case bpmn.KindCallActivity:
+		return ex.call(el)
+	}
+	return ex.unsupported(el)
+}
+
+func (ex *execution) unsupported(el *bpmn.Element) error {
+	kind := el.Kind
+	if el.EventKind != "" {
+		kind = el.EventKind + " " + kind
+	}
+	return fmt.Errorf("%s/%s: %s is not supported by the testkit engine", ex.process.ID, el.Label(), kind)
+}
+
+func (ex *execution) incident(el *bpmn.Element, message string, cmd *Command) error {
+	return &incidentError{incident: &Incident{ElementID: el.ID, Message: message, Command: cmd}}
+}
+
+// leave passes a token over every outgoing flow of el.
+func (ex *execution) leave(el *bpmn.Element) error {
+	for _, flow := range ex.outgoing(el) {
+		ex.take(flow)
+	}
+	return nil
+}
+
+func (ex *execution) take(flow *bpmn.SequenceFlow) {
+	ex.queue = append(ex.queue, flowToken{element: flow.Target, flow: flow.ID})
+}
+
+// outgoing returns el's outgoing flows in model order.
+func (ex *execution) outgoing(el *bpmn.Element) []*bpmn.SequenceFlow {
+	var flows []*bpmn.SequenceFlow
+	for _, id := range el.Outgoing {
+		if flow, ok := ex.process.Flows[id]; ok && flow.Source == el.ID {
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) == 0 {
+		flows = ex.process.OutgoingFlows(el.ID)
+	}
+	return flows
+}
+
+func (ex *execution) incoming(el *bpmn.Element) []string {
+	var ids []string
+	for _, id := range ex.process.FlowIDs() {
+		if ex.process.Flows[id].Target == el.ID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// choose takes the first (exclusive) or every (inclusive) outgoing flow whose
+// condition holds, or the default flow when none does.
+func (ex *execution) choose(gw *bpmn.Element, exclusive bool) error {
+	flows := ex.outgoing(gw)
+	taken := 0
+	for _, flow := range flows {
+		if flow.ID == gw.DefaultFlow {
+			continue
+		}
+		ok := len(flows) == 1 && flow.Condition == ""
+		if flow.Condition != "" {
+			var err error
+			ok, err = bpmn.EvaluateCondition(flow.Condition, ex.inst.Variables)
+			if err != nil {
+				return ex.incident(gw, err.Error(), nil)
+			}
+		}
+		if ok {
+			ex.take(flow)
+			taken++
+			if exclusive {
+				return nil
+			}
+		}
+	}
+	if taken > 0 {
+		return nil
+	}
+	if flow, ok := ex.process.Flows[gw.DefaultFlow]; ok {
+		ex.take(flow)
+		return nil
+	}
+	return ex.incident(gw, "no outgoing sequence flow condition is true and there is no default flow", nil)
+}
+
+// join forks a parallel gateway, once a token has arrived on every incoming
+// flow when it has several.
+func (ex *execution) join(gw *bpmn.Element, tok flowToken) error {
+	incoming := ex.incoming(gw)
+	if len(incoming) > 1 {
+		arrived := ex.arrived[gw.ID]
+		if arrived == nil {
+			arrived = make(map[string]int)
+			ex.arrived[gw.ID] = arrived
+		}
+		arrived[tok.flow]++
+		for _, id := range incoming {
+			if arrived[id] == 0 {
+				return nil
+			}
+		}
+		for _, id := range incoming {
+			arrived[id]--
+		}
+	}
+	return ex.leave(gw)
+}
+
+// runJob activates jobs for a service task until one is completed, a BPMN
+// error is thrown, the retries run out or a timer interrupts the task.
+func (ex *execution) runJob(el *bpmn.Element) error {
+	taskType, err := ex.evaluateString(el.TaskType)
+	if err != nil {
+		return ex.incident(el, err.Error(), nil)
+	}
+	handler, ok := ex.engine.handlers[taskType]
+	if !ok {
+		return fmt.Errorf("%s/%s: no handler registered for task type %q", ex.process.ID, el.Label(), taskType)
+	}
+
+	local, err := ex.inputs(el)
+	if err != nil {
+		return ex.incident(el, err.Error(), nil)
+	}
+	variables := merge(ex.inst.Variables, local)
+
+	if err := ex.startTimers(el); err != nil {
+		return err
+	}
+	defer ex.stopTimers(el.ID)
+
+	step := Step{TaskType: taskType, ElementID: el.ID}
+	defer func() { ex.inst.Steps = append(ex.inst.Steps, step) }()
+
+	retries := int32(DefaultRetries)
+	if el.Retries > 0 {
+		retries = int32(el.Retries)
+	}
+	maxAttempts := ex.engine.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > maxAttempts {
+			return fmt.Errorf("%s/%s: still failing after %d attempts", ex.process.ID, el.Label(), maxAttempts)
+		}
+
+		job := NewJob(taskType, variables,
+			WithRetries(retries),
+			WithProcess(ex.process.ID, ex.inst.Key),
+			WithElementID(el.ID),
+			WithCustomHeaders(el.Headers),
+		)
+		step.Jobs = append(step.Jobs, job)
+		handler(ex.engine.Client, job)
+		ex.engine.Clock.Advance(ex.engine.latency[taskType])
+
+		cmds := ex.engine.Client.ForJob(job.Key)
+		step.Commands = append(step.Commands, cmds...)
+
+		// A timer that fired while the job ran wins; the broker would reject
+		// the job's command because the job was cancelled.
+		if err := ex.fireTimers(); err != nil {
+			return err
+		}
+		if len(cmds) == 0 {
+			return fmt.Errorf("%s/%s: handler sent no command for job %d", ex.process.ID, el.Label(), job.Key)
+		}
+
+		cmd := cmds[len(cmds)-1]
+		switch cmd.Kind {
+		case Complete:
+			if err := ex.outputs(el, merge(variables, cmd.Variables), cmd.Variables); err != nil {
+				return ex.incident(el, err.Error(), nil)
+			}
+			return ex.leave(el)
+
+		case ThrowError:
+			return ex.catch(el, &thrownError{
+				origin:    ex,
+				element:   el.ID,
+				code:      cmd.ErrorCode,
+				message:   cmd.ErrorMessage,
+				variables: cmd.Variables,
+			})
+
+		case Fail:
+			if cmd.Retries <= 0 {
+				return ex.incident(el, cmd.ErrorMessage, &cmd)
+			}
+			retries = cmd.Retries
+			step.Backoff += cmd.RetryBackoff
+			ex.engine.Clock.Advance(cmd.RetryBackoff)
+			if err := ex.fireTimers(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// call runs the called process to its end as a child instance.
+func (ex *execution) call(el *bpmn.Element) error {
+	processID, err := ex.evaluateString(el.CalledProcessID)
+	if err != nil {
+		return ex.incident(el, err.Error(), nil)
+	}
+	dep, ok := ex.engine.processes[processID]
+	if !ok {
+		return fmt.Errorf("%s/%s: called process %q is not deployed", ex.process.ID, el.Label(), processID)
+	}
+
+	local, err := ex.inputs(el)
+	if err != nil {
+		return ex.incident(el, err.Error(), nil)
+	}
+	variables := local
+	if el.PropagateParent {
+		variables = merge(ex.inst.Variables, local)
+	}
+
+	if err := ex.startTimers(el); err != nil {
+		return err
+	}
+	defer ex.stopTimers(el.ID)
+
+	child := ex.engine.newExecution(dep, ex, variables)
+	ex.inst.Children = append(ex.inst.Children, child.inst)
+
+	if err := child.run(); err != nil {
+		var thrown *thrownError
+		if stderrors.As(err, &thrown) {
+			return ex.catch(el, thrown)
+		}
+		return err
+	}
+
+	result := child.inst.Variables
+	if !el.PropagateChild {
+		result = nil
+	}
+	if err := ex.outputs(el, merge(merge(ex.inst.Variables, local), child.inst.Variables), result); err != nil {
+		return ex.incident(el, err.Error(), nil)
+	}
+	return ex.leave(el)
+}
+
+// catch passes a thrown BPMN error to the error boundary event on el that
+// matches its code, or to a catch-all one. Uncaught errors propagate to the
+// calling call activity.
+func (ex *execution) catch(el *bpmn.Element, thrown *thrownError) error {
+	var catchAll *bpmn.Element
+	for _, b := range ex.process.Boundaries(el.ID) {
+		if b.EventKind != bpmn.EventError {
+			continue
+		}
+		code := ""
+		if e, ok := ex.defs.Errors[b.ErrorRef]; ok {
+			code = e.Code
+		}
+		if code == thrown.code {
+			return ex.trigger(el, b, thrown.variables)
+		}
+		if code == "" && catchAll == nil {
+			catchAll = b
+		}
+	}
+	if catchAll != nil {
+		return ex.trigger(el, catchAll, thrown.variables)
+	}
+	return thrown
+}
+
+// trigger leaves el through the boundary event b.
+func (ex *execution) trigger(el, b *bpmn.Element, variables map[string]interface{}) error {
+	ex.stopTimers(el.ID)
+	ex.inst.Variables = merge(ex.inst.Variables, variables)
+	ex.queue = append(ex.queue, flowToken{element: b.ID})
+	return nil
+}
+
+// interrupt ends the timer's host and continues from the boundary event.
+func (ex *execution) interrupt(t *timer) {
+	ex.stopTimers(t.host)
+	ex.queue = append(ex.queue, flowToken{element: t.boundary.ID})
+}
+
+// startTimers starts the timer boundary events attached to el.
+func (ex *execution) startTimers(el *bpmn.Element) error {
+	for _, b := range ex.process.Boundaries(el.ID) {
+		if b.EventKind != bpmn.EventTimer {
+			continue
+		}
+		d, err := bpmn.ParseDuration(b.TimerDuration)
+		if err != nil {
+			return ex.incident(b, err.Error(), nil)
+		}
+		ex.timers = append(ex.timers, &timer{host: el.ID, boundary: b, due: ex.engine.Clock.Now().Add(d)})
+	}
+	return nil
+}
+
+func (ex *execution) stopTimers(host string) {
+	kept := ex.timers[:0]
+	for _, t := range ex.timers {
+		if t.host != host {
+			kept = append(kept, t)
+		}
+	}
+	ex.timers = kept
+}
+
+// fireTimers fires every due timer of this and the calling executions,
+// earliest first. Non-interrupting timers pass a token to their boundary
+// event; an interrupting one unwinds to its owner.
+func (ex *execution) fireTimers() error {
+	now := ex.engine.Clock.Now()
+	for {
+		var due *timer
+		var owner *execution
+		for x := ex; x != nil; x = x.parent {
+			for _, t := range x.timers {
+				if !t.due.After(now) && (due == nil || t.due.Before(due.due)) {
+					due, owner = t, x
+				}
+			}
+		}
+		if due == nil {
+			return nil
+		}
+
+		if due.boundary.CancelActivity {
+			return &interruption{owner: owner, timer: due}
+		}
+		kept := owner.timers[:0]
+		for _, t := range owner.timers {
+			if t != due {
+				kept = append(kept, t)
+			}
+		}
+		owner.timers = kept
+		owner.queue = append(owner.queue, flowToken{element: due.boundary.ID})
+	}
+}
+
+// inputs evaluates el's input mappings into its local variables.
+func (ex *execution) inputs(el *bpmn.Element) (map[string]interface{}, error) {
+	local := make(map[string]interface{})
+	for _, m := range el.Inputs {
+		v, err := bpmn.Evaluate(m.Source, merge(ex.inst.Variables, local))
+		if err != nil {
+			return nil, fmt.Errorf("input mapping %s: %w", m.Target, err)
+		}
+		setPath(local, m.Target, v)
+	}
+	return local, nil
+}
+
+// outputs propagates result into the process scope and then applies el's
+// output mappings, evaluated against scope.
+func (ex *execution) outputs(el *bpmn.Element, scope, result map[string]interface{}) error {
+	if len(el.Outputs) == 0 || el.Kind == bpmn.KindCallActivity {
+		ex.inst.Variables = merge(ex.inst.Variables, result)
+	}
+	for _, m := range el.Outputs {
+		v, err := bpmn.Evaluate(m.Source, scope)
+		if err != nil {
+			return fmt.Errorf("output mapping %s: %w", m.Target, err)
+		}
+		setPath(ex.inst.Variables, m.Target, v)
+	}
+	return nil
+}
+
+// evaluateString returns s, or the string it evaluates to when it is a
+// "=" expression.
+func (ex *execution) evaluateString(s string) (string, error) {
+	if !strings.HasPrefix(s, "=") {
+		return s, nil
+	}
+	v, err := bpmn.Evaluate(s, ex.inst.Variables)
+	if err != nil {
+		return "", err
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expression %q evaluated to %T, not a string", s, v)
+	}
+	return str, nil
+}
+
+func (e *Engine) maxActivations() int {
+	if e.MaxActivations > 0 {
+		return e.MaxActivations
+	}
+	return DefaultMaxActivations
+}
+
+// setPath sets a mapping target such as "a.b", creating objects on the way.
+func setPath(vars map[string]interface{}, path string, v interface{}) {
+	parts := strings.Split(path, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := vars[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+		} else {
+			next = merge(nil, next)
+		}
+		vars[part] = next
+		vars = next
+	}
+	vars[parts[len(parts)-1]] = v
+}
@@ -0,0 +1,35 @@
+// internal/common/camunda/testkit/handlers.go
+package testkit
+
+import (
+	"context"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// Completes returns a stub handler that completes every job with variables.
+func Completes(variables map[string]interface{}) func(worker.JobClient, entities.Job) {
+	return func(client worker.JobClient, job entities.Job) {
+		cmd, err := client.NewCompleteJobCommand().JobKey(job.Key).VariablesFromMap(variables)
+		if err != nil {
+			panic("testkit: encode completion variables: " + err.Error())
+		}
+		_, _ = cmd.Send(context.Background())
+	}
+}
+
+// Throws returns a stub handler that throws the BPMN error code on every job.
+func Throws(code, message string) func(worker.JobClient, entities.Job) {
+	return func(client worker.JobClient, job entities.Job) {
+		_, _ = client.NewThrowErrorCommand().JobKey(job.Key).ErrorCode(code).ErrorMessage(message).Send(context.Background())
+	}
+}
+
+// Fails returns a stub handler that fails every job, using up one retry
+// each time.
+func Fails(message string) func(worker.JobClient, entities.Job) {
+	return func(client worker.JobClient, job entities.Job) {
+		_, _ = client.NewFailJobCommand().JobKey(job.Key).Retries(job.Retries - 1).ErrorMessage(message).Send(context.Background())
+	}
+}
@@ -0,0 +1,98 @@
+// internal/common/camunda/testkit/job.go
+package testkit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+)
+
+// DefaultRetries is the retry count of jobs built by NewJob, matching the
+// retries most service tasks declare in the models.
+const DefaultRetries = 3
+
+var lastKey int64 = 2251799813685248 // Zeebe's first partition key
+
+// nextKey returns a key unique within the test binary.
+func nextKey() int64 {
+	return atomic.AddInt64(&lastKey, 1)
+}
+
+// JobOption customises a job built by NewJob.
+type JobOption func(*pb.ActivatedJob)
+
+// WithKey sets the job key.
+func WithKey(key int64) JobOption {
+	return func(j *pb.ActivatedJob) {
+		j.Key = key
+	}
+}
+
+// WithRetries sets the job's remaining retries.
+func WithRetries(retries int32) JobOption {
+	return func(j *pb.ActivatedJob) {
+		j.Retries = retries
+	}
+}
+
+// WithProcess sets the BPMN process ID and process instance key.
+func WithProcess(bpmnProcessID string, processInstanceKey int64) JobOption {
+	return func(j *pb.ActivatedJob) {
+		j.BpmnProcessId = bpmnProcessID
+		j.ProcessInstanceKey = processInstanceKey
+	}
+}
+
+// WithElementID sets the ID of the service task the job belongs to.
+func WithElementID(elementID string) JobOption {
+	return func(j *pb.ActivatedJob) {
+		j.ElementId = elementID
+	}
+}
+
+// WithCustomHeaders sets the task headers of the job.
+func WithCustomHeaders(headers map[string]string) JobOption {
+	return func(j *pb.ActivatedJob) {
+		data, err := json.Marshal(headers)
+		if err != nil {
+			panic(fmt.Sprintf("testkit: encode custom headers: %v", err))
+		}
+		j.CustomHeaders = string(data)
+	}
+}
+
+// NewJob builds an activated job of the given task type carrying variables,
+// as a worker would receive it from the broker. Keys are unique, retries
+// default to DefaultRetries and the deadline is five minutes ahead. It panics
+// when variables cannot be encoded as JSON.
+func NewJob(taskType string, variables map[string]interface{}, opts ...JobOption) entities.Job {
+	if variables == nil {
+		variables = map[string]interface{}{}
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		panic(fmt.Sprintf("testkit: encode job variables: %v", err))
+	}
+
+	job := &pb.ActivatedJob{
+		Key:                nextKey(),
+		Type:               taskType,
+		ProcessInstanceKey: nextKey(),
+		BpmnProcessId:      "testkit",
+		ElementId:          taskType,
+		ElementInstanceKey: nextKey(),
+		CustomHeaders:      "{}",
+		Worker:             "testkit",
+		Retries:            DefaultRetries,
+		Deadline:           time.Now().Add(5 * time.Minute).UnixMilli(),
+		Variables:          string(data),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	return entities.Job{ActivatedJob: job}
+}
@@ -0,0 +1,164 @@
+// internal/common/camunda/testkit/runner.go
+package testkit
+
+import (
+	"fmt"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+)
+
+// DefaultMaxAttempts bounds how often the runner re-activates a failing job.
+const DefaultMaxAttempts = 10
+
+// Step is the outcome of one service task in a run.
+type Step struct {
+	TaskType  string
+	ElementID string
+	// Jobs holds every activation of the task, one per attempt.
+	Jobs []entities.Job
+	// Commands holds the commands sent for those jobs, in order.
+	Commands []Command
+	// Backoff is the retry backoff the broker would have waited in total.
+	// The runner re-activates failed jobs immediately.
+	Backoff time.Duration
+}
+
+// Result is the state of the process instance after a run.
+type Result struct {
+	// Variables are the process variables after the last completed task.
+	Variables map[string]interface{}
+	Steps     []Step
+	// Incident is the Fail command that left a job without retries.
+	Incident *Command
+	// Thrown is the ThrowError command that ended the run. Its variables
+	// are not merged, as the broker sets them on the catching event's scope.
+	Thrown *Command
+}
+
+// Completed reports whether every task in the run completed.
+func (r *Result) Completed() bool {
+	return r.Incident == nil && r.Thrown == nil
+}
+
+// Runner executes a linear chain of service tasks in process, the way the
+// broker would run a sequence of service tasks in a single process instance:
+// every job gets all process variables and the variables of each completion
+// are merged into them, top-level key by key, before the next task starts.
+type Runner struct {
+	// Client receives the commands of every job the runner activates. Use
+	// Client.Reject to simulate a broker refusing commands.
+	Client *JobClient
+	// ProcessID is the BPMN process ID set on the jobs.
+	ProcessID string
+	// MaxAttempts bounds the activations of a single task. Defaults to
+	// DefaultMaxAttempts.
+	MaxAttempts int
+
+	handlers map[string]func(worker.JobClient, entities.Job)
+}
+
+// NewRunner creates a runner without handlers.
+func NewRunner() *Runner {
+	return &Runner{
+		Client:      NewJobClient(),
+		ProcessID:   "testkit",
+		MaxAttempts: DefaultMaxAttempts,
+		handlers:    make(map[string]func(worker.JobClient, entities.Job)),
+	}
+}
+
+// Register sets the handler for a task type. Both camunda.HandlerFunc and
+// worker.JobHandler values are accepted.
+func (r *Runner) Register(taskType string, handler func(worker.JobClient, entities.Job)) *Runner {
+	r.handlers[taskType] = handler
+	return r
+}
+
+// RegisterWorker registers a worker under its task type.
+func (r *Runner) RegisterWorker(w camunda.Worker) *Runner {
+	return r.Register(w.TaskType(), w.Handle)
+}
+
+// Run starts a process instance with variables and executes the task types
+// in order. It stops at the first task that throws a BPMN error or raises an
+// incident; both are reported on the result, not as an error. An error is
+// returned when a task type has no handler, when a handler returns without
+// sending a command or when a job still fails after MaxAttempts.
+func (r *Runner) Run(variables map[string]interface{}, taskTypes ...string) (*Result, error) {
+	result := &Result{Variables: merge(nil, variables)}
+	instanceKey := nextKey()
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	for _, taskType := range taskTypes {
+		handler, ok := r.handlers[taskType]
+		if !ok {
+			return result, fmt.Errorf("no handler registered for task type %q", taskType)
+		}
+
+		step := Step{TaskType: taskType, ElementID: taskType}
+		retries := int32(DefaultRetries)
+		for attempt := 1; ; attempt++ {
+			if attempt > maxAttempts {
+				result.Steps = append(result.Steps, step)
+				return result, fmt.Errorf("%s still failing after %d attempts", taskType, maxAttempts)
+			}
+
+			job := NewJob(taskType, result.Variables,
+				WithRetries(retries),
+				WithProcess(r.ProcessID, instanceKey),
+			)
+			step.Jobs = append(step.Jobs, job)
+			handler(r.Client, job)
+
+			cmds := r.Client.ForJob(job.Key)
+			step.Commands = append(step.Commands, cmds...)
+			if len(cmds) == 0 {
+				result.Steps = append(result.Steps, step)
+				return result, fmt.Errorf("%s handler sent no command for job %d", taskType, job.Key)
+			}
+
+			cmd := cmds[len(cmds)-1]
+			switch cmd.Kind {
+			case Complete:
+				result.Variables = merge(result.Variables, cmd.Variables)
+			case ThrowError:
+				result.Thrown = &cmd
+			case Fail:
+				if cmd.Retries > 0 {
+					retries = cmd.Retries
+					step.Backoff += cmd.RetryBackoff
+					continue
+				}
+				result.Incident = &cmd
+			}
+			break
+		}
+
+		result.Steps = append(result.Steps, step)
+		if !result.Completed() {
+			break
+		}
+	}
+	return result, nil
+}
+
+// merge returns a copy of dst with the top-level keys of src set on it,
+// replacing whole values as the broker does when propagating variables.
+func merge(dst, src map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		out[k] = v
+	}
+	return out
+}
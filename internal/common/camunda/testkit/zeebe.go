@@ -0,0 +1,126 @@
+// internal/common/camunda/testkit/zeebe.go
+package testkit
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/commands"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
+	"google.golang.org/grpc"
+)
+
+// Activation is one ActivateJobs request a JobWorker sent.
+type Activation struct {
+	JobType string
+	MaxJobs int32
+}
+
+// Client is an in-memory zbc.Client for code that opens JobWorkers. Jobs
+// queued with Queue are activated by the JobWorker of their type, and the
+// commands their handlers send are recorded as by a JobClient. Every other
+// zbc.Client method is left to the embedded nil interface and panics if
+// called.
+type Client struct {
+	zbc.Client
+	*Recorder
+	jobs *JobClient
+
+	mu          sync.Mutex
+	queued      map[string][]*pb.ActivatedJob
+	activations []Activation
+}
+
+var _ zbc.Client = (*Client)(nil)
+
+// NewClient creates a Client with no queued jobs.
+func NewClient() *Client {
+	jobs := NewJobClient()
+	return &Client{Recorder: jobs.Recorder, jobs: jobs, queued: make(map[string][]*pb.ActivatedJob)}
+}
+
+// Queue adds jobs for the JobWorkers of their type to activate.
+func (c *Client) Queue(jobs ...entities.Job) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, job := range jobs {
+		c.queued[job.Type] = append(c.queued[job.Type], job.ActivatedJob)
+	}
+}
+
+// Queued returns how many jobs of jobType are waiting to be activated.
+func (c *Client) Queued(jobType string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queued[jobType])
+}
+
+// Activations returns every ActivateJobs request sent, in order.
+func (c *Client) Activations() []Activation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Activation(nil), c.activations...)
+}
+
+func (c *Client) NewJobWorker() worker.JobWorkerBuilderStep1 {
+	return worker.NewJobWorkerBuilder(&activator{client: c}, c.jobs, noRetry)
+}
+
+func (c *Client) NewCompleteJobCommand() commands.CompleteJobCommandStep1 {
+	return c.jobs.NewCompleteJobCommand()
+}
+
+func (c *Client) NewFailJobCommand() commands.FailJobCommandStep1 {
+	return c.jobs.NewFailJobCommand()
+}
+
+func (c *Client) NewThrowErrorCommand() commands.ThrowErrorCommandStep1 {
+	return c.jobs.NewThrowErrorCommand()
+}
+
+func (c *Client) Close() error { return nil }
+
+// activate removes up to max queued jobs of jobType.
+func (c *Client) activate(jobType string, max int32) []*pb.ActivatedJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.activations = append(c.activations, Activation{JobType: jobType, MaxJobs: max})
+
+	queued := c.queued[jobType]
+	n := int(max)
+	if n > len(queued) {
+		n = len(queued)
+	}
+	c.queued[jobType] = queued[n:]
+	return queued[:n:n]
+}
+
+// activator implements ActivateJobs of pb.GatewayClient. Every other RPC is
+// left to the embedded nil interface and panics if called.
+type activator struct {
+	pb.GatewayClient
+	client *Client
+}
+
+func (a *activator) ActivateJobs(_ context.Context, in *pb.ActivateJobsRequest, _ ...grpc.CallOption) (pb.Gateway_ActivateJobsClient, error) {
+	return &activateStream{jobs: a.client.activate(in.Type, in.MaxJobsToActivate)}, nil
+}
+
+// activateStream answers with one response holding jobs, then io.EOF.
+type activateStream struct {
+	grpc.ClientStream
+	jobs []*pb.ActivatedJob
+	done bool
+}
+
+func (s *activateStream) Recv() (*pb.ActivateJobsResponse, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	s.done = true
+	return &pb.ActivateJobsResponse{Jobs: s.jobs}, nil
+}
@@ -10,9 +10,14 @@ type Config struct {
 	Database      DatabaseConfig          `mapstructure:"database"`
 	Template      TemplateConfig          `mapstructure:"template"`
 	Workers       map[string]WorkerConfig `mapstructure:"workers"`
+	Registry      RegistryConfig          `mapstructure:"registry"`
 	Auth          AuthConfig              `mapstructure:"auth"`
 	Integrations  IntegrationConfig       `mapstructure:"integrations"`
 	APIs          APIsConfig              `mapstructure:"apis"`
+	Conversation  ConversationConfig      `mapstructure:"conversation"`
+	Retrieval     RetrievalConfig         `mapstructure:"retrieval"`
+	Guardrails    GuardrailsConfig        `mapstructure:"guardrails"`
+	Usage         UsageConfig             `mapstructure:"usage"`
 	Logging       LoggingConfig           `mapstructure:"logging"`
 	Notifications NotificationConfig      `mapstructure:"notifications"`
 }
@@ -29,6 +34,7 @@ type CamundaConfig struct {
 	MaxJobsActive  int    `mapstructure:"max_jobs_active"`
 	Timeout        int    `mapstructure:"timeout"`         // milliseconds
 	RequestTimeout int    `mapstructure:"request_timeout"` // milliseconds
+	DrainTimeout   int    `mapstructure:"drain_timeout"`   // milliseconds, in-flight job drain on shutdown
 }
 
 type DatabaseConfig struct {
@@ -85,8 +91,20 @@ type RedisConfig struct {
 type WorkerConfig struct {
 	Enabled       bool `mapstructure:"enabled"`
 	MaxJobsActive int  `mapstructure:"max_jobs_active"`
-	Timeout       int  `mapstructure:"timeout"`     // milliseconds
-	MaxRetries    int  `mapstructure:"max_retries"` // For error handling
+	Timeout       int  `mapstructure:"timeout"`       // milliseconds
+	MaxRetries    int  `mapstructure:"max_retries"`   // For error handling
+	RetryBackoff  int  `mapstructure:"retry_backoff"` // milliseconds before the first retry, doubled per attempt
+}
+
+// RegistryConfig points the worker manager at the activity registry.
+type RegistryConfig struct {
+	Path    string `mapstructure:"path"`
+	BPMNDir string `mapstructure:"bpmn_dir"` // deployed BPMN checked for task types without a worker at startup
+
+	// Runtime checks of job variables and handler output against each
+	// activity's inputSchema/outputSchema: "strict", "warn" or "off".
+	InputValidation  string `mapstructure:"input_validation"`
+	OutputValidation string `mapstructure:"output_validation"`
 }
 
 // --- Specific Configuration Sections ---
@@ -160,14 +178,132 @@ type APIsConfig struct {
 		BaseURL string `mapstructure:"base_url"`
 		APIKey  string `mapstructure:"api_key"`
 		Timeout int    `mapstructure:"timeout"` // milliseconds
+
+		// IntentFallback classifies questions in parse-user-intent when
+		// the parse-intent endpoint fails or is slow.
+		IntentFallback struct {
+			Enabled       bool   `mapstructure:"enabled"`
+			After         int    `mapstructure:"after"`          // milliseconds the API has before the fallback answers
+			TrainingPath  string `mapstructure:"training_path"`  // labeled JSONL questions the naive Bayes model learns from
+			GazetteerPath string `mapstructure:"gazetteer_path"` // US states and cities recognized as locations
+		} `mapstructure:"intent_fallback"`
 	} `mapstructure:"genai"`
 
+	// LLM selects the provider llm-synthesis generates answers with.
+	LLM struct {
+		Provider string `mapstructure:"provider"` // genai (default), openai or ollama
+		BaseURL  string `mapstructure:"base_url"` // genai falls back to genai.base_url
+		APIKey   string `mapstructure:"api_key"`  // genai falls back to genai.api_key
+		Model    string `mapstructure:"model"`
+		Timeout  int    `mapstructure:"timeout"` // milliseconds
+
+		// Stream publishes tokens while the answer is generated, on Redis
+		// pub/sub (progress: redis) or as Zeebe messages (progress: zeebe).
+		Stream           bool   `mapstructure:"stream"`
+		Progress         string `mapstructure:"progress"`
+		ProgressInterval int    `mapstructure:"progress_interval"` // milliseconds between progress events
+
+		// Grounding checks answers against the data they were given and
+		// caps the confidence of answers with unsupported claims.
+		Grounding            bool    `mapstructure:"grounding"`
+		UngroundedConfidence float64 `mapstructure:"ungrounded_confidence"`
+
+		// AnswerCache reuses the answers of repeated questions about the
+		// same entities until a franchise they cite is updated.
+		AnswerCache struct {
+			TTL        int     `mapstructure:"ttl"`        // minutes answers are cached in redis; 0 disables
+			Similarity float64 `mapstructure:"similarity"` // embedding similarity at which a reworded question matches; 0 matches exact questions only
+			Candidates int     `mapstructure:"candidates"` // recent questions per entity set compared by similarity
+		} `mapstructure:"answer_cache"`
+	} `mapstructure:"llm"`
+
+	// WebSearch selects the search backend of enrich-web-search.
 	WebSearch struct {
-		BaseURL  string `mapstructure:"base_url"`
+		Provider string `mapstructure:"provider"` // google (default), bing, brave or searxng
+		BaseURL  string `mapstructure:"base_url"` // defaults to the provider's public API; required for searxng
 		APIKey   string `mapstructure:"api_key"`
-		EngineID string `mapstructure:"engine_id"`
-		Timeout  int    `mapstructure:"timeout"` // milliseconds
+		EngineID string `mapstructure:"engine_id"` // google only
+		Timeout  int    `mapstructure:"timeout"`   // milliseconds
+
+		CacheTTL     int               `mapstructure:"cache_ttl"`     // minutes results are cached in redis by normalized query; 0 disables
+		AllowDomains []string          `mapstructure:"allow_domains"` // when set, only results from these domains and their subdomains
+		DenyDomains  []string          `mapstructure:"deny_domains"`
+		Recency      map[string]string `mapstructure:"recency"` // primary intent -> day, week, month or year
+
+		// Deep fetches the top result pages, obeying robots.txt, and
+		// summarizes them with the apis.llm provider.
+		Deep struct {
+			Pages        int      `mapstructure:"pages"`          // result pages fetched per search; 0 disables
+			Intents      []string `mapstructure:"intents"`        // primary intents fetched for; empty means all
+			MaxPageBytes int      `mapstructure:"max_page_bytes"` // larger pages are read up to this size
+			Timeout      int      `mapstructure:"timeout"`        // milliseconds for fetching and summarizing
+			UserAgent    string   `mapstructure:"user_agent"`     // its product token selects the robots.txt group
+			Summarize    bool     `mapstructure:"summarize"`      // false keeps the start of the page text
+		} `mapstructure:"deep"`
 	} `mapstructure:"web_search"`
+
+	BFF struct {
+		CallbackURL string `mapstructure:"callback_url"`
+		APIKey      string `mapstructure:"api_key"`
+		Timeout     int    `mapstructure:"timeout"` // milliseconds
+	} `mapstructure:"bff"`
+}
+
+// ConversationConfig holds the conversation memory shared by
+// parse-user-intent and llm-synthesis, keyed by the conversationId job
+// variable.
+type ConversationConfig struct {
+	Store        string `mapstructure:"store"`         // redis or postgres; empty disables memory
+	TTL          int    `mapstructure:"ttl"`           // minutes a conversation is kept in redis after its last turn
+	TokenBudget  int    `mapstructure:"token_budget"`  // older turns are summarized beyond this many tokens
+	HistoryTurns int    `mapstructure:"history_turns"` // recent turns sent with each prompt
+}
+
+// RetrievalConfig holds the passage index query-internal-data searches for
+// the user question and the rag-reindex tool fills.
+type RetrievalConfig struct {
+	Enabled       bool    `mapstructure:"enabled"`
+	Index         string  `mapstructure:"index"`          // elasticsearch index of the passages
+	Provider      string  `mapstructure:"provider"`       // embedding provider; defaults to apis.llm.provider
+	Model         string  `mapstructure:"model"`          // embedding model
+	TopK          int     `mapstructure:"top_k"`          // passages returned per question
+	NumCandidates int     `mapstructure:"num_candidates"` // kNN candidates per shard
+	ChunkSize     int     `mapstructure:"chunk_size"`     // words per passage
+	ChunkOverlap  int     `mapstructure:"chunk_overlap"`  // words shared by consecutive passages
+	VectorBoost   float64 `mapstructure:"vector_boost"`   // weight of the kNN score
+	KeywordBoost  float64 `mapstructure:"keyword_boost"`  // weight of the BM25 score
+}
+
+// GuardrailsConfig holds the PII redaction, prompt-injection and answer
+// category checks of the AI pipeline.
+type GuardrailsConfig struct {
+	Enabled           bool     `mapstructure:"enabled"`
+	BlockedCategories []string `mapstructure:"blocked_categories"` // financial_guarantee, franchisor_contact, legal_advice; all when empty
+}
+
+// UsageConfig holds the accounting of GenAI and LLM calls: Prometheus
+// counters, the ai_usage table, and the daily budgets validate-subscription
+// enforces from it. Map keys are case-insensitive.
+type UsageConfig struct {
+	Enabled bool                   `mapstructure:"enabled"`
+	Store   string                 `mapstructure:"store"`  // postgres records every call in ai_usage; empty keeps the counters only
+	Prices  map[string]PriceConfig `mapstructure:"prices"` // by model, or by provider for calls that report no model
+	Budgets struct {
+		Tiers map[string]BudgetConfig `mapstructure:"tiers"` // daily limits of each user of a subscription tier
+		Users map[string]BudgetConfig `mapstructure:"users"` // per-user limits, replacing the tier's
+	} `mapstructure:"budgets"`
+}
+
+// PriceConfig is the price of a model in US dollars per million tokens.
+type PriceConfig struct {
+	Prompt     float64 `mapstructure:"prompt"`
+	Completion float64 `mapstructure:"completion"`
+}
+
+// BudgetConfig is a daily AI budget; a zero limit is unlimited.
+type BudgetConfig struct {
+	DailyTokens  int     `mapstructure:"daily_tokens"`
+	DailyCostUSD float64 `mapstructure:"daily_cost_usd"`
 }
 
 // NotificationConfig holds settings for the send-notification worker.
@@ -196,6 +332,13 @@ type LoggingConfig struct {
 type TemplateConfig struct {
 	TemplateRules TemplateRules `mapstructure:"template_rules"`
 	RegistryPath  string        `mapstructure:"registry_path"`
+
+	// Prompt templates for llm-synthesis, read from PromptsPath or, with
+	// prompts_source: postgres, the prompt_templates table. Neither keeps
+	// the built-in prompt.
+	PromptsSource string `mapstructure:"prompts_source"` // file (default) or postgres
+	PromptsPath   string `mapstructure:"prompts_path"`
+	PromptsReload int    `mapstructure:"prompts_reload"` // milliseconds between reloads
 }
 
 // TemplateRules holds template routing rules
@@ -192,6 +192,11 @@ func overrideEmptyConfig(cfg *Config) {
 			cfg.APIs.GenAI.APIKey = val
 		}
 	}
+	if cfg.APIs.LLM.APIKey == "" {
+		if val := os.Getenv("LLM_API_KEY"); val != "" {
+			cfg.APIs.LLM.APIKey = val
+		}
+	}
 	
 	// Web Search API
 	if cfg.APIs.WebSearch.APIKey == "" {
@@ -259,6 +264,9 @@ func applyDefaults(cfg *Config) {
 	if cfg.Camunda.RequestTimeout == 0 {
 		cfg.Camunda.RequestTimeout = 30000
 	}
+	if cfg.Camunda.DrainTimeout == 0 {
+		cfg.Camunda.DrainTimeout = 30000
+	}
 
 	// Database defaults
 	if cfg.Database.Postgres.MaxConnections == 0 {
@@ -287,6 +295,63 @@ func applyDefaults(cfg *Config) {
 		cfg.Logging.Output = "stdout"
 	}
 
+	// Activity registry defaults
+	if cfg.Registry.Path == "" {
+		cfg.Registry.Path = "configs/activity-registry.json"
+	}
+	if cfg.Registry.BPMNDir == "" {
+		cfg.Registry.BPMNDir = "bpmn"
+	}
+	if cfg.Registry.InputValidation == "" {
+		cfg.Registry.InputValidation = "strict"
+	}
+	if cfg.Registry.OutputValidation == "" {
+		cfg.Registry.OutputValidation = "strict"
+	}
+
+	// Conversation memory defaults
+	if cfg.Conversation.TTL == 0 {
+		cfg.Conversation.TTL = 1440
+	}
+	if cfg.Conversation.TokenBudget == 0 {
+		cfg.Conversation.TokenBudget = 2000
+	}
+	if cfg.Conversation.HistoryTurns == 0 {
+		cfg.Conversation.HistoryTurns = 6
+	}
+
+	// Answers with unsupported claims stay below select-template's 0.8
+	// threshold for ai-detailed.
+	if cfg.APIs.LLM.UngroundedConfidence == 0 {
+		cfg.APIs.LLM.UngroundedConfidence = 0.6
+	}
+	if cfg.APIs.LLM.AnswerCache.Candidates == 0 {
+		cfg.APIs.LLM.AnswerCache.Candidates = 50
+	}
+
+	// Retrieval defaults
+	if cfg.Retrieval.Index == "" {
+		cfg.Retrieval.Index = "franchise_passages"
+	}
+	if cfg.Retrieval.TopK == 0 {
+		cfg.Retrieval.TopK = 5
+	}
+	if cfg.Retrieval.NumCandidates == 0 {
+		cfg.Retrieval.NumCandidates = 50
+	}
+	if cfg.Retrieval.ChunkSize == 0 {
+		cfg.Retrieval.ChunkSize = 200
+	}
+	if cfg.Retrieval.ChunkOverlap == 0 {
+		cfg.Retrieval.ChunkOverlap = 40
+	}
+	if cfg.Retrieval.VectorBoost == 0 {
+		cfg.Retrieval.VectorBoost = 1
+	}
+	if cfg.Retrieval.KeywordBoost == 0 {
+		cfg.Retrieval.KeywordBoost = 1
+	}
+
 	// Worker defaults - CRITICAL FIX!
 	for key, worker := range cfg.Workers {
 		if worker.MaxJobsActive == 0 {
@@ -298,6 +363,9 @@ func applyDefaults(cfg *Config) {
 		if worker.MaxRetries == 0 {
 			worker.MaxRetries = 3
 		}
+		if worker.RetryBackoff == 0 {
+			worker.RetryBackoff = 1000
+		}
 		cfg.Workers[key] = worker
 	}
 
@@ -305,9 +373,30 @@ func applyDefaults(cfg *Config) {
 	if cfg.APIs.GenAI.Timeout == 0 {
 		cfg.APIs.GenAI.Timeout = 60000
 	}
+	if cfg.APIs.GenAI.IntentFallback.After == 0 {
+		cfg.APIs.GenAI.IntentFallback.After = 5000
+	}
+	if cfg.APIs.GenAI.IntentFallback.TrainingPath == "" {
+		cfg.APIs.GenAI.IntentFallback.TrainingPath = "configs/intent-training.jsonl"
+	}
+	if cfg.APIs.GenAI.IntentFallback.GazetteerPath == "" {
+		cfg.APIs.GenAI.IntentFallback.GazetteerPath = "configs/us-gazetteer.json"
+	}
+	if cfg.APIs.LLM.Provider == "" {
+		cfg.APIs.LLM.Provider = "genai"
+	}
 	if cfg.APIs.WebSearch.Timeout == 0 {
 		cfg.APIs.WebSearch.Timeout = 10000
 	}
+	if cfg.APIs.WebSearch.Deep.MaxPageBytes == 0 {
+		cfg.APIs.WebSearch.Deep.MaxPageBytes = 1 << 20
+	}
+	if cfg.APIs.WebSearch.Deep.Timeout == 0 {
+		cfg.APIs.WebSearch.Deep.Timeout = 5000
+	}
+	if cfg.APIs.BFF.Timeout == 0 {
+		cfg.APIs.BFF.Timeout = 10000
+	}
 }
 
 // validateConfig validates critical configuration fields
@@ -344,8 +433,10 @@ func GetDuration(milliseconds int) time.Duration {
 
 // GetWorkerConfig retrieves worker-specific configuration with fallback to defaults
 func GetWorkerConfig(cfg *Config, workerName string) WorkerConfig {
-	if worker, exists := cfg.Workers[workerName]; exists {
-		return worker
+	if cfg != nil {
+		if worker, exists := cfg.Workers[workerName]; exists {
+			return worker
+		}
 	}
 
 	// Return default worker config if not found
@@ -354,6 +445,7 @@ func GetWorkerConfig(cfg *Config, workerName string) WorkerConfig {
 		MaxJobsActive: 5,
 		Timeout:       30000,
 		MaxRetries:    3,
+		RetryBackoff:  1000,
 	}
 }
 
@@ -0,0 +1,301 @@
+// internal/common/contract/contract.go
+package contract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/validation"
+	"camunda-workers/pkg/registry"
+)
+
+// RegistryEnv overrides the registry file used by contract tests.
+const RegistryEnv = "ACTIVITY_REGISTRY"
+
+// registryFile is the registry path relative to the module root.
+const registryFile = "configs/activity-registry.json"
+
+// Fixture wires a worker's handler to fake dependencies for the contract
+// tests generated by worker-generator. In and Out are the handler's input
+// and output types.
+type Fixture[In any, Out any] struct {
+	// Prepare adjusts the sample input generated from the inputSchema before
+	// it is decoded, for values the schema cannot express (IDs the fakes
+	// expect, patterns). Optional.
+	Prepare func(sample map[string]interface{})
+
+	// Execute runs the handler with fakes set up for success.
+	Execute func(t *testing.T, ctx context.Context, input *In) (Out, error)
+
+	// ErrorCases provoke each declared error code. Every case sets up its
+	// own fakes, runs the handler and returns its error.
+	ErrorCases map[string]func(t *testing.T, ctx context.Context, input *In) error
+
+	// Fallback and Mappings mirror the worker's camunda.MapErrors middleware
+	// so that sentinel errors are converted exactly as in production.
+	Fallback errors.ErrorCode
+	Mappings []camunda.ErrorMapping
+}
+
+// RoundTrip decodes a sample input generated from the activity's inputSchema
+// into In, runs the handler and checks the output against the outputSchema.
+// It fails when In has no field for an inputSchema property, when Out has no
+// field for an outputSchema property and when the output carries fields the
+// outputSchema does not declare.
+func RoundTrip[In any, Out any](t *testing.T, activityID string, fx *Fixture[In, Out]) {
+	t.Helper()
+	activity := Activity(t, activityID)
+
+	input := sampleInput(t, activity, fx, true)
+	output, err := fx.Execute(t, context.Background(), input)
+	if err != nil {
+		t.Fatalf("handler failed on sample input: %v", err)
+	}
+
+	for _, name := range missingFields(reflect.TypeOf(output), properties(activity.OutputSchema)) {
+		t.Errorf("outputSchema property %q has no field in %T", name, output)
+	}
+
+	schema, err := validation.SchemaFromMap(activity.OutputSchema)
+	if err != nil {
+		t.Fatalf("invalid outputSchema for %s: %v", activity.ID, err)
+	}
+	if schema == nil {
+		return
+	}
+	strict := *schema
+	strict.AdditionalProperties = false
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		t.Fatalf("encode output: %v", err)
+	}
+	variables := make(map[string]interface{})
+	if err := json.Unmarshal(data, &variables); err != nil {
+		t.Fatalf("output is not a JSON object: %v", err)
+	}
+	for _, msg := range validation.ValidateInput(variables, strict).GetErrorMessages() {
+		t.Errorf("output does not match outputSchema: %s", msg)
+	}
+}
+
+// ErrorCodes runs the fixture's error case for every code the activity
+// declares and checks that it ends in that code. declared is the list the
+// test was generated from; the test fails when the registry has changed
+// since, so that the contract test is regenerated.
+func ErrorCodes[In any, Out any](t *testing.T, activityID string, fx *Fixture[In, Out], declared ...string) {
+	t.Helper()
+	activity := Activity(t, activityID)
+
+	if !sameSet(activity.ErrorCodes, declared) {
+		t.Fatalf("errorCodes of %s changed from %v to %v; regenerate the contract test with worker-generator -contract",
+			activity.ID, declared, activity.ErrorCodes)
+	}
+
+	for code := range fx.ErrorCases {
+		if !contains(declared, code) {
+			t.Errorf("fixture provokes %s, which %s does not declare in errorCodes", code, activity.ID)
+		}
+	}
+
+	for _, code := range declared {
+		code := code
+		t.Run(code, func(t *testing.T) {
+			provoke, ok := fx.ErrorCases[code]
+			if !ok {
+				t.Fatalf("no error case for %s in the contract fixture", code)
+			}
+
+			input := sampleInput(t, activity, fx, false)
+			err := provoke(t, context.Background(), input)
+			if err == nil {
+				t.Fatalf("error case for %s returned no error", code)
+			}
+			if got := mapError(err, fx.Fallback, fx.Mappings); got != code {
+				t.Errorf("error case for %s ended in %q: %v", code, got, err)
+			}
+		})
+	}
+}
+
+// Activity returns the registry entry with the given ID.
+func Activity(t testing.TB, activityID string) registry.Activity {
+	t.Helper()
+
+	path, err := registryPath()
+	if err != nil {
+		t.Fatalf("locate activity registry: %v", err)
+	}
+	reg, err := registry.LoadRegistry(path)
+	if err != nil {
+		t.Fatalf("load activity registry: %v", err)
+	}
+	for _, a := range reg.Activities {
+		if a.ID == activityID {
+			return a
+		}
+	}
+	t.Fatalf("activity %s not found in %s", activityID, path)
+	return registry.Activity{}
+}
+
+// sampleInput builds the sample for the activity, applies the fixture's
+// Prepare hook and decodes it into a new In. With strict set, properties
+// that In cannot hold fail the test.
+func sampleInput[In any, Out any](t *testing.T, activity registry.Activity, fx *Fixture[In, Out], strict bool) *In {
+	t.Helper()
+
+	sample, _ := Sample(activity.InputSchema).(map[string]interface{})
+	if sample == nil {
+		sample = make(map[string]interface{})
+	}
+	if fx.Prepare != nil {
+		fx.Prepare(sample)
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		t.Fatalf("encode sample input: %v", err)
+	}
+
+	input := new(In)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(input); err != nil {
+		t.Fatalf("sample input %s does not decode into %T: %v", data, input, err)
+	}
+	return input
+}
+
+// mapError returns the error code the worker's pipeline reports for err.
+func mapError(err error, fallback errors.ErrorCode, mappings []camunda.ErrorMapping) string {
+	fail := func(context.Context, *camunda.Job) error { return err }
+	mapped := camunda.Chain(fail, camunda.MapErrors(fallback, mappings...))(context.Background(), &camunda.Job{})
+
+	var stdErr *errors.StandardError
+	if stderrors.As(mapped, &stdErr) {
+		return string(stdErr.Code)
+	}
+	return ""
+}
+
+// registryPath finds the activity registry in RegistryEnv or by walking up
+// from the working directory to the module root.
+func registryPath() (string, error) {
+	if path := os.Getenv(RegistryEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		candidate := filepath.Join(dir, registryFile)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s not found above the working directory", registryFile)
+		}
+		dir = parent
+	}
+}
+
+// properties returns the property names of a JSON schema object.
+func properties(schema map[string]interface{}) []string {
+	props, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// missingFields returns the names that have no JSON field in struct type t.
+// Non-struct types hold any property and report nothing.
+func missingFields(t reflect.Type, names []string) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := jsonFields(t)
+	var missing []string
+	for _, name := range names {
+		if !fields[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n := range jsonFields(ft) {
+					fields[n] = true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, v := range a {
+		if !contains(b, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
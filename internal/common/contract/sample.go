@@ -0,0 +1,138 @@
+// internal/common/contract/sample.go
+package contract
+
+import (
+	"sort"
+	"strings"
+)
+
+// Sample values for string formats used in the activity registry.
+var formatSamples = map[string]string{
+	"email":     "contract.test@example.com",
+	"uri":       "https://example.com/contract",
+	"url":       "https://example.com/contract",
+	"uuid":      "00000000-0000-4000-8000-000000000001",
+	"date":      "2024-01-01",
+	"date-time": "2024-01-01T00:00:00Z",
+}
+
+// Sample returns a value that satisfies the given JSON schema: every declared
+// property is filled in, enums take their first value, defaults are used when
+// present and numbers and lengths respect their bounds. Patterns are not
+// inverted; use the fixture's Prepare hook for patterned strings.
+func Sample(schema map[string]interface{}) interface{} {
+	if len(schema) == 0 {
+		return nil
+	}
+	if v, ok := schema["default"]; ok {
+		return v
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		obj := make(map[string]interface{})
+		props, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(props))
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, _ := props[name].(map[string]interface{})
+			obj[name] = sampleNamed(name, prop)
+		}
+		return obj
+
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		n := 1
+		if min, ok := number(schema["minItems"]); ok && int(min) > n {
+			n = int(min)
+		}
+		arr := make([]interface{}, n)
+		for i := range arr {
+			arr[i] = Sample(items)
+		}
+		return arr
+
+	case "string":
+		return sampleString(schema, "sample")
+
+	case "integer", "number":
+		v := 1.0
+		if min, ok := number(schema["minimum"]); ok && v < min {
+			v = min
+		}
+		if min, ok := number(schema["exclusiveMinimum"]); ok && v <= min {
+			v = min + 1
+		}
+		if max, ok := number(schema["maximum"]); ok && v > max {
+			v = max
+		}
+		return v
+
+	case "boolean":
+		return true
+	}
+	return nil
+}
+
+// sampleNamed samples a property, using its name for readable strings.
+func sampleNamed(name string, schema map[string]interface{}) interface{} {
+	if schemaType(schema) == "string" {
+		if _, ok := schema["enum"]; !ok {
+			if _, ok := schema["default"]; !ok {
+				return sampleString(schema, "sample-"+name)
+			}
+		}
+	}
+	return Sample(schema)
+}
+
+func sampleString(schema map[string]interface{}, base string) string {
+	format, _ := schema["format"].(string)
+	if s, ok := formatSamples[format]; ok {
+		return s
+	}
+
+	s := base
+	if min, ok := number(schema["minLength"]); ok && len(s) < int(min) {
+		s += strings.Repeat("x", int(min)-len(s))
+	}
+	if max, ok := number(schema["maxLength"]); ok && len(s) > int(max) {
+		s = s[:int(max)]
+	}
+	return s
+}
+
+// schemaType returns the schema's type, the first non-null one for a list of
+// types, or "object" when only properties are given.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
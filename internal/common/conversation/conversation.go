@@ -0,0 +1,192 @@
+// internal/common/conversation/conversation.go
+package conversation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Store kinds selectable with conversation.store.
+const (
+	StoreRedis    = "redis"
+	StorePostgres = "postgres"
+)
+
+// Turn roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Turn is one message of a conversation.
+type Turn struct {
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	At      time.Time `json:"at"`
+}
+
+// Entity is an entity resolved while parsing a question, e.g. the franchise
+// a follow-up like "what about in Texas?" refers to.
+type Entity struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Conversation is what is remembered between the questions of a
+// conversation. Turns folded into Summary are dropped.
+type Conversation struct {
+	ID           string    `json:"id"`
+	Turns        []Turn    `json:"turns"`
+	Summary      string    `json:"summary,omitempty"`
+	Entities     []Entity  `json:"entities"`
+	FranchiseIDs []string  `json:"franchiseIds"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// Store loads and saves conversations. Load returns an empty conversation
+// for an unknown ID.
+type Store interface {
+	Load(ctx context.Context, id string) (*Conversation, error)
+	Save(ctx context.Context, conv *Conversation) error
+}
+
+// ErrNoConversationID is returned when saving a conversation without ID.
+var ErrNoConversationID = errors.New("conversation id is required")
+
+// AddTurn appends a turn.
+func (c *Conversation) AddTurn(role, content string) {
+	c.Turns = append(c.Turns, Turn{Role: role, Content: content, At: time.Now().UTC()})
+}
+
+// MergeEntities records newly resolved entities. An entity replaces the
+// earlier one of the same type, so the latest franchise or location is the
+// one later questions refer to.
+func (c *Conversation) MergeEntities(entities []Entity) {
+	for _, e := range entities {
+		if e.Value == "" {
+			continue
+		}
+		replaced := false
+		for i := range c.Entities {
+			if c.Entities[i].Type == e.Type {
+				c.Entities[i] = e
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Entities = append(c.Entities, e)
+		}
+	}
+}
+
+// AddFranchiseIDs records franchises cited in an answer, once each.
+func (c *Conversation) AddFranchiseIDs(ids ...string) {
+	for _, id := range ids {
+		if id != "" && !contains(c.FranchiseIDs, id) {
+			c.FranchiseIDs = append(c.FranchiseIDs, id)
+		}
+	}
+}
+
+// History returns the most recent turns, at most maxTurns of them and at
+// most maxTokens tokens together. Zero disables a limit.
+func (c *Conversation) History(maxTurns, maxTokens int) []Turn {
+	start := len(c.Turns)
+	tokens := 0
+	for start > 0 {
+		if maxTurns > 0 && len(c.Turns)-start >= maxTurns {
+			break
+		}
+		t := EstimateTokens(c.Turns[start-1].Content)
+		if maxTokens > 0 && tokens+t > maxTokens {
+			break
+		}
+		tokens += t
+		start--
+	}
+	return c.Turns[start:]
+}
+
+// Tokens estimates the size of the summary and turns.
+func (c *Conversation) Tokens() int {
+	n := EstimateTokens(c.Summary)
+	for _, t := range c.Turns {
+		n += EstimateTokens(t.Content)
+	}
+	return n
+}
+
+// EstimateTokens approximates the token count of s at four characters per
+// token, close enough for English text to keep prompts within budget.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// Summarizer folds turns into an existing summary.
+type Summarizer func(ctx context.Context, summary string, turns []Turn) (string, error)
+
+// Compact summarizes the oldest turns once the conversation exceeds
+// budget tokens, keeping the last keep turns verbatim. It reports whether
+// it changed the conversation. When summarize fails, or is nil, the turns
+// are folded in with ExtractiveSummary instead.
+func Compact(ctx context.Context, c *Conversation, budget, keep int, summarize Summarizer) bool {
+	if budget <= 0 || c.Tokens() <= budget || len(c.Turns) <= keep {
+		return false
+	}
+	old := c.Turns[:len(c.Turns)-keep]
+
+	summary := ""
+	if summarize != nil {
+		if s, err := summarize(ctx, c.Summary, old); err == nil {
+			summary = strings.TrimSpace(s)
+		}
+	}
+	if summary == "" {
+		summary = ExtractiveSummary(c.Summary, old, budget/2)
+	}
+
+	c.Summary = summary
+	c.Turns = append([]Turn(nil), c.Turns[len(c.Turns)-keep:]...)
+	return true
+}
+
+// ExtractiveSummary appends one line per turn, cut to its first sentence, to
+// summary and keeps the last maxTokens tokens of the result.
+func ExtractiveSummary(summary string, turns []Turn, maxTokens int) string {
+	lines := []string{}
+	if summary != "" {
+		lines = append(lines, summary)
+	}
+	for _, t := range turns {
+		lines = append(lines, fmt.Sprintf("%s: %s", t.Role, firstSentence(t.Content)))
+	}
+	out := strings.Join(lines, "\n")
+	if limit := maxTokens * 4; maxTokens > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+		if i := strings.IndexByte(out, '\n'); i >= 0 {
+			out = out[i+1:]
+		}
+	}
+	return out
+}
+
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if i := strings.IndexAny(s, ".?!"); i >= 0 && i < len(s)-1 {
+		return s[:i+1]
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
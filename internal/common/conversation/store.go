@@ -0,0 +1,133 @@
+// internal/common/conversation/store.go
+package conversation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps each conversation as JSON under conversation:<id>,
+// expiring ttl after its last save.
+type RedisStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+func NewRedisStore(client redis.UniversalClient, ttl time.Duration) *RedisStore {
+	return &RedisStore{client: client, ttl: ttl}
+}
+
+// Key is the Redis key of a conversation.
+func Key(id string) string {
+	return "conversation:" + id
+}
+
+func (s *RedisStore) Load(ctx context.Context, id string) (*Conversation, error) {
+	data, err := s.client.Get(ctx, Key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return &Conversation{ID: id}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load conversation %s: %w", id, err)
+	}
+	return decode(id, data)
+}
+
+func (s *RedisStore) Save(ctx context.Context, conv *Conversation) error {
+	data, err := encode(conv)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Set(ctx, Key(conv.ID), data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("save conversation %s: %w", conv.ID, err)
+	}
+	return nil
+}
+
+// PostgresStore keeps conversations in the ai_conversations table:
+//
+//	CREATE TABLE ai_conversations (
+//	    id         TEXT PRIMARY KEY,
+//	    data       JSONB NOT NULL,
+//	    updated_at TIMESTAMPTZ NOT NULL
+//	);
+//
+// Expiry is left to a scheduled cleanup on updated_at.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Load(ctx context.Context, id string) (*Conversation, error) {
+	var data []byte
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM ai_conversations WHERE id = $1`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Conversation{ID: id}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load conversation %s: %w", id, err)
+	}
+	return decode(id, data)
+}
+
+func (s *PostgresStore) Save(ctx context.Context, conv *Conversation) error {
+	data, err := encode(conv)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO ai_conversations (id, data, updated_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at`,
+		conv.ID, data, conv.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("save conversation %s: %w", conv.ID, err)
+	}
+	return nil
+}
+
+// NewStore returns the store of the given kind, or nil when kind is empty.
+// The client of the selected kind must not be nil.
+func NewStore(kind string, rdb redis.UniversalClient, db *sql.DB, ttl time.Duration) (Store, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case StoreRedis:
+		if rdb == nil {
+			return nil, errors.New("conversation store redis: redis is not configured")
+		}
+		return NewRedisStore(rdb, ttl), nil
+	case StorePostgres:
+		if db == nil {
+			return nil, errors.New("conversation store postgres: postgres is not configured")
+		}
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown conversation store %q", kind)
+	}
+}
+
+func encode(conv *Conversation) ([]byte, error) {
+	if conv.ID == "" {
+		return nil, ErrNoConversationID
+	}
+	conv.UpdatedAt = time.Now().UTC()
+	return json.Marshal(conv)
+}
+
+func decode(id string, data []byte) (*Conversation, error) {
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("decode conversation %s: %w", id, err)
+	}
+	conv.ID = id
+	return &conv, nil
+}
@@ -407,6 +407,26 @@ func NewAuthenticationError(details string) *StandardError {
 	}
 }
 
+func NewInputParsingFailedError(err error) *StandardError {
+	return &StandardError{
+		Code:      "INPUT_PARSING_FAILED",
+		Message:   "Failed to parse job variables",
+		Details:   err.Error(),
+		Retryable: false,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+func NewInternalError(details string) *StandardError {
+	return &StandardError{
+		Code:      "INTERNAL_ERROR",
+		Message:   "Unexpected error",
+		Details:   details,
+		Retryable: false,
+		Timestamp: time.Now().UTC(),
+	}
+}
+
 // ==========================
 // 4. Error Conversion to BPMN
 // ==========================
@@ -478,16 +498,21 @@ func ConvertToBPMNError(stdErr *StandardError) *BPMNError {
 		retries = 0
 	}
 
+	// Metadata such as validation field errors is passed on to the workflow.
+	variables := make(map[string]interface{}, len(stdErr.Metadata)+2)
+	for k, v := range stdErr.Metadata {
+		variables[k] = v
+	}
+	variables["originalErrorCode"] = string(stdErr.Code)
+	variables["timestamp"] = stdErr.Timestamp.Format(time.RFC3339)
+
 	return &BPMNError{
-		Code:      bpmnCode,
-		Message:   stdErr.Message,
-		Details:   stdErr.Details,
-		Retryable: stdErr.Retryable,
-		Retries:   retries,
-		ErrorVariables: map[string]interface{}{
-			"originalErrorCode": string(stdErr.Code),
-			"timestamp":         stdErr.Timestamp.Format(time.RFC3339),
-		},
+		Code:           bpmnCode,
+		Message:        stdErr.Message,
+		Details:        stdErr.Details,
+		Retryable:      stdErr.Retryable,
+		Retries:        retries,
+		ErrorVariables: variables,
 	}
 }
 
@@ -4,6 +4,8 @@ package errors
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
+	"fmt"
 	"time"
 
 	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
@@ -13,17 +15,61 @@ import (
 // ErrorHandler handles job errors with standardized error handling
 type ErrorHandler struct {
 	logger Logger
+	policy RetryPolicy
 }
 
 type Logger interface {
 	Error(msg string, fields map[string]interface{})
 }
 
+// RetryPolicy controls how transient failures are handed back to the broker.
+type RetryPolicy struct {
+	// MaxRetries is the retry count a task starts with; together with the
+	// job's remaining retries it gives the number of failed attempts so far.
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy matches the default `workers:` max_retries of 3.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  1 * time.Second,
+	MaxDelay:   60 * time.Second,
+}
+
+// Backoff returns the delay before the next attempt, doubling with every
+// attempt that has already failed.
+func (p RetryPolicy) Backoff(remainingRetries int32) time.Duration {
+	attempt := p.MaxRetries - int(remainingRetries)
+	if attempt < 0 {
+		attempt = 0
+	}
+	if attempt > 16 {
+		attempt = 16
+	}
+
+	delay := p.BaseDelay * time.Duration(1<<attempt)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
 func NewErrorHandler(logger Logger) *ErrorHandler {
-	return &ErrorHandler{logger: logger}
+	return NewErrorHandlerWithPolicy(logger, DefaultRetryPolicy)
 }
 
-// HandleJobError handles any error in a worker job
+// NewErrorHandlerWithPolicy creates an ErrorHandler that backs off transient
+// failures according to policy.
+func NewErrorHandlerWithPolicy(logger Logger, policy RetryPolicy) *ErrorHandler {
+	return &ErrorHandler{logger: logger, policy: policy}
+}
+
+// HandleJobError reports a failed job to the broker. Transient (retryable)
+// errors fail the job with one retry fewer and a backoff, so the broker
+// retries it and raises an incident once retries run out. Business errors are
+// thrown as BPMN errors for the process model to catch.
 func (h *ErrorHandler) HandleJobError(ctx context.Context, client worker.JobClient, job entities.Job, err error) {
 	// Normalize to StandardError
 	stdErr := h.normalizeError(err)
@@ -31,63 +77,70 @@ func (h *ErrorHandler) HandleJobError(ctx context.Context, client worker.JobClie
 	// Convert to BPMN error
 	bpmnErr := ConvertToBPMNError(stdErr)
 
-	// Log
-	h.logError(job, stdErr, bpmnErr)
-
-	// Decide: retry or throw
-	retries := GetRetryCount(stdErr.Code)
-	if retries > 0 && job.Retries > 0 {
+	if stdErr.Retryable {
+		retries := RemainingRetries(stdErr.Code, job.Retries)
+		h.logError(job, stdErr, bpmnErr, retries)
 		h.failJobWithRetries(ctx, client, job, bpmnErr, retries)
-	} else {
-		h.throwBPMNError(ctx, client, job, bpmnErr)
+		return
+	}
+
+	h.logError(job, stdErr, bpmnErr, 0)
+	h.throwBPMNError(ctx, client, job, bpmnErr)
+}
+
+// RemainingRetries returns the retries to leave on a job after a transient
+// failure: one fewer than the job has, capped by the code's retry budget
+// from GetRetryCount when it has one.
+func RemainingRetries(code ErrorCode, jobRetries int32) int32 {
+	remaining := jobRetries - 1
+	if budget := int32(GetRetryCount(code)); budget > 0 && budget < remaining {
+		remaining = budget
+	}
+	if remaining < 0 {
+		remaining = 0
 	}
+	return remaining
 }
 
 // normalizeError ensures we always have a StandardError
 func (h *ErrorHandler) normalizeError(err error) *StandardError {
-	if stdErr, ok := err.(*StandardError); ok {
+	var stdErr *StandardError
+	if stderrors.As(err, &stdErr) {
 		return stdErr
 	}
-	return &StandardError{
-		Code:      "INTERNAL_ERROR",
-		Message:   "Unexpected error",
-		Details:   err.Error(),
-		Retryable: false,
-		Timestamp: time.Now().UTC(), // FIXED: Use time.Now().UTC() instead of undefined Now()
-	}
+	return NewInternalError(err.Error())
 }
 
-func (h *ErrorHandler) failJobWithRetries(ctx context.Context, client worker.JobClient, job entities.Job, bpmnErr *BPMNError, maxRetries int) {
-	// Camunda uses job.Retries as total remaining — so set it to maxRetries
-	// But to avoid overriding, use min(job.Retries, maxRetries)
-	retriesToUse := maxRetries
-	if job.Retries > 0 && int(job.Retries) < maxRetries {
-		retriesToUse = int(job.Retries)
-	}
-
+func (h *ErrorHandler) failJobWithRetries(ctx context.Context, client worker.JobClient, job entities.Job, bpmnErr *BPMNError, retries int32) {
 	vars := bpmnErr.ToErrorVariables()
 	varsJSON, _ := json.Marshal(vars)
 
-	// FIXED: Chain commands properly without type assignment issues
 	cmd := client.NewFailJobCommand().
 		JobKey(job.Key).
-		Retries(int32(retriesToUse)).
-		ErrorMessage(bpmnErr.Message)
+		Retries(retries).
+		ErrorMessage(fmt.Sprintf("[%s] %s", bpmnErr.Code, bpmnErr.Message))
+
+	// Without retries left the broker raises an incident right away, so
+	// there is nothing to back off from.
+	if retries > 0 {
+		cmd = cmd.RetryBackoff(h.policy.Backoff(retries))
+	}
 
 	// Add error variables if available
 	if len(vars) > 0 {
 		if varsJSONStr := string(varsJSON); varsJSONStr != "null" {
-			// Use the variables command directly without type assignment
 			cmdWithVars, err := cmd.VariablesFromString(varsJSONStr)
 			if err == nil {
-				_, _ = cmdWithVars.Send(ctx)
+				_, sendErr := cmdWithVars.Send(ctx)
+				h.logSendError(job, "fail", sendErr)
 				return
 			}
 		}
 	}
 
 	// Fallback: send without variables if there was an issue
-	_, _ = cmd.Send(ctx)
+	_, sendErr := cmd.Send(ctx)
+	h.logSendError(job, "fail", sendErr)
 }
 
 func (h *ErrorHandler) throwBPMNError(ctx context.Context, client worker.JobClient, job entities.Job, bpmnErr *BPMNError) {
@@ -104,17 +157,19 @@ func (h *ErrorHandler) throwBPMNError(ctx context.Context, client worker.JobClie
 		if varsJSONStr := string(varsJSON); varsJSONStr != "null" {
 			cmdWithVars, err := cmd.VariablesFromString(varsJSONStr)
 			if err == nil {
-				_, _ = cmdWithVars.Send(ctx)
+				_, sendErr := cmdWithVars.Send(ctx)
+				h.logSendError(job, "throw error", sendErr)
 				return
 			}
 		}
 	}
 
 	// Fallback: send without variables if there was an issue
-	_, _ = cmd.Send(ctx)
+	_, sendErr := cmd.Send(ctx)
+	h.logSendError(job, "throw error", sendErr)
 }
 
-func (h *ErrorHandler) logError(job entities.Job, stdErr *StandardError, bpmnErr *BPMNError) {
+func (h *ErrorHandler) logError(job entities.Job, stdErr *StandardError, bpmnErr *BPMNError, retries int32) {
 	h.logger.Error("Job failed", map[string]interface{}{
 		"jobKey":           job.Key,
 		"jobType":          job.Type,
@@ -123,8 +178,19 @@ func (h *ErrorHandler) logError(job entities.Job, stdErr *StandardError, bpmnErr
 		"message":          bpmnErr.Message,
 		"details":          stdErr.Details,
 		"retryable":        stdErr.Retryable,
-		"retries":          GetRetryCount(stdErr.Code),
+		"retries":          retries,
 		"errorCategory":    GetErrorCategory(stdErr.Code),
 		"workflowInstance": job.ProcessInstanceKey,
 	})
 }
+
+func (h *ErrorHandler) logSendError(job entities.Job, command string, err error) {
+	if err == nil {
+		return
+	}
+	h.logger.Error("Failed to send "+command+" command", map[string]interface{}{
+		"jobKey":  job.Key,
+		"jobType": job.Type,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,48 @@
+// internal/common/genaistub/embed.go
+package genaistub
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+// EmbeddingDims is the length of the vectors the stub returns.
+const EmbeddingDims = 64
+
+// EmbedResponse is the body of a /api/ai/embed response.
+type EmbedResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// Embed hashes the words of text into a normalized bag-of-words vector, so
+// texts sharing words are close without a model.
+func Embed(text string) []float32 {
+	v := make([]float32, EmbeddingDims)
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		h := fnv.New32a()
+		h.Write([]byte(w))
+		v[h.Sum32()%EmbeddingDims]++
+	}
+	var norm float64
+	for _, x := range v {
+		norm += float64(x * x)
+	}
+	if norm > 0 {
+		n := float32(math.Sqrt(norm))
+		for i := range v {
+			v[i] /= n
+		}
+	}
+	return v
+}
+
+func (s *Server) embed(body map[string]interface{}) interface{} {
+	input, _ := body["input"].([]interface{})
+	resp := EmbedResponse{Embeddings: make([][]float32, 0, len(input))}
+	for _, item := range input {
+		text, _ := item.(string)
+		resp.Embeddings = append(resp.Embeddings, Embed(text))
+	}
+	return resp
+}
@@ -0,0 +1,152 @@
+// internal/common/genaistub/generate.go
+package genaistub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GenerateResponse is the body of a /api/ai/generate response.
+type GenerateResponse struct {
+	Text       string   `json:"text"`
+	Confidence float64  `json:"confidence"`
+	Sources    []string `json:"sources"`
+}
+
+// generate answers without a model: it restates the question and echoes the
+// internal data, passages and web sources it was given, so tests can check what
+// reached the service.
+func (s *Server) generate(body map[string]interface{}) interface{} {
+	prompt, _ := body["prompt"].(string)
+	ctx, _ := body["context"].(map[string]interface{})
+	internal, _ := ctx["internal"].(map[string]interface{})
+	external, _ := ctx["external"].(map[string]interface{})
+	webSources, _ := external["sources"].([]interface{})
+	passages, _ := ctx["passages"].([]interface{})
+
+	var text strings.Builder
+	if q := question(prompt); q != "" {
+		fmt.Fprintf(&text, "Re: %s", q)
+	} else {
+		text.WriteString("Re: your question")
+	}
+
+	sources := []string{}
+	keys := make([]string, 0, len(internal))
+	for k := range internal {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		text.WriteString(" Internal data:")
+		for _, k := range keys {
+			fmt.Fprintf(&text, " %s=%s;", k, compact(internal[k]))
+			sources = append(sources, "internal:"+k)
+		}
+	}
+
+	if len(passages) > 0 {
+		text.WriteString(" Passages:")
+		for _, p := range passages {
+			m, _ := p.(map[string]interface{})
+			id, _ := m["id"].(string)
+			passage, _ := m["text"].(string)
+			fmt.Fprintf(&text, " [%s] %s;", id, passage)
+			sources = append(sources, id)
+		}
+	}
+
+	if len(webSources) > 0 {
+		text.WriteString(" Web sources:")
+		for _, src := range webSources {
+			m, _ := src.(map[string]interface{})
+			title, _ := m["title"].(string)
+			url, _ := m["url"].(string)
+			fmt.Fprintf(&text, " %s;", title)
+			if url != "" {
+				sources = append(sources, url)
+			}
+		}
+	}
+
+	grounded := len(keys) > 0 || len(passages) > 0
+	confidence := 0.2
+	switch {
+	case grounded && len(webSources) > 0:
+		confidence = 0.85
+	case grounded || len(webSources) > 0:
+		confidence = 0.6
+	default:
+		text.WriteString(" I don't have enough information to answer that question.")
+	}
+
+	return GenerateResponse{
+		Text:       strings.TrimSuffix(text.String(), ";"),
+		Confidence: confidence,
+		Sources:    sources,
+	}
+}
+
+// question returns the "User Question:" line of an llm-synthesis prompt.
+func question(prompt string) string {
+	for _, line := range strings.Split(prompt, "\n") {
+		if q, ok := strings.CutPrefix(strings.TrimSpace(line), "User Question:"); ok {
+			return strings.TrimSpace(q)
+		}
+	}
+	return ""
+}
+
+func compact(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// streamGenerate sends resp as server-sent events, one word per event,
+// followed by a done event with confidence, sources and usage; tokens are
+// counted as words. A malformed stream breaks off in the middle of an event.
+func (s *Server) streamGenerate(w http.ResponseWriter, r *http.Request, resp GenerateResponse, promptTokens int, malformed bool) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	delay := time.Duration(s.Faults().ChunkDelayMs) * time.Millisecond
+
+	words := strings.SplitAfter(resp.Text, " ")
+	for i, word := range words {
+		if malformed && i == len(words)/2 {
+			fmt.Fprintf(w, "data: {\"delta\": %q\n\n", word)
+			return
+		}
+		data, _ := json.Marshal(map[string]string{"delta": word})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if !sleep(r.Context(), delay) {
+			return
+		}
+	}
+
+	done, _ := json.Marshal(map[string]interface{}{
+		"done":          true,
+		"confidence":    resp.Confidence,
+		"sources":       resp.Sources,
+		"finish_reason": "stop",
+		"usage": map[string]int{
+			"prompt_tokens":     promptTokens,
+			"completion_tokens": len(words),
+		},
+	})
+	fmt.Fprintf(w, "data: %s\n\n", done)
+}
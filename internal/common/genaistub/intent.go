@@ -0,0 +1,164 @@
+// internal/common/genaistub/intent.go
+package genaistub
+
+import (
+	"regexp"
+	"strings"
+)
+
+// IntentRule maps keywords in a query to an intent. Single words match whole
+// words; phrases match as substrings.
+type IntentRule struct {
+	Intent      string   `json:"intent"`
+	Keywords    []string `json:"keywords"`
+	Confidence  float64  `json:"confidence"`
+	DataSources []string `json:"dataSources"`
+}
+
+// fallbackIntent answers queries no rule matches.
+var fallbackIntent = IntentRule{Intent: "general_info", Confidence: 0.4}
+
+// DefaultIntentRules cover the intents parse-user-intent knows about. Rules
+// without DataSources leave the choice to the worker. Greeting comes last so
+// that "hi, what does X cost?" is a cost inquiry.
+var DefaultIntentRules = []IntentRule{
+	{Intent: "cost_inquiry", Keywords: []string{"cost", "costs", "price", "fee", "fees", "investment", "invest", "how much", "capital", "royalty"}, Confidence: 0.9},
+	{Intent: "location_inquiry", Keywords: []string{"where", "location", "locations", "near", "territory", "city", "area"}, Confidence: 0.85},
+	{Intent: "competitor_analysis", Keywords: []string{"competitor", "competitors", "compare", "versus", "vs", "alternative", "alternatives"}, Confidence: 0.85},
+	{Intent: "market_research", Keywords: []string{"market", "trend", "trends", "growth", "demand", "industry", "outlook"}, Confidence: 0.8},
+	{Intent: "category_search", Keywords: []string{"category", "categories", "type of", "kinds of", "list", "show me"}, Confidence: 0.8},
+	{Intent: "franchise_inquiry", Keywords: []string{"franchise", "franchises", "franchisor", "requirements", "apply", "open a"}, Confidence: 0.75},
+	{Intent: "greeting", Keywords: []string{"hello", "hi", "hey", "good morning", "good afternoon"}, Confidence: 0.95},
+}
+
+var (
+	investmentPattern = regexp.MustCompile(`\$\s?\d[\d,]*(?:\.\d+)?\s?(?:k|m|thousand|million)?\b`)
+	quotedPattern     = regexp.MustCompile(`"([^"]+)"`)
+	wordPattern       = regexp.MustCompile(`[a-z0-9']+`)
+)
+
+// Gazetteers the entity extraction looks values up in.
+var (
+	knownLocations = []string{
+		"new york", "los angeles", "chicago", "houston", "phoenix", "dallas", "austin",
+		"miami", "seattle", "denver", "boston", "atlanta", "san francisco", "texas",
+		"california", "florida", "london", "toronto",
+	}
+	knownCategories = []string{
+		"coffee", "food", "fast food", "restaurant", "fitness", "education", "cleaning",
+		"retail", "beauty", "automotive", "pet", "health", "home services", "childcare",
+	}
+)
+
+// Entity is an entity found in a query.
+type Entity struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// IntentResponse is the body of a /api/ai/parse-intent response.
+type IntentResponse struct {
+	Intent      string   `json:"intent"`
+	Confidence  float64  `json:"confidence"`
+	Entities    []Entity `json:"entities"`
+	DataSources []string `json:"dataSources,omitempty"`
+}
+
+// ClassifyIntent picks the rule with the most keyword matches; the first
+// rule wins a tie.
+func ClassifyIntent(rules []IntentRule, query string) IntentRule {
+	text := strings.ToLower(query)
+	words := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(text, -1) {
+		words[w] = true
+	}
+
+	best, bestHits := fallbackIntent, 0
+	for _, rule := range rules {
+		hits := 0
+		for _, kw := range rule.Keywords {
+			kw = strings.ToLower(kw)
+			if strings.Contains(kw, " ") {
+				if strings.Contains(text, kw) {
+					hits++
+				}
+			} else if words[kw] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = rule, hits
+		}
+	}
+	return best
+}
+
+// ExtractEntities finds investment amounts, known locations and categories,
+// and quoted franchise names in a query.
+func ExtractEntities(query string) []Entity {
+	entities := []Entity{}
+	for _, m := range quotedPattern.FindAllStringSubmatch(query, -1) {
+		entities = append(entities, Entity{Type: "franchise_name", Value: m[1]})
+	}
+	for _, m := range investmentPattern.FindAllString(query, -1) {
+		entities = append(entities, Entity{Type: "investment_amount", Value: strings.TrimSpace(m)})
+	}
+
+	text := " " + strings.Join(wordPattern.FindAllString(strings.ToLower(query), -1), " ") + " "
+	for _, loc := range knownLocations {
+		if strings.Contains(text, " "+loc+" ") {
+			entities = append(entities, Entity{Type: "location", Value: loc})
+		}
+	}
+	for _, cat := range knownCategories {
+		if strings.Contains(text, " "+cat+" ") {
+			entities = append(entities, Entity{Type: "category", Value: cat})
+		}
+	}
+	return entities
+}
+
+// ResolveEntities carries the franchise of earlier questions over to a
+// follow-up that names none, so "what about in Texas?" stays about the same
+// franchise.
+func ResolveEntities(entities, prior []Entity) []Entity {
+	for _, e := range entities {
+		if e.Type == "franchise_name" {
+			return entities
+		}
+	}
+	for _, e := range prior {
+		if e.Type == "franchise_name" {
+			entities = append(entities, e)
+		}
+	}
+	return entities
+}
+
+func (s *Server) parseIntent(body map[string]interface{}) interface{} {
+	query, _ := body["query"].(string)
+	rule := ClassifyIntent(s.rules, query)
+	return IntentResponse{
+		Intent:      rule.Intent,
+		Confidence:  rule.Confidence,
+		Entities:    ResolveEntities(ExtractEntities(query), priorEntities(body)),
+		DataSources: rule.DataSources,
+	}
+}
+
+// priorEntities reads context.priorEntities, sent by parse-user-intent for
+// questions of a remembered conversation.
+func priorEntities(body map[string]interface{}) []Entity {
+	ctx, _ := body["context"].(map[string]interface{})
+	list, _ := ctx["priorEntities"].([]interface{})
+	var entities []Entity
+	for _, item := range list {
+		m, _ := item.(map[string]interface{})
+		typ, _ := m["type"].(string)
+		value, _ := m["value"].(string)
+		if typ != "" && value != "" {
+			entities = append(entities, Entity{Type: typ, Value: value})
+		}
+	}
+	return entities
+}
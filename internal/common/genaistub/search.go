@@ -0,0 +1,76 @@
+// internal/common/genaistub/search.go
+package genaistub
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// SearchResult is a hit of a /search response, in the SearXNG JSON format.
+type SearchResult struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// SearchResponse is the body of a /search response.
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+}
+
+// search answers with a page named after the query, a franchise buying guide
+// and a directory listing, so tests can check the query and filter domains.
+func (s *Server) search(q, timeRange string) SearchResponse {
+	slug := strings.Join(wordPattern.FindAllString(strings.ToLower(q), -1), "-")
+	content := "Stub result for " + q
+	if timeRange != "" {
+		content += " from the past " + timeRange
+	}
+	return SearchResponse{Results: []SearchResult{
+		{URL: "https://example.com/" + slug, Title: q, Content: content},
+		{URL: "https://www.franchise.org/buying-a-franchise", Title: "Buying a Franchise", Content: "What to know before buying a franchise."},
+		{URL: "https://franchise-directory.example.net/" + slug, Title: "Top franchises: " + q, Content: "Sponsored franchise listings."},
+	}}
+}
+
+// serveSearch answers GET /search?q=...&format=json from the script, with an
+// injected fault or with search, like serveAI.
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	params := r.URL.Query()
+	body := map[string]interface{}{"q": params.Get("q"), "time_range": params.Get("time_range")}
+
+	if idx, resp, ok := s.scripted(r.URL.Path, params.Get("q")); ok {
+		s.serveScripted(w, r, body, idx, resp)
+		return
+	}
+
+	delay, fail, malformed, errStatus := s.roll()
+	status := http.StatusOK
+	if fail {
+		status = errStatus
+	}
+	s.record(r.URL.Path, body, status, -1)
+	if !sleep(r.Context(), delay) {
+		return
+	}
+
+	answer := s.search(params.Get("q"), params.Get("time_range"))
+	switch {
+	case fail:
+		writeJSON(w, status, map[string]string{"error": "injected failure"})
+	case malformed:
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(answer)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes()[:buf.Len()/2])
+	default:
+		writeJSON(w, http.StatusOK, answer)
+	}
+}
@@ -0,0 +1,343 @@
+// internal/common/genaistub/server.go
+package genaistub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints served by the GenAI service that the workers call.
+const (
+	PathParseIntent = "/api/ai/parse-intent"
+	PathGenerate    = "/api/ai/generate"
+	PathEmbed       = "/api/ai/embed"
+	// PathSearch serves web searches in the SearXNG JSON format, for
+	// enrich-web-search with apis.web_search.provider searxng.
+	PathSearch = "/search"
+)
+
+// Faults are injected into every response that is not scripted.
+type Faults struct {
+	// LatencyMs delays each response; JitterMs adds up to that much more.
+	LatencyMs int `json:"latencyMs"`
+	JitterMs  int `json:"jitterMs"`
+	// ErrorRate is the share of requests, 0 to 1, answered with ErrorStatus.
+	ErrorRate   float64 `json:"errorRate"`
+	ErrorStatus int     `json:"errorStatus"`
+	// MalformedRate is the share of requests answered with truncated JSON.
+	MalformedRate float64 `json:"malformedRate"`
+	// ChunkDelayMs is the pause between events of a streamed answer.
+	ChunkDelayMs int `json:"chunkDelayMs"`
+}
+
+// Config configures a Server.
+type Config struct {
+	Faults Faults `json:"faults"`
+	// Seed makes the injected faults reproducible. Zero uses the clock.
+	Seed int64 `json:"seed"`
+	// Script lists canned responses, tried in order before the rules.
+	Script []ScriptedResponse `json:"script"`
+	// IntentRules replace DefaultIntentRules when set.
+	IntentRules []IntentRule `json:"intentRules"`
+}
+
+// ScriptedResponse answers matching requests with a fixed response.
+type ScriptedResponse struct {
+	// Path is the endpoint the response applies to; empty matches all.
+	Path string `json:"path"`
+	// Match is a case-insensitive substring of the query or prompt; empty
+	// matches every request.
+	Match string `json:"match"`
+	// Status defaults to 200.
+	Status int `json:"status"`
+	// Body is sent as JSON; RawBody is sent verbatim and wins when set.
+	Body    json.RawMessage `json:"body"`
+	RawBody string          `json:"rawBody"`
+	DelayMs int             `json:"delayMs"`
+	// Times limits how often the response is used; zero means always.
+	Times int `json:"times"`
+}
+
+// LoadConfig reads a Config from a JSON file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("read stub config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse stub config: %w", err)
+	}
+	return cfg, nil
+}
+
+// RecordedRequest is a request the server received.
+type RecordedRequest struct {
+	Path   string
+	Body   map[string]interface{}
+	Status int
+	// Scripted is the index of the scripted response used, or -1.
+	Scripted int
+}
+
+// Server is an in-process stand-in for the GenAI service.
+type Server struct {
+	mu       sync.Mutex
+	faults   Faults
+	script   []ScriptedResponse
+	used     []int
+	rules    []IntentRule
+	rand     *rand.Rand
+	requests []RecordedRequest
+}
+
+// New creates a server from cfg.
+func New(cfg Config) *Server {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rules := cfg.IntentRules
+	if len(rules) == 0 {
+		rules = DefaultIntentRules
+	}
+	return &Server{
+		faults: cfg.Faults,
+		script: cfg.Script,
+		used:   make([]int, len(cfg.Script)),
+		rules:  rules,
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Faults returns the faults currently injected.
+func (s *Server) Faults() Faults {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.faults
+}
+
+// SetFaults replaces the injected faults.
+func (s *Server) SetFaults(f Faults) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = f
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// Reset forgets received requests and how often scripted responses were used.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.used = make([]int, len(s.script))
+}
+
+// Handler returns the HTTP handler serving the GenAI endpoints, the search
+// endpoint, /health and
+// the /_stub control endpoints: GET/PUT /_stub/faults, GET /_stub/requests
+// and POST /_stub/reset.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathParseIntent, s.serveAI(s.parseIntent))
+	mux.HandleFunc(PathGenerate, s.serveAI(s.generate))
+	mux.HandleFunc(PathEmbed, s.serveAI(s.embed))
+	mux.HandleFunc(PathSearch, s.serveSearch)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("/_stub/faults", s.serveFaults)
+	mux.HandleFunc("/_stub/requests", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.Requests())
+	})
+	mux.HandleFunc("/_stub/reset", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func (s *Server) serveFaults(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Faults())
+	case http.MethodPut, http.MethodPost:
+		var f Faults
+		if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		s.SetFaults(f)
+		writeJSON(w, http.StatusOK, f)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveAI decodes the request, then answers from the script, with an
+// injected fault or with the rule-based answer, in that order.
+func (s *Server) serveAI(answer func(body map[string]interface{}) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		body := make(map[string]interface{})
+		if err := json.Unmarshal(data, &body); err != nil {
+			s.record(r.URL.Path, nil, http.StatusBadRequest, -1)
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+			return
+		}
+
+		if idx, resp, ok := s.scripted(r.URL.Path, requestText(body)); ok {
+			s.serveScripted(w, r, body, idx, resp)
+			return
+		}
+
+		delay, fail, malformed, errStatus := s.roll()
+		status := http.StatusOK
+		if fail {
+			status = errStatus
+		}
+		s.record(r.URL.Path, body, status, -1)
+		if !sleep(r.Context(), delay) {
+			return
+		}
+
+		stream, _ := body["stream"].(bool)
+		switch {
+		case fail:
+			writeJSON(w, status, map[string]string{"error": "injected failure"})
+		case stream && r.URL.Path == PathGenerate:
+			s.streamGenerate(w, r, answer(body).(GenerateResponse), len(strings.Fields(requestText(body))), malformed)
+		case malformed:
+			var buf bytes.Buffer
+			json.NewEncoder(&buf).Encode(answer(body))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write(buf.Bytes()[:buf.Len()/2])
+		default:
+			writeJSON(w, http.StatusOK, answer(body))
+		}
+	}
+}
+
+func (s *Server) serveScripted(w http.ResponseWriter, r *http.Request, body map[string]interface{}, idx int, resp ScriptedResponse) {
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	s.record(r.URL.Path, body, status, idx)
+	if !sleep(r.Context(), time.Duration(resp.DelayMs)*time.Millisecond) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if resp.RawBody != "" {
+		io.WriteString(w, resp.RawBody)
+	} else {
+		w.Write(resp.Body)
+	}
+}
+
+// scripted returns the first scripted response matching the request that
+// has uses left, and counts the use.
+func (s *Server) scripted(path, text string) (int, ScriptedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	text = strings.ToLower(text)
+	for i, resp := range s.script {
+		if resp.Path != "" && resp.Path != path {
+			continue
+		}
+		if resp.Match != "" && !strings.Contains(text, strings.ToLower(resp.Match)) {
+			continue
+		}
+		if resp.Times > 0 && s.used[i] >= resp.Times {
+			continue
+		}
+		s.used[i]++
+		return i, resp, true
+	}
+	return -1, ScriptedResponse{}, false
+}
+
+// roll decides the faults for one response.
+func (s *Server) roll() (delay time.Duration, fail, malformed bool, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := s.faults
+	delay = time.Duration(f.LatencyMs) * time.Millisecond
+	if f.JitterMs > 0 {
+		delay += time.Duration(s.rand.Intn(f.JitterMs+1)) * time.Millisecond
+	}
+	fail = f.ErrorRate > 0 && s.rand.Float64() < f.ErrorRate
+	malformed = !fail && f.MalformedRate > 0 && s.rand.Float64() < f.MalformedRate
+	status = f.ErrorStatus
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+	return delay, fail, malformed, status
+}
+
+func (s *Server) record(path string, body map[string]interface{}, status, scripted int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, RecordedRequest{Path: path, Body: body, Status: status, Scripted: scripted})
+}
+
+// requestText is the text scripted responses match against: the query of an
+// intent request or search, or the prompt of a generate request.
+func requestText(body map[string]interface{}) string {
+	for _, key := range []string{"query", "prompt", "q"} {
+		if s, ok := body[key].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// sleep waits for d unless the client goes away first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Fprintf(w, `{"error":%q}`, err.Error())
+	}
+}
@@ -0,0 +1,101 @@
+// internal/common/guardrails/guardrails.go
+package guardrails
+
+import "regexp"
+
+// Rules a decision can be taken under.
+const (
+	RuleEmail              = "pii_email"
+	RulePhone              = "pii_phone"
+	RuleSSN                = "pii_ssn"
+	RulePromptInjection    = "prompt_injection"
+	RuleFinancialGuarantee = "financial_guarantee"
+	RuleFranchisorContact  = "franchisor_contact"
+	RuleLegalAdvice        = "legal_advice"
+)
+
+// Actions taken by a guardrail.
+const (
+	ActionRedacted = "redacted"
+	ActionDropped  = "dropped"
+	ActionBlocked  = "blocked"
+)
+
+// Stages a decision is taken in.
+const (
+	StageInput  = "input"
+	StageOutput = "output"
+)
+
+// Decision records one guardrail intervention for audit. Detail names what
+// matched, never the redacted data itself.
+type Decision struct {
+	Stage  string `json:"stage"`
+	Rule   string `json:"rule"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Count  int    `json:"count,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// piiRules are applied in order, so that an SSN is not taken for a phone
+// number.
+var piiRules = []struct {
+	rule        string
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{RuleEmail, emailPattern, "[REDACTED_EMAIL]"},
+	{RuleSSN, regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), "[REDACTED_SSN]"},
+	{RulePhone, phonePattern, "[REDACTED_PHONE]"},
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+1[-.\s]?)?(?:\(\d{3}\)\s?|\b\d{3}[-.\s])\d{3}[-.\s]\d{4}\b`)
+)
+
+// Redact replaces emails, SSN-like numbers and phone numbers in text. It
+// returns one decision per rule that matched, for target.
+func Redact(text, target string) (string, []Decision) {
+	var decisions []Decision
+	for _, r := range piiRules {
+		n := 0
+		text = r.pattern.ReplaceAllStringFunc(text, func(string) string {
+			n++
+			return r.replacement
+		})
+		if n > 0 {
+			decisions = append(decisions, Decision{
+				Stage:  StageInput,
+				Rule:   r.rule,
+				Action: ActionRedacted,
+				Target: target,
+				Count:  n,
+			})
+		}
+	}
+	return text, decisions
+}
+
+// injectionPatterns are phrases that address the model rather than the
+// reader; web pages carrying them are not used as sources.
+var injectionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\s+(all\s+|any\s+|the\s+)?(previous|prior|above|earlier|preceding|system)\s+(instructions|prompts?|directions|rules|messages)`),
+	regexp.MustCompile(`(?i)\b(reveal|print|show|repeat)\s+(me\s+)?(your|the)\s+(system\s+)?(prompt|instructions)`),
+	regexp.MustCompile(`(?i)\byou\s+are\s+now\s+(a|an|in|the)\b`),
+	regexp.MustCompile(`(?i)\bnew\s+instructions\s*:`),
+	regexp.MustCompile(`(?i)\b(system|assistant)\s*:\s*you\s+(are|must|will)\b`),
+	regexp.MustCompile(`(?i)\bdo\s+anything\s+now\b|\bjailbreak\b|\bDAN\s+mode\b`),
+	regexp.MustCompile(`(?i)<\|?(im_start|im_end|system|endoftext)\|?>|\[/?INST\]|###\s*instruction`),
+}
+
+// DetectInjection returns the first prompt-injection phrase in text.
+func DetectInjection(text string) (string, bool) {
+	for _, p := range injectionPatterns {
+		if m := p.FindString(text); m != "" {
+			return m, true
+		}
+	}
+	return "", false
+}
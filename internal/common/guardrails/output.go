@@ -0,0 +1,136 @@
+// internal/common/guardrails/output.go
+package guardrails
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultCategories are the answer categories blocked unless configured
+// otherwise.
+var DefaultCategories = []string{RuleFinancialGuarantee, RuleFranchisorContact, RuleLegalAdvice}
+
+// categoryPatterns recognize the answer categories blocked by phrase.
+// Franchisor contact data is recognized by comparison with the input.
+var categoryPatterns = map[string][]*regexp.Regexp{
+	RuleFinancialGuarantee: {
+		regexp.MustCompile(`(?i)\bguarantee[sd]?\b.{0,40}\b(returns?|profits?|income|earnings|success|roi|payback)\b`),
+		regexp.MustCompile(`(?i)\b(risk[- ]free|no[- ]risk|can'?t\s+lose|cannot\s+lose|sure\s+thing)\b`),
+		regexp.MustCompile(`(?i)\byou('ll|\s+will)\s+(definitely\s+|certainly\s+|surely\s+)?(make|earn|profit|recoup)\s+(at\s+least\s+)?(\$|a\s+profit|money|your\s+investment\s+back)`),
+	},
+	RuleLegalAdvice: {
+		regexp.MustCompile(`(?i)\b(as\s+your\s+(lawyer|attorney)|this\s+is\s+legal\s+advice)\b`),
+		regexp.MustCompile(`(?i)\byou\s+(don'?t|do\s+not)\s+need\s+(a|an|to\s+consult\s+(a|an))\s+(lawyer|attorney)\b`),
+		regexp.MustCompile(`(?i)\b(the|this|your)\s+(franchise\s+|non-?compete\s+)?(agreement|contract|fdd|clause|non-?compete)\s+is\s+(legally\s+)?(unenforceable|enforceable|binding|void|illegal|invalid)\b`),
+		regexp.MustCompile(`(?i)\byou\s+(can|could|should)\s+(sue|take\s+legal\s+action|break\s+the\s+(contract|agreement)|ignore\s+the\s+(contract|agreement|non-?compete))\b`),
+		regexp.MustCompile(`(?i)\byou\s+(should|must)\s+(not\s+)?sign\b`),
+	},
+}
+
+// CheckAnswer removes the sentences of answer falling in one of categories.
+// known is the text the answer was generated from: an email or phone number
+// not found in it is fabricated franchisor contact data. It returns the
+// remaining answer and a decision per removed sentence.
+func CheckAnswer(answer, known string, categories []string) (string, []Decision) {
+	var decisions []Decision
+	for _, sentence := range sentences(answer) {
+		rule, detail := classify(sentence, known, categories)
+		if rule == "" {
+			continue
+		}
+		answer = strings.Replace(answer, sentence, "", 1)
+		decisions = append(decisions, Decision{
+			Stage:  StageOutput,
+			Rule:   rule,
+			Action: ActionBlocked,
+			Target: "answer",
+			Detail: detail,
+		})
+	}
+	if len(decisions) == 0 {
+		return answer, nil
+	}
+	return tidy(answer), decisions
+}
+
+func classify(sentence, known string, categories []string) (rule, detail string) {
+	for _, category := range categories {
+		if category == RuleFranchisorContact {
+			if kind := fabricatedContact(sentence, known); kind != "" {
+				return category, kind
+			}
+			continue
+		}
+		for _, p := range categoryPatterns[category] {
+			if m := p.FindString(sentence); m != "" {
+				return category, m
+			}
+		}
+	}
+	return "", ""
+}
+
+// fabricatedContact returns "email" or "phone" when the sentence gives an
+// email address or phone number that known does not contain.
+func fabricatedContact(sentence, known string) string {
+	lower := strings.ToLower(known)
+	for _, email := range emailPattern.FindAllString(sentence, -1) {
+		if !strings.Contains(lower, strings.ToLower(email)) {
+			return "email"
+		}
+	}
+	knownDigits := digits(known)
+	for _, phone := range phonePattern.FindAllString(sentence, -1) {
+		d := digits(phone)
+		if len(d) > 10 {
+			d = d[len(d)-10:] // drop the +1 country code
+		}
+		if !strings.Contains(knownDigits, d) {
+			return "phone"
+		}
+	}
+	return ""
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// sentences splits text after ., ! or ? followed by whitespace, and at line
+// breaks.
+func sentences(text string) []string {
+	var out []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		end := r == '\n' || ((r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n'))
+		if !end {
+			continue
+		}
+		if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+			out = append(out, s)
+		}
+		start = i + 1
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+var extraSpaces = regexp.MustCompile(`[ \t]{2,}`)
+
+// tidy collapses the gaps removed sentences leave behind.
+func tidy(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(extraSpaces.ReplaceAllString(line, " "))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
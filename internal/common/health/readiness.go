@@ -0,0 +1,109 @@
+// internal/common/health/readiness.go
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+// Pinger is a shared client that /ready probes.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingFunc adapts a function to Pinger.
+type PingFunc func(ctx context.Context) error
+
+func (f PingFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+// Clients are the shared clients the worker manager connects at startup.
+type Clients struct {
+	Zeebe         Pinger
+	Postgres      Pinger
+	Redis         Pinger
+	Elasticsearch Pinger
+}
+
+// Lifecycle is the part of camunda.Lifecycle the health endpoints read.
+type Lifecycle interface {
+	Stopping() bool
+	Workers() map[string]camunda.Worker
+	Stats() map[string]camunda.WorkerStats
+}
+
+// WorkerDependencyChecks maps workers whose HealthCheck probes an external
+// service to the component name reported by /ready.
+var WorkerDependencyChecks = map[string]string{
+	"email-send":      "smtp",
+	"crm-user-create": "zoho-crm",
+	"auth-logout":     "keycloak",
+}
+
+// NewReadinessChecker builds the /ready checks for the shared clients and
+// the external services behind started workers.
+func NewReadinessChecker(clients Clients, lifecycle Lifecycle) *Checker {
+	checker := NewChecker(5 * time.Second)
+
+	checker.Register("lifecycle", func(ctx context.Context) error {
+		if lifecycle.Stopping() {
+			return fmt.Errorf("worker manager is shutting down")
+		}
+		return nil
+	})
+	checker.Register("zeebe", clients.Zeebe.Ping)
+	checker.Register("postgres", clients.Postgres.Ping)
+	checker.Register("redis", clients.Redis.Ping)
+	checker.Register("elasticsearch", clients.Elasticsearch.Ping)
+
+	started := lifecycle.Workers()
+	for taskType, component := range WorkerDependencyChecks {
+		if w, ok := started[taskType]; ok {
+			checker.Register(component, w.HealthCheck)
+		}
+	}
+
+	return checker
+}
+
+// workerStatus is one entry of the /health/workers response.
+type workerStatus struct {
+	TaskType    string `json:"taskType"`
+	Enabled     bool   `json:"enabled"`
+	Running     bool   `json:"running"`
+	ActiveJobs  int64  `json:"activeJobs"`
+	LastSuccess string `json:"lastSuccess,omitempty"`
+}
+
+// WorkersHandler lists every task type in taskTypes with its enabled flag,
+// active-job count and the time of its last completed job.
+func WorkersHandler(cfg *config.Config, lifecycle Lifecycle, taskTypes []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := lifecycle.Stats()
+
+		workers := make([]workerStatus, 0, len(taskTypes))
+		for _, taskType := range taskTypes {
+			status := workerStatus{
+				TaskType: taskType,
+				Enabled:  cfg.Workers[taskType].Enabled,
+			}
+			if ws, ok := stats[taskType]; ok {
+				status.Running = true
+				status.ActiveJobs = ws.ActiveJobs
+				if !ws.LastSuccess.IsZero() {
+					status.LastSuccess = ws.LastSuccess.Format(time.RFC3339)
+				}
+			}
+			workers = append(workers, status)
+		}
+
+		WriteJSON(w, http.StatusOK, map[string]interface{}{
+			"time":    time.Now().Format(time.RFC3339),
+			"workers": workers,
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// internal/common/health/readiness_test.go
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/health"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+// fakePinger fails with err, or succeeds when err is nil.
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) Ping(context.Context) error { return p.err }
+
+// fakeLifecycle serves fixed workers and stats.
+type fakeLifecycle struct {
+	stopping bool
+	workers  map[string]camunda.Worker
+	stats    map[string]camunda.WorkerStats
+}
+
+func (l *fakeLifecycle) Stopping() bool                        { return l.stopping }
+func (l *fakeLifecycle) Workers() map[string]camunda.Worker    { return l.workers }
+func (l *fakeLifecycle) Stats() map[string]camunda.WorkerStats { return l.stats }
+
+type fakeClients struct {
+	zeebe, postgres, redis, elasticsearch fakePinger
+}
+
+func (c *fakeClients) clients() health.Clients {
+	return health.Clients{Zeebe: &c.zeebe, Postgres: &c.postgres, Redis: &c.redis, Elasticsearch: &c.elasticsearch}
+}
+
+// checkedWorker is a started worker whose HealthCheck fails with err.
+func checkedWorker(taskType string, err error) camunda.Worker {
+	return camunda.NewHandlerWorker(taskType, testkit.Completes(nil),
+		camunda.WithHealthCheck(func(context.Context) error { return err }))
+}
+
+// ready serves /ready from checker and decodes the report.
+func ready(t *testing.T, checker *health.Checker) (int, health.Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	checker.ReadyHandler()(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	return rec.Code, report
+}
+
+// ==========================
+// Readiness Tests
+// ==========================
+
+func TestReadiness_AllUp(t *testing.T) {
+	deps := &fakeClients{}
+	code, report := ready(t, health.NewReadinessChecker(deps.clients(), &fakeLifecycle{}))
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusUp, report.Status)
+	assert.ElementsMatch(t, []string{"lifecycle", "zeebe", "postgres", "redis", "elasticsearch"}, keys(report.Components),
+		"no worker dependency is checked when its worker is not started")
+}
+
+func TestReadiness_SharedClientDown(t *testing.T) {
+	tests := []struct {
+		component string
+		fail      func(*fakeClients, error)
+	}{
+		{"zeebe", func(c *fakeClients, err error) { c.zeebe.err = err }},
+		{"postgres", func(c *fakeClients, err error) { c.postgres.err = err }},
+		{"redis", func(c *fakeClients, err error) { c.redis.err = err }},
+		{"elasticsearch", func(c *fakeClients, err error) { c.elasticsearch.err = err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			deps := &fakeClients{}
+			tt.fail(deps, stderrors.New("connection refused"))
+
+			code, report := ready(t, health.NewReadinessChecker(deps.clients(), &fakeLifecycle{}))
+
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.Equal(t, health.StatusDown, report.Status)
+			for name, status := range report.Components {
+				if name == tt.component {
+					assert.Equal(t, health.StatusDown, status.Status)
+					assert.Equal(t, "connection refused", status.Error)
+					continue
+				}
+				assert.Equal(t, health.StatusUp, status.Status, name)
+			}
+		})
+	}
+}
+
+func TestReadiness_Stopping(t *testing.T) {
+	deps := &fakeClients{}
+	code, report := ready(t, health.NewReadinessChecker(deps.clients(), &fakeLifecycle{stopping: true}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusDown, report.Components["lifecycle"].Status)
+	assert.Equal(t, "worker manager is shutting down", report.Components["lifecycle"].Error)
+}
+
+func TestReadiness_WorkerDependencies(t *testing.T) {
+	tests := []struct {
+		taskType  string
+		component string
+	}{
+		{"email-send", "smtp"},
+		{"crm-user-create", "zoho-crm"},
+		{"auth-logout", "keycloak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			lifecycle := &fakeLifecycle{workers: map[string]camunda.Worker{
+				"llm-synthesis": checkedWorker("llm-synthesis", stderrors.New("not a readiness dependency")),
+			}}
+			for _, other := range tests {
+				var err error
+				if other.taskType == tt.taskType {
+					err = stderrors.New(tt.component + " unreachable")
+				}
+				lifecycle.workers[other.taskType] = checkedWorker(other.taskType, err)
+			}
+
+			deps := &fakeClients{}
+			code, report := ready(t, health.NewReadinessChecker(deps.clients(), lifecycle))
+
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.Len(t, report.Components, 8, "shared clients, lifecycle and the three worker dependencies")
+			assert.NotContains(t, report.Components, "llm-synthesis")
+			for _, other := range tests {
+				status := report.Components[other.component]
+				if other.component == tt.component {
+					assert.Equal(t, health.StatusDown, status.Status)
+					assert.Equal(t, tt.component+" unreachable", status.Error)
+					continue
+				}
+				assert.Equal(t, health.StatusUp, status.Status, other.component)
+			}
+		})
+	}
+}
+
+func keys(components map[string]health.ComponentStatus) []string {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ==========================
+// Workers Handler Tests
+// ==========================
+
+func TestWorkersHandler(t *testing.T) {
+	lastSuccess := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	cfg := &config.Config{Workers: map[string]config.WorkerConfig{
+		"email-send":    {Enabled: true},
+		"llm-synthesis": {Enabled: true},
+	}}
+	lifecycle := &fakeLifecycle{stats: map[string]camunda.WorkerStats{
+		"email-send":    {ActiveJobs: 3, LastSuccess: lastSuccess},
+		"llm-synthesis": {},
+	}}
+
+	rec := httptest.NewRecorder()
+	handler := health.WorkersHandler(cfg, lifecycle, []string{"auth-logout", "email-send", "llm-synthesis"})
+	handler(rec, httptest.NewRequest(http.MethodGet, "/health/workers", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body struct {
+		Time    string                   `json:"time"`
+		Workers []map[string]interface{} `json:"workers"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.NotEmpty(t, body.Time)
+	assert.Equal(t, []map[string]interface{}{
+		{"taskType": "auth-logout", "enabled": false, "running": false, "activeJobs": 0.0},
+		{"taskType": "email-send", "enabled": true, "running": true, "activeJobs": 3.0, "lastSuccess": lastSuccess.Format(time.RFC3339)},
+		{"taskType": "llm-synthesis", "enabled": true, "running": true, "activeJobs": 0.0},
+	}, body.Workers, "lastSuccess is left out until a job completes")
+}
@@ -0,0 +1,165 @@
+// internal/common/intent/bayes.go
+package intent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Example is a labeled question of the training file, one JSON object per
+// line: {"text": "what does it cost to open one?", "intent": "cost_inquiry"}.
+type Example struct {
+	Text   string `json:"text"`
+	Intent string `json:"intent"`
+}
+
+// ReadExamples reads the examples of a JSONL training file, skipping blank
+// lines.
+func ReadExamples(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open training file: %w", err)
+	}
+	defer f.Close()
+
+	var examples []Example
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var ex Example
+		if err := json.Unmarshal([]byte(text), &ex); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if ex.Text == "" || ex.Intent == "" {
+			return nil, fmt.Errorf("%s:%d: text and intent are required", path, line)
+		}
+		examples = append(examples, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read training file: %w", err)
+	}
+	return examples, nil
+}
+
+// Model is a multinomial naive Bayes classifier over the words of a
+// question, with add-one smoothing.
+type Model struct {
+	intents    []string
+	prior      map[string]float64            // log P(intent)
+	likelihood map[string]map[string]float64 // log P(word | intent)
+	unseen     map[string]float64            // log P(word | intent) of a word not seen with intent
+	vocabulary map[string]bool
+}
+
+// Train fits a Model to examples. It returns nil without examples.
+func Train(examples []Example) *Model {
+	if len(examples) == 0 {
+		return nil
+	}
+	docs := make(map[string]int)
+	counts := make(map[string]map[string]int)
+	totals := make(map[string]int)
+	vocabulary := make(map[string]bool)
+	for _, ex := range examples {
+		docs[ex.Intent]++
+		if counts[ex.Intent] == nil {
+			counts[ex.Intent] = make(map[string]int)
+		}
+		for _, w := range features(ex.Text) {
+			counts[ex.Intent][w]++
+			totals[ex.Intent]++
+			vocabulary[w] = true
+		}
+	}
+
+	m := &Model{
+		prior:      make(map[string]float64),
+		likelihood: make(map[string]map[string]float64),
+		unseen:     make(map[string]float64),
+		vocabulary: vocabulary,
+	}
+	v := float64(len(vocabulary))
+	for intent, n := range docs {
+		m.intents = append(m.intents, intent)
+		m.prior[intent] = math.Log(float64(n) / float64(len(examples)))
+		denom := float64(totals[intent]) + v
+		m.likelihood[intent] = make(map[string]float64, len(counts[intent]))
+		for w, c := range counts[intent] {
+			m.likelihood[intent][w] = math.Log((float64(c) + 1) / denom)
+		}
+		m.unseen[intent] = math.Log(1 / denom)
+	}
+	sort.Strings(m.intents)
+	return m
+}
+
+// Predict returns the most probable intent of text and its posterior
+// probability, or "" when text has no word the model was trained on.
+func (m *Model) Predict(text string) (string, float64) {
+	var words []string
+	for _, w := range features(text) {
+		if m.vocabulary[w] {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return "", 0
+	}
+
+	scores := make([]float64, len(m.intents))
+	best := 0
+	for i, intent := range m.intents {
+		score := m.prior[intent]
+		for _, w := range words {
+			if l, ok := m.likelihood[intent][w]; ok {
+				score += l
+			} else {
+				score += m.unseen[intent]
+			}
+		}
+		scores[i] = score
+		if score > scores[best] {
+			best = i
+		}
+	}
+
+	// Posterior of the best intent: 1 / sum(exp(score - best score)).
+	var sum float64
+	for _, s := range scores {
+		sum += math.Exp(s - scores[best])
+	}
+	return m.intents[best], 1 / sum
+}
+
+// stopWords carry no intent.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "for": true, "is": true, "are": true, "it": true, "i": true,
+	"me": true, "my": true, "do": true, "does": true, "be": true, "this": true, "that": true,
+	"with": true, "can": true, "you": true, "your": true, "what": true, "about": true,
+}
+
+// features are the lowercase words of text without stop words, with
+// amounts reduced to $.
+func features(text string) []string {
+	var words []string
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		w = strings.Trim(w, "'")
+		switch {
+		case w == "" || stopWords[w]:
+			continue
+		case strings.HasPrefix(w, "$"):
+			w = "$"
+		}
+		words = append(words, w)
+	}
+	return words
+}
@@ -0,0 +1,176 @@
+// internal/common/intent/classifier.go
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Entity types the classifier extracts, as parse-intent returns them.
+const (
+	EntityFranchise  = "franchise_name"
+	EntityLocation   = "location"
+	EntityCategory   = "category"
+	EntityInvestment = "investment_amount"
+)
+
+const (
+	// DefaultIntent answers questions neither the rules nor the model
+	// recognize.
+	DefaultIntent     = "general_info"
+	defaultConfidence = 0.3
+	// maxModelConfidence caps the model's posterior, which is overconfident
+	// on questions as short as these.
+	maxModelConfidence = 0.85
+)
+
+// Entity is an entity found in a question.
+type Entity struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Result is the intent of a question, with the entities it names.
+type Result struct {
+	Intent     string
+	Confidence float64
+	Entities   []Entity
+}
+
+// Options configure a Classifier.
+type Options struct {
+	// Rules are the keyword and pattern rules; nil uses DefaultRules.
+	Rules []Rule
+	// Model is consulted alongside the rules; nil classifies by the rules
+	// alone.
+	Model *Model
+	// Gazetteer lists the locations recognized; nil recognizes none.
+	Gazetteer *Gazetteer
+	// Franchises are the franchise names recognized, e.g. from LoadCatalog.
+	Franchises []string
+	// Categories are recognized in addition to DefaultCategories.
+	Categories []string
+}
+
+// Classifier classifies questions in process, without the GenAI service.
+// It is safe for concurrent use.
+type Classifier struct {
+	rules      []Rule
+	model      *Model
+	franchises *phraseIndex
+	locations  *phraseIndex
+	categories *phraseIndex
+	stateCodes map[string]string
+}
+
+// New returns a Classifier for opts.
+func New(opts Options) *Classifier {
+	c := &Classifier{
+		rules:      opts.Rules,
+		model:      opts.Model,
+		franchises: newPhraseIndex(),
+		locations:  newPhraseIndex(),
+		categories: newPhraseIndex(),
+		stateCodes: make(map[string]string),
+	}
+	if c.rules == nil {
+		c.rules = DefaultRules
+	}
+	for _, name := range opts.Franchises {
+		c.franchises.add(name, name)
+	}
+	if g := opts.Gazetteer; g != nil {
+		for _, s := range g.States {
+			c.locations.add(s.Name, s.Name)
+			if !ambiguousStateCodes[s.Code] {
+				c.stateCodes[strings.ToUpper(s.Code)] = s.Name
+			}
+		}
+		for _, city := range g.Cities {
+			c.locations.add(city.Name, city.Name)
+		}
+	}
+	// Catalog categories come first so that their spelling is kept.
+	for _, cat := range opts.Categories {
+		c.categories.add(cat, cat)
+	}
+	for _, cat := range DefaultCategories {
+		c.categories.add(cat, cat)
+	}
+	c.franchises.sort()
+	c.locations.sort()
+	c.categories.sort()
+	return c
+}
+
+// Classify returns the intent of question and its entities. The rules and
+// the model each propose an intent; the more confident one wins, and the
+// confidence is raised when they agree. A question neither recognizes is
+// DefaultIntent.
+func (c *Classifier) Classify(question string) Result {
+	var intent string
+	var confidence float64
+	if rule, hits := matchRules(c.rules, question); hits > 0 {
+		intent, confidence = rule.Intent, rule.confidence(hits)
+	}
+	if c.model != nil {
+		if predicted, p := c.model.Predict(question); predicted != "" {
+			p = min(p, maxModelConfidence)
+			switch {
+			case predicted == intent:
+				confidence = max(confidence, p)
+			case p > confidence:
+				intent, confidence = predicted, p
+			}
+		}
+	}
+	if intent == "" {
+		intent, confidence = DefaultIntent, defaultConfidence
+	}
+	return Result{Intent: intent, Confidence: confidence, Entities: c.Entities(question)}
+}
+
+var stateCodePattern = regexp.MustCompile(`\b[A-Z]{2}\b`)
+
+// Entities returns the franchise names, investment amounts, locations and
+// categories in question. A phrase is taken once, longest first, so that
+// "Texas Roadhouse" is a franchise rather than a state.
+func (c *Classifier) Entities(question string) []Entity {
+	entities := []Entity{}
+	text := normalize(question)
+
+	var names []string
+	names, text = c.franchises.find(text)
+	for _, name := range names {
+		entities = append(entities, Entity{Type: EntityFranchise, Value: name})
+	}
+	for _, amount := range investmentAmounts(question) {
+		entities = append(entities, Entity{Type: EntityInvestment, Value: amount})
+	}
+
+	var locations []string
+	locations, text = c.locations.find(text)
+	for _, m := range stateCodePattern.FindAllString(question, -1) {
+		if name, ok := c.stateCodes[m]; ok {
+			locations = appendUnique(locations, name)
+		}
+	}
+	for _, loc := range locations {
+		entities = append(entities, Entity{Type: EntityLocation, Value: loc})
+	}
+
+	categories, _ := c.categories.find(text)
+	for _, cat := range categories {
+		entities = append(entities, Entity{Type: EntityCategory, Value: cat})
+	}
+	return entities
+}
+
+func appendUnique(values []string, v string) []string {
+	for _, existing := range values {
+		if strings.EqualFold(existing, v) {
+			return values
+		}
+	}
+	return append(values, v)
+}
@@ -0,0 +1,164 @@
+// internal/common/intent/entities.go
+package intent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Gazetteer lists the US states and cities recognized as locations, read from
+// a JSON file: {"states": [{"name": "Texas", "code": "TX"}],
+// "cities": [{"name": "Austin", "state": "TX"}]}.
+type Gazetteer struct {
+	States []State `json:"states"`
+	Cities []City  `json:"cities"`
+}
+
+type State struct {
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+type City struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// ReadGazetteer reads a gazetteer file.
+func ReadGazetteer(path string) (*Gazetteer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read gazetteer: %w", err)
+	}
+	var g Gazetteer
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("parse gazetteer %s: %w", path, err)
+	}
+	return &g, nil
+}
+
+// ambiguousStateCodes are also English words; the state is recognized by
+// its name only.
+var ambiguousStateCodes = map[string]bool{"IN": true, "OR": true, "ME": true, "OK": true, "HI": true, "OH": true}
+
+// DefaultCategories are recognized as categories besides those of the
+// franchises table.
+var DefaultCategories = []string{
+	"coffee", "food", "fast food", "restaurant", "pizza", "bakery", "fitness", "education",
+	"tutoring", "cleaning", "retail", "beauty", "automotive", "pet", "health", "senior care",
+	"home services", "childcare", "real estate", "hotel",
+}
+
+// LoadCatalog reads the franchise names and the distinct categories of the
+// franchises table.
+func LoadCatalog(ctx context.Context, db *sql.DB) (franchises, categories []string, err error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, COALESCE(category, '') FROM franchises ORDER BY name`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load franchises: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var name, category string
+		if err := rows.Scan(&name, &category); err != nil {
+			return nil, nil, fmt.Errorf("scan franchise: %w", err)
+		}
+		franchises = append(franchises, name)
+		if key := strings.ToLower(category); category != "" && !seen[key] {
+			seen[key] = true
+			categories = append(categories, category)
+		}
+	}
+	return franchises, categories, rows.Err()
+}
+
+// phraseIndex finds known names in normalized text.
+type phraseIndex struct {
+	phrases []phrase
+	seen    map[string]bool
+}
+
+type phrase struct {
+	key   string // normalized, without the padding spaces
+	value string
+}
+
+func newPhraseIndex() *phraseIndex {
+	return &phraseIndex{seen: make(map[string]bool)}
+}
+
+// add indexes name, reported as value; the first value of a name is kept.
+func (p *phraseIndex) add(name, value string) {
+	key := strings.TrimSpace(normalize(name))
+	if key == "" || p.seen[key] {
+		return
+	}
+	p.seen[key] = true
+	p.phrases = append(p.phrases, phrase{key: key, value: value})
+}
+
+// sort puts longer phrases first, so that they are found before the
+// phrases they contain.
+func (p *phraseIndex) sort() {
+	sort.SliceStable(p.phrases, func(i, j int) bool {
+		return len(p.phrases[i].key) > len(p.phrases[j].key)
+	})
+}
+
+// find returns the values of the phrases in text, and text with them
+// blanked out.
+func (p *phraseIndex) find(text string) ([]string, string) {
+	var values []string
+	for _, ph := range p.phrases {
+		padded := " " + ph.key + " "
+		if strings.Contains(text, padded) {
+			values = appendUnique(values, ph.value)
+			text = strings.ReplaceAll(text, padded, " | ")
+		}
+	}
+	return values, text
+}
+
+var tokenPattern = regexp.MustCompile(`[\p{L}\p{N}&]+`)
+
+// normalize lowercases s, drops apostrophes so that "McDonalds" finds
+// "McDonald's", and returns its words padded with spaces.
+func normalize(s string) string {
+	s = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(s))
+	return " " + strings.Join(tokenPattern.FindAllString(s, -1), " ") + " "
+}
+
+var investmentPattern = regexp.MustCompile(`(?i)(\$\s?)?\b(\d{1,3}(?:,\d{3})+|\d+)(\.\d+)?\s?(k|m|thousand|million)?\b(\s?dollars)?`)
+
+var multipliers = map[string]float64{"k": 1e3, "thousand": 1e3, "m": 1e6, "million": 1e6}
+
+// investmentAmounts returns the amounts of money in question in dollars,
+// e.g. "250000" for "$250k". A number is taken for an amount only with a
+// dollar sign, a multiplier or "dollars".
+func investmentAmounts(question string) []string {
+	var amounts []string
+	for _, m := range investmentPattern.FindAllStringSubmatch(question, -1) {
+		dollar, multiplier, dollars := m[1] != "", strings.ToLower(m[4]), m[5] != ""
+		if !dollar && multiplier == "" && !dollars {
+			continue
+		}
+		n, err := strconv.ParseFloat(strings.ReplaceAll(m[2], ",", "")+m[3], 64)
+		if err != nil {
+			continue
+		}
+		if mult, ok := multipliers[multiplier]; ok {
+			n *= mult
+		}
+		amounts = appendUnique(amounts, strconv.FormatInt(int64(math.Round(n)), 10))
+	}
+	return amounts
+}
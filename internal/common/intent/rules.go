@@ -0,0 +1,99 @@
+// internal/common/intent/rules.go
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rule maps keywords and patterns in a question to an intent. Single-word
+// keywords match whole words; phrases match as substrings.
+type Rule struct {
+	Intent     string
+	Keywords   []string
+	Patterns   []*regexp.Regexp
+	Confidence float64
+}
+
+// confidence grows by 0.05 with every hit beyond the first, up to 0.95.
+func (r Rule) confidence(hits int) float64 {
+	return min(r.Confidence+0.05*float64(hits-1), 0.95)
+}
+
+// DefaultRules cover the intents of the parse-intent API. A greeting must be
+// the whole question, so that "hi, what does X cost?" is a cost inquiry.
+var DefaultRules = []Rule{
+	{
+		Intent:     "cost_inquiry",
+		Keywords:   []string{"cost", "costs", "price", "pricing", "fee", "fees", "investment", "invest", "royalty", "royalties", "capital", "afford", "expensive", "cheap", "how much", "startup cost"},
+		Patterns:   []*regexp.Regexp{regexp.MustCompile(`\$\s?\d`)},
+		Confidence: 0.8,
+	},
+	{
+		Intent:     "location_inquiry",
+		Keywords:   []string{"where", "location", "locations", "near", "nearby", "territory", "territories", "city", "cities", "area", "open in"},
+		Confidence: 0.75,
+	},
+	{
+		Intent:     "competitor_analysis",
+		Keywords:   []string{"competitor", "competitors", "compare", "comparison", "versus", "vs", "alternative", "alternatives", "better than", "difference between"},
+		Confidence: 0.75,
+	},
+	{
+		Intent:     "market_research",
+		Keywords:   []string{"market", "trend", "trends", "growth", "growing", "demand", "industry", "outlook", "forecast"},
+		Confidence: 0.7,
+	},
+	{
+		Intent:     "category_search",
+		Keywords:   []string{"category", "categories", "type of", "types of", "kinds of", "list", "show me"},
+		Confidence: 0.7,
+	},
+	{
+		Intent:     "franchise_inquiry",
+		Keywords:   []string{"franchise", "franchises", "franchisor", "franchisee", "requirements", "apply", "open a"},
+		Confidence: 0.65,
+	},
+	{
+		Intent:     "greeting",
+		Patterns:   []*regexp.Regexp{regexp.MustCompile(`(?i)^\W*(hi|hello|hey|good (morning|afternoon|evening))( there)?\W*$`)},
+		Confidence: 0.9,
+	},
+}
+
+var wordPattern = regexp.MustCompile(`[a-z0-9$']+`)
+
+// matchRules returns the rule with the most hits in question; the first
+// rule wins a tie.
+func matchRules(rules []Rule, question string) (Rule, int) {
+	text := strings.ToLower(question)
+	words := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(text, -1) {
+		words[w] = true
+	}
+
+	var best Rule
+	bestHits := 0
+	for _, rule := range rules {
+		hits := 0
+		for _, kw := range rule.Keywords {
+			kw = strings.ToLower(kw)
+			if strings.Contains(kw, " ") {
+				if strings.Contains(text, kw) {
+					hits++
+				}
+			} else if words[kw] {
+				hits++
+			}
+		}
+		for _, p := range rule.Patterns {
+			if p.MatchString(question) {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = rule, hits
+		}
+	}
+	return best, bestHits
+}
@@ -0,0 +1,84 @@
+// internal/common/llm/embed.go
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Embedder turns texts into embedding vectors, one per text and in order.
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder creates the embedder of the provider registered under name.
+// opts.Model names the embedding model, which is usually not the chat model.
+func NewEmbedder(name string, opts Options) (Embedder, error) {
+	client := &http.Client{Timeout: opts.Timeout}
+	switch strings.ToLower(name) {
+	case "", ProviderGenAI:
+		return NewGenAI(opts.BaseURL, opts.APIKey, client), nil
+	case ProviderOpenAI:
+		return NewOpenAI(opts.BaseURL, opts.APIKey, opts.Model, client), nil
+	case ProviderOllama:
+		return NewOllama(opts.BaseURL, opts.Model, client), nil
+	default:
+		return nil, fmt.Errorf("unknown embedding provider %q", name)
+	}
+}
+
+// Embed posts the texts to /api/ai/embed and reads {"embeddings": [...]}.
+func (p *GenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var out struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	body := map[string]interface{}{"input": texts}
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/api/ai/embed", p.headers(), body, &out); err != nil {
+		return nil, err
+	}
+	return checkEmbeddings(p.Name(), texts, out.Embeddings)
+}
+
+// Embed calls POST {baseURL}/embeddings with the provider's model.
+func (p *OpenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var out struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	body := map[string]interface{}{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/embeddings", p.headers(), body, &out); err != nil {
+		return nil, quotaError(err)
+	}
+	sort.Slice(out.Data, func(i, j int) bool { return out.Data[i].Index < out.Data[j].Index })
+	embeddings := make([][]float32, len(out.Data))
+	for i, d := range out.Data {
+		embeddings[i] = d.Embedding
+	}
+	return checkEmbeddings(p.Name(), texts, embeddings)
+}
+
+// Embed calls POST {baseURL}/api/embed with the provider's model.
+func (p *Ollama) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var out struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	body := map[string]interface{}{"model": p.model, "input": texts}
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/api/embed", nil, body, &out); err != nil {
+		return nil, busyError(err)
+	}
+	return checkEmbeddings(p.Name(), texts, out.Embeddings)
+}
+
+// checkEmbeddings rejects an answer without exactly one vector per text.
+func checkEmbeddings(provider string, texts []string, embeddings [][]float32) ([][]float32, error) {
+	if len(embeddings) != len(texts) {
+		return nil, decodeError(provider, fmt.Errorf("got %d embeddings for %d texts", len(embeddings), len(texts)))
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,167 @@
+// internal/common/llm/genai.go
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GenAI calls the internal GenAI service at POST /api/ai/generate. The
+// service takes a single prompt, so the system prompt is put in front of
+// the last user message; earlier turns are sent as history.
+type GenAI struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewGenAI creates a GenAI provider. A nil client uses one without timeout.
+func NewGenAI(baseURL, apiKey string, client *http.Client) *GenAI {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &GenAI{baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, client: client}
+}
+
+func (p *GenAI) Name() string { return ProviderGenAI }
+
+func (p *GenAI) Generate(ctx context.Context, req *Request) (*Response, error) {
+	var out struct {
+		Text       string   `json:"text"`
+		Confidence float64  `json:"confidence"`
+		Sources    []string `json:"sources"`
+		Model      string   `json:"model"`
+		Usage      struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/api/ai/generate", p.headers(), p.body(req), &out); err != nil {
+		return nil, err
+	}
+	return &Response{
+		Text:       out.Text,
+		Confidence: out.Confidence,
+		Sources:    out.Sources,
+		Model:      out.Model,
+		Usage:      Usage{PromptTokens: out.Usage.PromptTokens, CompletionTokens: out.Usage.CompletionTokens},
+	}, nil
+}
+
+// Stream asks the service for server-sent events: {"delta": "..."} while
+// generating, then one {"done": true, ...} event carrying confidence,
+// sources, finish_reason and usage.
+func (p *GenAI) Stream(ctx context.Context, req *Request, onChunk func(Chunk) error) (*Response, error) {
+	body := p.body(req)
+	body["stream"] = true
+
+	stream, err := postStream(ctx, p.client, p.Name(), p.baseURL+"/api/ai/generate", p.headers(), body)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	var done bool
+	resp := &Response{}
+	err = readSSE(stream, func(data string) error {
+		var event struct {
+			Delta        string   `json:"delta"`
+			Done         bool     `json:"done"`
+			Confidence   float64  `json:"confidence"`
+			Sources      []string `json:"sources"`
+			FinishReason string   `json:"finish_reason"`
+			Usage        struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return decodeError(p.Name(), err)
+		}
+		if event.Done {
+			done = true
+			resp.Confidence = event.Confidence
+			resp.Sources = event.Sources
+			resp.FinishReason = event.FinishReason
+			resp.Usage = Usage{PromptTokens: event.Usage.PromptTokens, CompletionTokens: event.Usage.CompletionTokens}
+		}
+		return emit(&text, event.Delta, onChunk)
+	})
+	if err == nil && !done {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, streamError(ctx, p.Name(), err)
+	}
+	resp.Text = text.String()
+	return resp, nil
+}
+
+func (p *GenAI) body(req *Request) map[string]interface{} {
+	prompt, history := lastUserMessage(req.Messages)
+	if req.SystemPrompt != "" {
+		prompt = req.SystemPrompt + "\n\n" + prompt
+	}
+
+	body := map[string]interface{}{
+		"prompt":      prompt,
+		"context":     req.Context,
+		"max_tokens":  req.MaxTokens,
+		"temperature": req.Temperature,
+	}
+	if len(history) > 0 {
+		body["history"] = history
+	}
+	if len(req.Stop) > 0 {
+		body["stop"] = req.Stop
+	}
+	return body
+}
+
+func (p *GenAI) headers() map[string]string {
+	if p.apiKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.apiKey}
+}
+
+// postJSON sends body as JSON and decodes a 200 answer into out, mapping
+// failures with statusError, transportError and decodeError.
+func postJSON(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return &Error{Kind: ErrFailed, Provider: provider, Err: fmt.Errorf("encode request: %w", err)}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return &Error{Kind: ErrFailed, Provider: provider, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return transportError(ctx, provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return statusError(provider, resp.StatusCode, string(msg))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		if ctx.Err() != nil {
+			return transportError(ctx, provider, err)
+		}
+		return decodeError(provider, err)
+	}
+	return nil
+}
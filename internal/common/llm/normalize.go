@@ -0,0 +1,16 @@
+// internal/common/llm/normalize.go
+package llm
+
+import (
+	"regexp"
+	"strings"
+)
+
+var nonWordPattern = regexp.MustCompile(`[^\p{L}\p{N}$'%]+`)
+
+// NormalizeQuery lowercases a question or search query and reduces its
+// punctuation and spacing, so that texts differing only in those share a
+// cache entry.
+func NormalizeQuery(text string) string {
+	return strings.TrimSpace(nonWordPattern.ReplaceAllString(strings.ToLower(text), " "))
+}
@@ -0,0 +1,27 @@
+// internal/common/llm/normalize_test.go
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"  McDonald's franchise — cost, $1M?", "mcdonald's franchise cost $1m"},
+		{"Coffee  Franchise!", "coffee franchise"},
+		{"ROI of 15% or more?", "roi of 15% or more"},
+		{"Café\tcrêpes\n", "café crêpes"},
+		{"?!", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeQuery(tt.text))
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// internal/common/llm/ollama.go
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Ollama calls a local Ollama server at POST {baseURL}/api/chat.
+type Ollama struct {
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewOllama creates an Ollama provider. A nil client uses one without
+// timeout.
+func NewOllama(baseURL, model string, client *http.Client) *Ollama {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if baseURL == "" {
+		baseURL = "http://localhost:11434"
+	}
+	return &Ollama{baseURL: strings.TrimRight(baseURL, "/"), model: model, client: client}
+}
+
+func (p *Ollama) Name() string { return ProviderOllama }
+
+func (p *Ollama) Generate(ctx context.Context, req *Request) (*Response, error) {
+	var out ollamaChat
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/api/chat", nil, p.body(req, false), &out); err != nil {
+		return nil, busyError(err)
+	}
+	resp := &Response{Text: out.Message.Content, Confidence: -1}
+	out.finish(resp)
+	return resp, nil
+}
+
+// Stream reads the newline-delimited JSON objects Ollama sends while
+// generating; the last one has done set and carries the counts.
+func (p *Ollama) Stream(ctx context.Context, req *Request, onChunk func(Chunk) error) (*Response, error) {
+	stream, err := postStream(ctx, p.client, p.Name(), p.baseURL+"/api/chat", nil, p.body(req, true))
+	if err != nil {
+		return nil, busyError(err)
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	var done bool
+	resp := &Response{Confidence: -1}
+	err = readNDJSON(stream, func(line []byte) error {
+		var chunk ollamaChat
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return decodeError(p.Name(), err)
+		}
+		if chunk.Error != "" {
+			return &Error{Kind: ErrFailed, Provider: p.Name(), Err: errors.New(chunk.Error)}
+		}
+		if chunk.Done {
+			done = true
+			chunk.finish(resp)
+		}
+		return emit(&text, chunk.Message.Content, onChunk)
+	})
+	if err == nil && !done {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, streamError(ctx, p.Name(), err)
+	}
+	resp.Text = text.String()
+	return resp, nil
+}
+
+func (p *Ollama) body(req *Request, stream bool) map[string]interface{} {
+	options := map[string]interface{}{
+		"temperature": req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	return map[string]interface{}{
+		"model":    p.model,
+		"messages": chatMessages(req),
+		"stream":   stream,
+		"options":  options,
+	}
+}
+
+// ollamaChat is an /api/chat answer, or one line of it when streaming.
+type ollamaChat struct {
+	Model           string  `json:"model"`
+	Message         Message `json:"message"`
+	Done            bool    `json:"done"`
+	DoneReason      string  `json:"done_reason"`
+	PromptEvalCount int     `json:"prompt_eval_count"`
+	EvalCount       int     `json:"eval_count"`
+	Error           string  `json:"error"`
+}
+
+func (c *ollamaChat) finish(resp *Response) {
+	resp.FinishReason = c.DoneReason
+	resp.Model = c.Model
+	resp.Usage = Usage{PromptTokens: c.PromptEvalCount, CompletionTokens: c.EvalCount}
+}
+
+// busyError treats a 503 from Ollama, which it sends when its request queue
+// is full, like rate limiting.
+func busyError(err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusServiceUnavailable {
+		e.Kind = ErrTimeout
+	}
+	return err
+}
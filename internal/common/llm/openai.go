@@ -0,0 +1,159 @@
+// internal/common/llm/openai.go
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI calls an OpenAI-compatible chat-completions API at
+// POST {baseURL}/chat/completions, e.g. https://api.openai.com/v1 or a
+// vLLM or LiteLLM gateway.
+type OpenAI struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAI creates an OpenAI-compatible provider. A nil client uses one
+// without timeout.
+func NewOpenAI(baseURL, apiKey, model string, client *http.Client) *OpenAI {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	return &OpenAI{baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, model: model, client: client}
+}
+
+func (p *OpenAI) Name() string { return ProviderOpenAI }
+
+func (p *OpenAI) Generate(ctx context.Context, req *Request) (*Response, error) {
+	var out struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message      Message `json:"message"`
+			FinishReason string  `json:"finish_reason"`
+		} `json:"choices"`
+		Usage openAIUsage `json:"usage"`
+	}
+	if err := postJSON(ctx, p.client, p.Name(), p.baseURL+"/chat/completions", p.headers(), p.body(req), &out); err != nil {
+		return nil, quotaError(err)
+	}
+	if len(out.Choices) == 0 {
+		return nil, decodeError(p.Name(), errors.New("no choices in response"))
+	}
+
+	return &Response{
+		Text:         out.Choices[0].Message.Content,
+		Confidence:   -1,
+		FinishReason: out.Choices[0].FinishReason,
+		Model:        out.Model,
+		Usage:        out.Usage.usage(),
+	}, nil
+}
+
+// Stream reads the chat-completion chunks sent as server-sent events until
+// [DONE]. Usage arrives in a last chunk without choices.
+func (p *OpenAI) Stream(ctx context.Context, req *Request, onChunk func(Chunk) error) (*Response, error) {
+	body := p.body(req)
+	body["stream"] = true
+	body["stream_options"] = map[string]interface{}{"include_usage": true}
+
+	stream, err := postStream(ctx, p.client, p.Name(), p.baseURL+"/chat/completions", p.headers(), body)
+	if err != nil {
+		return nil, quotaError(err)
+	}
+	defer stream.Close()
+
+	var text strings.Builder
+	var done bool
+	resp := &Response{Confidence: -1}
+	err = readSSE(stream, func(data string) error {
+		if data == "[DONE]" {
+			done = true
+			return nil
+		}
+		var chunk struct {
+			Model   string `json:"model"`
+			Choices []struct {
+				Delta        Message `json:"delta"`
+				FinishReason string  `json:"finish_reason"`
+			} `json:"choices"`
+			Usage *openAIUsage `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return decodeError(p.Name(), err)
+		}
+		if chunk.Model != "" {
+			resp.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			resp.Usage = chunk.Usage.usage()
+		}
+		if len(chunk.Choices) == 0 {
+			return nil
+		}
+		if reason := chunk.Choices[0].FinishReason; reason != "" {
+			resp.FinishReason = reason
+		}
+		return emit(&text, chunk.Choices[0].Delta.Content, onChunk)
+	})
+	if err == nil && !done {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, streamError(ctx, p.Name(), err)
+	}
+	resp.Text = text.String()
+	return resp, nil
+}
+
+func (p *OpenAI) body(req *Request) map[string]interface{} {
+	body := map[string]interface{}{
+		"model":       p.model,
+		"messages":    chatMessages(req),
+		"temperature": req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if len(req.Stop) > 0 {
+		body["stop"] = req.Stop
+	}
+	return body
+}
+
+func (p *OpenAI) headers() map[string]string {
+	if p.apiKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + p.apiKey}
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func (u openAIUsage) usage() Usage {
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+// quotaError turns a 429 caused by an exhausted quota, rather than by rate
+// limiting, into a failure that retrying will not fix.
+func quotaError(err error) error {
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusTooManyRequests &&
+		strings.Contains(e.Err.Error(), "insufficient_quota") {
+		e.Kind = ErrFailed
+		e.Retryable = false
+	}
+	return err
+}
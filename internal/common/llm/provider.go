@@ -0,0 +1,191 @@
+// internal/common/llm/provider.go
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Provider names accepted in apis.llm.provider.
+const (
+	ProviderGenAI  = "genai"
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// Errors every provider maps its failures to. They carry the BPMN error
+// codes llm-synthesis reports.
+var (
+	// ErrTimeout covers deadlines, gateway timeouts and rate limiting:
+	// the call may succeed if tried again later.
+	ErrTimeout = errors.New("LLM_TIMEOUT")
+	// ErrFailed covers everything else.
+	ErrFailed = errors.New("LLM_SYNTHESIS_FAILED")
+)
+
+// Role is the author of a chat message.
+type Role string
+
+const (
+	RoleSystem    Role = "system"
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+)
+
+// Message is one turn of a conversation.
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request is a provider-neutral generation request.
+type Request struct {
+	SystemPrompt string
+	// Messages is the conversation so far, ending with the user turn to
+	// answer.
+	Messages    []Message
+	MaxTokens   int
+	Temperature float64
+	Stop        []string
+	// Context is structured data for providers that accept it next to the
+	// prompt (the internal GenAI service). Chat providers ignore it.
+	Context map[string]interface{}
+}
+
+// Usage is the token count reported by the provider, zero when unknown.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Response is a provider-neutral generation result.
+type Response struct {
+	Text string
+	// Confidence is only reported by the internal GenAI service; chat
+	// providers leave it at -1.
+	Confidence   float64
+	Sources      []string
+	FinishReason string
+	Model        string
+	Usage        Usage
+}
+
+// Provider generates text from a conversation.
+type Provider interface {
+	Name() string
+	Generate(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Error is a failed provider call. It matches ErrTimeout or ErrFailed with
+// errors.Is, depending on Kind.
+type Error struct {
+	Kind       error
+	Provider   string
+	StatusCode int
+	// Retryable reports whether the same request may succeed if sent again:
+	// transport errors, 408, 429 and 5xx statuses are; other statuses and
+	// undecodable answers are not.
+	Retryable bool
+	Err       error
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%v: %s: status %d: %v", e.Kind, e.Provider, e.StatusCode, e.Err)
+	}
+	return fmt.Sprintf("%v: %s: %v", e.Kind, e.Provider, e.Err)
+}
+
+func (e *Error) Is(target error) bool { return target == e.Kind }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// IsRetryable reports whether err is a provider error worth retrying.
+func IsRetryable(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Retryable
+}
+
+// statusError maps an HTTP error status to ErrTimeout or ErrFailed. Rate
+// limiting and gateway timeouts become ErrTimeout, like a deadline would.
+// Only those and server errors are retryable: any other 4xx rejects the
+// request itself, which sending it again will not change.
+func statusError(provider string, status int, body string) error {
+	kind := ErrFailed
+	switch status {
+	case http.StatusTooManyRequests, http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		kind = ErrTimeout
+	}
+	return &Error{
+		Kind:       kind,
+		Provider:   provider,
+		StatusCode: status,
+		Retryable:  kind == ErrTimeout || status >= http.StatusInternalServerError,
+		Err:        errors.New(strings.TrimSpace(body)),
+	}
+}
+
+// transportError maps a failed round trip. A deadline on ctx or on the
+// client becomes ErrTimeout.
+func transportError(ctx context.Context, provider string, err error) error {
+	kind := ErrFailed
+	var netErr interface{ Timeout() bool }
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		kind = ErrTimeout
+	}
+	return &Error{Kind: kind, Provider: provider, Retryable: ctx.Err() == nil, Err: err}
+}
+
+// decodeError reports an answer that could not be understood.
+func decodeError(provider string, err error) error {
+	return &Error{Kind: ErrFailed, Provider: provider, Err: fmt.Errorf("decode error: %w", err)}
+}
+
+// lastUserMessage returns the content of the final user turn and the
+// messages before it.
+func lastUserMessage(messages []Message) (string, []Message) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == RoleUser {
+			return messages[i].Content, messages[:i]
+		}
+	}
+	return "", messages
+}
+
+// chatMessages returns the messages of req with the system prompt first, the
+// shape chat-style APIs expect.
+func chatMessages(req *Request) []Message {
+	messages := make([]Message, 0, len(req.Messages)+1)
+	if req.SystemPrompt != "" {
+		messages = append(messages, Message{Role: RoleSystem, Content: req.SystemPrompt})
+	}
+	return append(messages, req.Messages...)
+}
+
+// Options configures a provider.
+type Options struct {
+	BaseURL string
+	APIKey  string
+	Model   string
+	// Timeout bounds each HTTP call; zero leaves it to the caller's context.
+	Timeout time.Duration
+}
+
+// New creates the provider registered under name.
+func New(name string, opts Options) (Provider, error) {
+	client := &http.Client{Timeout: opts.Timeout}
+	switch strings.ToLower(name) {
+	case "", ProviderGenAI:
+		return NewGenAI(opts.BaseURL, opts.APIKey, client), nil
+	case ProviderOpenAI:
+		return NewOpenAI(opts.BaseURL, opts.APIKey, opts.Model, client), nil
+	case ProviderOllama:
+		return NewOllama(opts.BaseURL, opts.Model, client), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", name)
+	}
+}
@@ -0,0 +1,71 @@
+// internal/common/llm/provider_test.go
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		status    int
+		kind      error
+		retryable bool
+	}{
+		{http.StatusBadRequest, ErrFailed, false},
+		{http.StatusUnauthorized, ErrFailed, false},
+		{http.StatusForbidden, ErrFailed, false},
+		{http.StatusNotFound, ErrFailed, false},
+		{http.StatusRequestTimeout, ErrTimeout, true},
+		{http.StatusConflict, ErrFailed, false},
+		{http.StatusRequestEntityTooLarge, ErrFailed, false},
+		{http.StatusUnprocessableEntity, ErrFailed, false},
+		{http.StatusTooManyRequests, ErrTimeout, true},
+		{http.StatusInternalServerError, ErrFailed, true},
+		{http.StatusBadGateway, ErrFailed, true},
+		{http.StatusServiceUnavailable, ErrFailed, true},
+		{http.StatusGatewayTimeout, ErrTimeout, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			err := statusError("test", tt.status, " upstream said no \n")
+
+			assert.True(t, errors.Is(err, tt.kind), "got %v", err)
+			assert.Equal(t, tt.retryable, IsRetryable(err))
+
+			var e *Error
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tt.status, e.StatusCode)
+			assert.Equal(t, "upstream said no", e.Err.Error())
+		})
+	}
+}
+
+func TestGenAI_StatusRetryable(t *testing.T) {
+	for status, retryable := range map[int]bool{
+		http.StatusUnauthorized:       false,
+		http.StatusTooManyRequests:    true,
+		http.StatusServiceUnavailable: true,
+	} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			_, err := NewGenAI(server.URL, "", nil).Generate(context.Background(), &Request{
+				Messages: []Message{{Role: RoleUser, Content: "Which franchises cost under $100k?"}},
+			})
+
+			require.Error(t, err)
+			assert.Equal(t, retryable, IsRetryable(err))
+		})
+	}
+}
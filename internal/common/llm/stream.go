@@ -0,0 +1,141 @@
+// internal/common/llm/stream.go
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Chunk is a piece of an answer delivered while it is generated.
+type Chunk struct {
+	Delta string
+}
+
+// StreamingProvider is a Provider that can deliver the answer in chunks.
+// Stream calls onChunk for every non-empty chunk, in order, and returns the
+// complete answer once the provider is done. An error from onChunk aborts
+// the stream.
+type StreamingProvider interface {
+	Provider
+	Stream(ctx context.Context, req *Request, onChunk func(Chunk) error) (*Response, error)
+}
+
+// postStream sends body as JSON and returns the body of a 200 answer for the
+// caller to read and close, mapping failures like postJSON.
+func postStream(ctx context.Context, client *http.Client, provider, url string, headers map[string]string, body interface{}) (io.ReadCloser, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, &Error{Kind: ErrFailed, Provider: provider, Err: fmt.Errorf("encode request: %w", err)}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, &Error{Kind: ErrFailed, Provider: provider, Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream, application/x-ndjson")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, transportError(ctx, provider, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, statusError(provider, resp.StatusCode, string(msg))
+	}
+	return resp.Body, nil
+}
+
+// readSSE calls fn with the data of every server-sent event in r. Multi-line
+// data is joined with newlines; comments and other fields are skipped.
+func readSSE(r io.Reader, fn func(data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var data []string
+	flush := func() error {
+		if len(data) == 0 {
+			return nil
+		}
+		event := strings.Join(data, "\n")
+		data = data[:0]
+		return fn(event)
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if err := flush(); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// readNDJSON calls fn with every non-empty line of r.
+func readNDJSON(r io.Reader, fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// callbackError marks an error returned by the onChunk callback so that
+// streamError passes it through unchanged.
+type callbackError struct{ err error }
+
+func (e *callbackError) Error() string { return e.err.Error() }
+
+func (e *callbackError) Unwrap() error { return e.err }
+
+// emit calls onChunk for a non-empty delta and appends it to text.
+func emit(text *strings.Builder, delta string, onChunk func(Chunk) error) error {
+	if delta == "" {
+		return nil
+	}
+	text.WriteString(delta)
+	if err := onChunk(Chunk{Delta: delta}); err != nil {
+		return &callbackError{err}
+	}
+	return nil
+}
+
+// streamError maps an error that ended a stream. Errors from onChunk are
+// returned as they are; a stream cut off midway is not retryable, as the
+// chunks already delivered cannot be taken back.
+func streamError(ctx context.Context, provider string, err error) error {
+	if cb, ok := err.(*callbackError); ok {
+		return cb.err
+	}
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	if ctx.Err() != nil {
+		return transportError(ctx, provider, err)
+	}
+	return &Error{Kind: ErrFailed, Provider: provider, Err: fmt.Errorf("stream: %w", err)}
+}
@@ -38,4 +38,67 @@ var (
 		},
 		[]string{"task_type"},
 	)
+
+	WorkflowErrorsHandled = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "workflow_errors_handled_total",
+			Help: "Total number of BPMN errors handled by error-handler tasks",
+		},
+		[]string{"source", "error_code"},
+	)
+
+	WorkerSchemaViolations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "worker_schema_violations_total",
+			Help: "Total number of job inputs and outputs that did not match the activity schema",
+		},
+		[]string{"task_type", "direction"},
+	)
+
+	AIRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_requests_total",
+			Help: "Total number of GenAI and LLM calls by operation, provider, model and status",
+		},
+		[]string{"operation", "provider", "model", "status"},
+	)
+
+	AITokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_tokens_total",
+			Help: "Total number of prompt and completion tokens of GenAI and LLM calls",
+		},
+		[]string{"operation", "provider", "model", "kind"},
+	)
+
+	AICost = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_cost_usd_total",
+			Help: "Estimated cost of GenAI and LLM calls in US dollars",
+		},
+		[]string{"operation", "provider", "model"},
+	)
+
+	AIRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "ai_request_duration_seconds",
+			Help: "Latency of GenAI and LLM calls in seconds",
+		},
+		[]string{"operation", "provider"},
+	)
+
+	AnswerCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_answer_cache_requests_total",
+			Help: "Total number of answer cache lookups by result (hit, miss) and match (exact, similar, none)",
+		},
+		[]string{"result", "match"},
+	)
+
+	AnswerCacheInvalidations = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ai_answer_cache_invalidations_total",
+			Help: "Total number of cached answers dropped because a franchise they cite was updated",
+		},
+	)
 )
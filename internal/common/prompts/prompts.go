@@ -0,0 +1,219 @@
+// internal/common/prompts/prompts.go
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ErrNoTemplate is returned when no template matches a selection.
+var ErrNoTemplate = errors.New("no prompt template matches")
+
+// Template is a versioned prompt. Intents and Tiers restrict it to those
+// intents and subscription tiers; empty matches any. Templates matching the
+// same selection equally well share its traffic by Weight.
+type Template struct {
+	ID      string   `json:"id"`
+	Version string   `json:"version"`
+	Intents []string `json:"intents,omitempty"`
+	Tiers   []string `json:"tiers,omitempty"`
+	Weight  int      `json:"weight,omitempty"` // share of traffic; 0 counts as 100
+	System  string   `json:"system"`
+	User    string   `json:"user"`
+
+	system *template.Template
+	user   *template.Template
+}
+
+// funcs are available to every template.
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	"join": strings.Join,
+}
+
+func (t *Template) parse() error {
+	var err error
+	if t.system, err = template.New(t.ID + "/system").Funcs(funcs).Parse(t.System); err != nil {
+		return fmt.Errorf("prompt %s@%s: %w", t.ID, t.Version, err)
+	}
+	if t.user, err = template.New(t.ID + "/user").Funcs(funcs).Parse(t.User); err != nil {
+		return fmt.Errorf("prompt %s@%s: %w", t.ID, t.Version, err)
+	}
+	return nil
+}
+
+// Render executes the system and user templates with data.
+func (t *Template) Render(data interface{}) (system, user string, err error) {
+	var b bytes.Buffer
+	if err := t.system.Execute(&b, data); err != nil {
+		return "", "", fmt.Errorf("render prompt %s@%s: %w", t.ID, t.Version, err)
+	}
+	system = strings.TrimSpace(b.String())
+	b.Reset()
+	if err := t.user.Execute(&b, data); err != nil {
+		return "", "", fmt.Errorf("render prompt %s@%s: %w", t.ID, t.Version, err)
+	}
+	return system, strings.TrimSpace(b.String()), nil
+}
+
+func (t *Template) weight() int {
+	if t.Weight <= 0 {
+		return 100
+	}
+	return t.Weight
+}
+
+// score ranks how specifically t matches: an intent match outranks a tier
+// match, which outranks a catch-all. -1 means no match.
+func (t *Template) score(intent, tier string) int {
+	score := 0
+	if len(t.Intents) > 0 {
+		if !contains(t.Intents, intent) {
+			return -1
+		}
+		score += 2
+	}
+	if len(t.Tiers) > 0 {
+		if !contains(t.Tiers, tier) {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// Source loads the current set of templates.
+type Source interface {
+	Load(ctx context.Context) ([]Template, error)
+}
+
+// Registry selects templates from a Source, reloading it at most every ttl
+// so prompt changes go live without a redeploy.
+type Registry struct {
+	source Source
+	ttl    time.Duration
+
+	mu        sync.RWMutex
+	templates []*Template
+	loadedAt  time.Time
+}
+
+func NewRegistry(source Source, ttl time.Duration) *Registry {
+	return &Registry{source: source, ttl: ttl}
+}
+
+// Select returns the template for an intent and subscription tier. Among the
+// most specific matches the choice is weighted and deterministic in key, so
+// that a conversation keeps seeing the same variant.
+func (r *Registry) Select(ctx context.Context, intent, tier, key string) (*Template, error) {
+	templates, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	best := -1
+	var candidates []*Template
+	for _, t := range templates {
+		switch s := t.score(intent, tier); {
+		case s > best:
+			best, candidates = s, []*Template{t}
+		case s == best && s >= 0:
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: intent %q, tier %q", ErrNoTemplate, intent, tier)
+	}
+	return pick(candidates, key), nil
+}
+
+// pick chooses among candidates by weight, bucketing key by hash.
+func pick(candidates []*Template, key string) *Template {
+	total := 0
+	for _, t := range candidates {
+		total += t.weight()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	bucket := int(h.Sum32() % uint32(total))
+	for _, t := range candidates {
+		if bucket < t.weight() {
+			return t
+		}
+		bucket -= t.weight()
+	}
+	return candidates[len(candidates)-1]
+}
+
+// load returns the cached templates, reloading them once ttl has passed. A
+// failed reload keeps serving the previous templates.
+func (r *Registry) load(ctx context.Context) ([]*Template, error) {
+	r.mu.RLock()
+	if r.templates != nil && time.Since(r.loadedAt) < r.ttl {
+		defer r.mu.RUnlock()
+		return r.templates, nil
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.templates != nil && time.Since(r.loadedAt) < r.ttl {
+		return r.templates, nil
+	}
+
+	loaded, err := r.source.Load(ctx)
+	if err == nil {
+		var templates []*Template
+		templates, err = parseAll(loaded)
+		if err == nil {
+			r.templates, r.loadedAt = templates, time.Now()
+			return r.templates, nil
+		}
+	}
+	if r.templates != nil {
+		r.loadedAt = time.Now()
+		return r.templates, nil
+	}
+	return nil, err
+}
+
+func parseAll(loaded []Template) ([]*Template, error) {
+	templates := make([]*Template, 0, len(loaded))
+	seen := make(map[string]bool)
+	for i := range loaded {
+		t := loaded[i]
+		if t.ID == "" || t.Version == "" {
+			return nil, fmt.Errorf("prompt template %d: id and version are required", i)
+		}
+		key := t.ID + "@" + t.Version
+		if seen[key] {
+			return nil, fmt.Errorf("prompt %s defined twice", key)
+		}
+		seen[key] = true
+		if err := t.parse(); err != nil {
+			return nil, err
+		}
+		templates = append(templates, &t)
+	}
+	return templates, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
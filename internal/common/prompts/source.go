@@ -0,0 +1,68 @@
+// internal/common/prompts/source.go
+package prompts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileSource reads templates from a JSON file shaped like
+// configs/prompts.json: {"prompts": [...]}.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Load(ctx context.Context) ([]Template, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("read prompts: %w", err)
+	}
+	var file struct {
+		Prompts []Template `json:"prompts"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse prompts: %w", err)
+	}
+	return file.Prompts, nil
+}
+
+// PostgresSource reads the active templates from the prompt_templates
+// table, one JSON template per row:
+//
+//	CREATE TABLE prompt_templates (
+//	    id         TEXT NOT NULL,
+//	    version    TEXT NOT NULL,
+//	    definition JSONB NOT NULL,
+//	    active     BOOLEAN NOT NULL DEFAULT TRUE,
+//	    PRIMARY KEY (id, version)
+//	);
+type PostgresSource struct {
+	DB *sql.DB
+}
+
+func (s PostgresSource) Load(ctx context.Context) ([]Template, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT id, version, definition FROM prompt_templates WHERE active ORDER BY id, version`)
+	if err != nil {
+		return nil, fmt.Errorf("query prompts: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []Template
+	for rows.Next() {
+		var id, version string
+		var definition []byte
+		if err := rows.Scan(&id, &version, &definition); err != nil {
+			return nil, fmt.Errorf("scan prompt: %w", err)
+		}
+		var t Template
+		if err := json.Unmarshal(definition, &t); err != nil {
+			return nil, fmt.Errorf("parse prompt %s@%s: %w", id, version, err)
+		}
+		t.ID, t.Version = id, version
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
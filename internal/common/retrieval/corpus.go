@@ -0,0 +1,138 @@
+// internal/common/retrieval/corpus.go
+package retrieval
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// LoadCorpus reads the documents to index from Postgres: each franchise's
+// description, its outlets as one document, and its FDD documents from
+//
+//	CREATE TABLE franchise_documents (
+//	    id           TEXT PRIMARY KEY,
+//	    franchise_id TEXT NOT NULL REFERENCES franchises (id),
+//	    title        TEXT NOT NULL,
+//	    content      TEXT NOT NULL,
+//	    url          TEXT
+//	);
+//
+// A non-empty franchiseID loads that franchise only.
+func LoadCorpus(ctx context.Context, db *sql.DB, franchiseID string) ([]Document, error) {
+	names, docs, err := loadDescriptions(ctx, db, franchiseID)
+	if err != nil {
+		return nil, err
+	}
+	outlets, err := loadOutlets(ctx, db, franchiseID, names)
+	if err != nil {
+		return nil, err
+	}
+	fdds, err := loadFDDs(ctx, db, franchiseID)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(docs, outlets...), fdds...), nil
+}
+
+// where restricts a query on column to franchiseID when it is set.
+func where(column, franchiseID string) (string, []interface{}) {
+	if franchiseID == "" {
+		return "", nil
+	}
+	return " WHERE " + column + " = $1", []interface{}{franchiseID}
+}
+
+func loadDescriptions(ctx context.Context, db *sql.DB, franchiseID string) (map[string]string, []Document, error) {
+	clause, args := where("id", franchiseID)
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, name, COALESCE(description, ''), COALESCE(category, '') FROM franchises`+clause+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load franchises: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]string)
+	var docs []Document
+	for rows.Next() {
+		var id, name, description, category string
+		if err := rows.Scan(&id, &name, &description, &category); err != nil {
+			return nil, nil, fmt.Errorf("scan franchise: %w", err)
+		}
+		names[id] = name
+		text := description
+		if category != "" {
+			text = fmt.Sprintf("%s is a %s franchise. %s", name, category, description)
+		}
+		docs = append(docs, Document{
+			ID:          "franchise:" + id,
+			FranchiseID: id,
+			Kind:        KindDescription,
+			Title:       name,
+			Text:        text,
+		})
+	}
+	return names, docs, rows.Err()
+}
+
+// loadOutlets folds each franchise's outlets into one document, one line per
+// outlet, so questions about where a franchise operates find them together.
+func loadOutlets(ctx context.Context, db *sql.DB, franchiseID string, names map[string]string) ([]Document, error) {
+	clause, args := where("franchise_id", franchiseID)
+	rows, err := db.QueryContext(ctx,
+		`SELECT franchise_id, COALESCE(address, ''), COALESCE(city, ''), COALESCE(state, '') FROM franchise_outlets`+clause+` ORDER BY franchise_id, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("load outlets: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []Document
+	var lines []string
+	flush := func() {
+		if len(docs) > 0 {
+			docs[len(docs)-1].Text = strings.Join(lines, "\n")
+		}
+		lines = nil
+	}
+	for rows.Next() {
+		var fid, address, city, state string
+		if err := rows.Scan(&fid, &address, &city, &state); err != nil {
+			return nil, fmt.Errorf("scan outlet: %w", err)
+		}
+		if len(docs) == 0 || docs[len(docs)-1].FranchiseID != fid {
+			flush()
+			docs = append(docs, Document{
+				ID:          "outlets:" + fid,
+				FranchiseID: fid,
+				Kind:        KindOutlets,
+				Title:       strings.TrimSpace(names[fid] + " outlets"),
+			})
+		}
+		lines = append(lines, fmt.Sprintf("%s outlet in %s, %s: %s.", names[fid], city, state, address))
+	}
+	flush()
+	return docs, rows.Err()
+}
+
+func loadFDDs(ctx context.Context, db *sql.DB, franchiseID string) ([]Document, error) {
+	clause, args := where("franchise_id", franchiseID)
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, franchise_id, title, content, COALESCE(url, '') FROM franchise_documents`+clause+` ORDER BY franchise_id, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("load franchise documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []Document
+	for rows.Next() {
+		doc := Document{Kind: KindFDD}
+		var id string
+		if err := rows.Scan(&id, &doc.FranchiseID, &doc.Title, &doc.Text, &doc.URL); err != nil {
+			return nil, fmt.Errorf("scan franchise document: %w", err)
+		}
+		doc.ID = "fdd:" + id
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
@@ -0,0 +1,246 @@
+// internal/common/retrieval/elasticsearch.go
+package retrieval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"camunda-workers/internal/common/config"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// ESIndex keeps chunks in an Elasticsearch index with a dense_vector field
+// and searches them by kNN on the embedding and BM25 on the text at once.
+// Elasticsearch adds up both scores, weighted by the configured boosts.
+type ESIndex struct {
+	client        *elasticsearch.Client
+	index         string
+	numCandidates int
+	vectorBoost   float64
+	keywordBoost  float64
+}
+
+func NewESIndex(client *elasticsearch.Client, cfg config.RetrievalConfig) *ESIndex {
+	return &ESIndex{
+		client:        client,
+		index:         cfg.Index,
+		numCandidates: cfg.NumCandidates,
+		vectorBoost:   cfg.VectorBoost,
+		keywordBoost:  cfg.KeywordBoost,
+	}
+}
+
+// EnsureIndex creates the index for embeddings of dims dimensions unless it
+// exists. recreate drops an existing index first, as needed when the
+// embedding model changes.
+func (x *ESIndex) EnsureIndex(ctx context.Context, dims int, recreate bool) error {
+	res, err := x.client.Indices.Exists([]string{x.index}, x.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("check index %s: %w", x.index, err)
+	}
+	res.Body.Close()
+	exists := res.StatusCode == 200
+
+	if exists && recreate {
+		res, err := x.client.Indices.Delete([]string{x.index}, x.client.Indices.Delete.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("delete index %s: %w", x.index, err)
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return fmt.Errorf("delete index %s: %s", x.index, res.String())
+		}
+		exists = false
+	}
+	if exists {
+		return nil
+	}
+
+	mapping := map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"document_id":  map[string]interface{}{"type": "keyword"},
+				"franchise_id": map[string]interface{}{"type": "keyword"},
+				"kind":         map[string]interface{}{"type": "keyword"},
+				"title":        map[string]interface{}{"type": "text"},
+				"text":         map[string]interface{}{"type": "text"},
+				"url":          map[string]interface{}{"type": "keyword", "index": false},
+				"position":     map[string]interface{}{"type": "integer"},
+				"embedding": map[string]interface{}{
+					"type":       "dense_vector",
+					"dims":       dims,
+					"index":      true,
+					"similarity": "cosine",
+				},
+			},
+		},
+	}
+	body, _ := json.Marshal(mapping)
+	res, err = x.client.Indices.Create(x.index,
+		x.client.Indices.Create.WithContext(ctx),
+		x.client.Indices.Create.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return fmt.Errorf("create index %s: %w", x.index, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("create index %s: %s", x.index, res.String())
+	}
+	return nil
+}
+
+// Index writes chunks with the bulk API, replacing chunks of the same ID.
+func (x *ESIndex) Index(ctx context.Context, chunks []Chunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	for _, c := range chunks {
+		enc.Encode(map[string]interface{}{"index": map[string]interface{}{"_id": c.ID}})
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("encode chunk %s: %w", c.ID, err)
+		}
+	}
+
+	req := esapi.BulkRequest{Index: x.index, Body: &b}
+	res, err := req.Do(ctx, x.client)
+	if err != nil {
+		return fmt.Errorf("bulk index: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("bulk index: %s", res.String())
+	}
+
+	var r struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			ID    string          `json:"_id"`
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return fmt.Errorf("decode bulk response: %w", err)
+	}
+	if r.Errors {
+		for _, item := range r.Items {
+			for _, result := range item {
+				if len(result.Error) > 0 {
+					return fmt.Errorf("bulk index chunk %s: %s", result.ID, result.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteFranchise removes the chunks of a franchise, so a reindex drops
+// passages of documents that no longer exist.
+func (x *ESIndex) DeleteFranchise(ctx context.Context, franchiseID string) error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"franchise_id": franchiseID},
+		},
+	})
+	req := esapi.DeleteByQueryRequest{
+		Index: []string{x.index},
+		Body:  bytes.NewReader(body),
+	}
+	res, err := req.Do(ctx, x.client)
+	if err != nil {
+		return fmt.Errorf("delete passages of franchise %s: %w", franchiseID, err)
+	}
+	defer res.Body.Close()
+	if res.IsError() && res.StatusCode != 404 {
+		return fmt.Errorf("delete passages of franchise %s: %s", franchiseID, res.String())
+	}
+	return nil
+}
+
+// Search runs the hybrid query. A chunk found by only one of kNN and BM25
+// still ranks, with the score of that one.
+func (x *ESIndex) Search(ctx context.Context, q Query) ([]Passage, error) {
+	var filter []interface{}
+	if len(q.FranchiseIDs) > 0 {
+		filter = append(filter, map[string]interface{}{
+			"terms": map[string]interface{}{"franchise_id": q.FranchiseIDs},
+		})
+	}
+
+	query := map[string]interface{}{
+		"size": q.K,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"match": map[string]interface{}{
+						"text": map[string]interface{}{"query": q.Text, "boost": x.keywordBoost},
+					},
+				},
+				"filter": filter,
+			},
+		},
+		"_source": map[string]interface{}{"excludes": []string{"embedding"}},
+	}
+	if len(q.Vector) > 0 {
+		knn := map[string]interface{}{
+			"field":          "embedding",
+			"query_vector":   q.Vector,
+			"k":              q.K,
+			"num_candidates": max(x.numCandidates, q.K),
+			"boost":          x.vectorBoost,
+		}
+		if filter != nil {
+			knn["filter"] = filter
+		}
+		query["knn"] = knn
+	}
+
+	body, _ := json.Marshal(query)
+	req := esapi.SearchRequest{
+		Index: []string{x.index},
+		Body:  strings.NewReader(string(body)),
+	}
+	res, err := req.Do(ctx, x.client)
+	if err != nil {
+		return nil, fmt.Errorf("search passages: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, fmt.Errorf("search passages: %s", res.String())
+	}
+
+	var r struct {
+		Hits struct {
+			Hits []struct {
+				ID     string  `json:"_id"`
+				Score  float64 `json:"_score"`
+				Source Chunk   `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode passages: %w", err)
+	}
+
+	passages := make([]Passage, 0, len(r.Hits.Hits))
+	for _, hit := range r.Hits.Hits {
+		passages = append(passages, Passage{
+			ID:          hit.ID,
+			DocumentID:  hit.Source.DocumentID,
+			FranchiseID: hit.Source.FranchiseID,
+			Kind:        hit.Source.Kind,
+			Title:       hit.Source.Title,
+			Text:        hit.Source.Text,
+			URL:         hit.Source.URL,
+			Score:       hit.Score,
+		})
+	}
+	return passages, nil
+}
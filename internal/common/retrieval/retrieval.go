@@ -0,0 +1,172 @@
+// internal/common/retrieval/retrieval.go
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/llm"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// Document kinds.
+const (
+	KindDescription = "description"
+	KindFDD         = "fdd"
+	KindOutlets     = "outlets"
+)
+
+// Document is a franchise text to retrieve passages from.
+type Document struct {
+	ID          string
+	FranchiseID string
+	Kind        string
+	Title       string
+	Text        string
+	URL         string
+}
+
+// Chunk is an indexed piece of a document with its embedding.
+type Chunk struct {
+	ID          string    `json:"-"`
+	DocumentID  string    `json:"document_id"`
+	FranchiseID string    `json:"franchise_id"`
+	Kind        string    `json:"kind"`
+	Title       string    `json:"title"`
+	Text        string    `json:"text"`
+	URL         string    `json:"url,omitempty"`
+	Position    int       `json:"position"`
+	Embedding   []float32 `json:"embedding,omitempty"`
+}
+
+// Passage is a retrieved chunk. ID is the chunk ID answers cite.
+type Passage struct {
+	ID          string  `json:"id"`
+	DocumentID  string  `json:"documentId"`
+	FranchiseID string  `json:"franchiseId"`
+	Kind        string  `json:"kind"`
+	Title       string  `json:"title"`
+	Text        string  `json:"text"`
+	URL         string  `json:"url,omitempty"`
+	Score       float64 `json:"score"`
+}
+
+// Split cuts a document into chunks of size words, each overlapping the
+// previous one by overlap words so a sentence cut at a boundary is still
+// found whole in one of them.
+func Split(doc Document, size, overlap int) []Chunk {
+	words := strings.Fields(doc.Text)
+	if len(words) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = len(words)
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+
+	var chunks []Chunk
+	for start := 0; ; start += size - overlap {
+		end := start + size
+		if end > len(words) {
+			end = len(words)
+		}
+		chunks = append(chunks, Chunk{
+			ID:          fmt.Sprintf("%s#%d", doc.ID, len(chunks)),
+			DocumentID:  doc.ID,
+			FranchiseID: doc.FranchiseID,
+			Kind:        doc.Kind,
+			Title:       doc.Title,
+			Text:        strings.Join(words[start:end], " "),
+			URL:         doc.URL,
+			Position:    len(chunks),
+		})
+		if end == len(words) {
+			return chunks
+		}
+	}
+}
+
+// Query is a hybrid search: Text is matched with BM25, Vector with kNN.
+// FranchiseIDs, when set, restricts both to those franchises.
+type Query struct {
+	Text         string
+	Vector       []float32
+	K            int
+	FranchiseIDs []string
+}
+
+// Searcher finds the passages best matching a query.
+type Searcher interface {
+	Search(ctx context.Context, q Query) ([]Passage, error)
+}
+
+// Retriever embeds a question and searches for the passages that answer it.
+type Retriever struct {
+	Embedder llm.Embedder
+	Searcher Searcher
+	TopK     int
+}
+
+// Retrieve returns the TopK passages for question.
+func (r *Retriever) Retrieve(ctx context.Context, question string, franchiseIDs []string) ([]Passage, error) {
+	vectors, err := r.Embedder.Embed(ctx, []string{question})
+	if err != nil {
+		return nil, fmt.Errorf("embed question: %w", err)
+	}
+	return r.Searcher.Search(ctx, Query{
+		Text:         question,
+		Vector:       vectors[0],
+		K:            r.TopK,
+		FranchiseIDs: franchiseIDs,
+	})
+}
+
+// NewEmbedder builds the embedder selected by retrieval.provider, which
+// defaults to apis.llm.provider, with the endpoint and key of apis.llm. Like
+// llm-synthesis, the genai provider falls back to apis.genai.
+func NewEmbedder(cfg *config.Config) (llm.Embedder, error) {
+	name := cfg.Retrieval.Provider
+	if name == "" {
+		name = cfg.APIs.LLM.Provider
+	}
+	opts := llm.Options{
+		BaseURL: cfg.APIs.LLM.BaseURL,
+		APIKey:  cfg.APIs.LLM.APIKey,
+		Model:   cfg.Retrieval.Model,
+		Timeout: config.GetDuration(cfg.APIs.LLM.Timeout),
+	}
+	if name == "" || name == llm.ProviderGenAI {
+		if opts.BaseURL == "" {
+			opts.BaseURL = cfg.APIs.GenAI.BaseURL
+		}
+		if opts.APIKey == "" {
+			opts.APIKey = cfg.APIs.GenAI.APIKey
+		}
+	}
+	return llm.NewEmbedder(name, opts)
+}
+
+// NewRetriever builds the retriever configured under retrieval, or nil when
+// retrieval is disabled.
+func NewRetriever(cfg *config.Config, es *elasticsearch.Client) (*Retriever, error) {
+	if !cfg.Retrieval.Enabled {
+		return nil, nil
+	}
+	if es == nil {
+		return nil, fmt.Errorf("retrieval: elasticsearch is not configured")
+	}
+	embedder, err := NewEmbedder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Retriever{
+		Embedder: embedder,
+		Searcher: NewESIndex(es, cfg.Retrieval),
+		TopK:     cfg.Retrieval.TopK,
+	}, nil
+}
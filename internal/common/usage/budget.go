@@ -0,0 +1,66 @@
+// internal/common/usage/budget.go
+package usage
+
+import (
+	"strings"
+	"time"
+
+	"camunda-workers/internal/common/config"
+)
+
+// Budget is a daily limit on a user's AI calls; a zero limit is unlimited.
+type Budget struct {
+	DailyTokens  int
+	DailyCostUSD float64
+}
+
+// Exceeded reports whether spend has reached either limit.
+func (b Budget) Exceeded(spend Spend) bool {
+	return (b.DailyTokens > 0 && spend.Tokens >= b.DailyTokens) ||
+		(b.DailyCostUSD > 0 && spend.CostUSD >= b.DailyCostUSD)
+}
+
+// Budgets are the daily budgets of users, by subscription tier with
+// per-user overrides. Keys are lowercase.
+type Budgets struct {
+	Tiers map[string]Budget
+	Users map[string]Budget
+}
+
+// NewBudgets returns the budgets configured under usage.budgets, or nil when
+// there are none.
+func NewBudgets(cfg config.UsageConfig) *Budgets {
+	if len(cfg.Budgets.Tiers) == 0 && len(cfg.Budgets.Users) == 0 {
+		return nil
+	}
+	b := &Budgets{
+		Tiers: make(map[string]Budget, len(cfg.Budgets.Tiers)),
+		Users: make(map[string]Budget, len(cfg.Budgets.Users)),
+	}
+	for tier, limit := range cfg.Budgets.Tiers {
+		b.Tiers[strings.ToLower(tier)] = Budget(limit)
+	}
+	for user, limit := range cfg.Budgets.Users {
+		b.Users[strings.ToLower(user)] = Budget(limit)
+	}
+	return b
+}
+
+// For returns the budget of a user of tier: the user's own, else the
+// tier's. ok is false when neither is set.
+func (b *Budgets) For(userID, tier string) (budget Budget, ok bool) {
+	if b == nil {
+		return Budget{}, false
+	}
+	if budget, ok = b.Users[strings.ToLower(userID)]; ok {
+		return budget, true
+	}
+	budget, ok = b.Tiers[strings.ToLower(tier)]
+	return budget, ok
+}
+
+// StartOfDay is midnight UTC of the day of t, when daily budgets reset.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,115 @@
+// internal/common/usage/metered.go
+package usage
+
+import (
+	"context"
+	"time"
+
+	"camunda-workers/internal/common/llm"
+)
+
+// Provider returns p recording each call with m under op. The result
+// streams when p does. With a nil m, p is returned as is.
+func Provider(p llm.Provider, m *Meter, op string) llm.Provider {
+	if m == nil || p == nil {
+		return p
+	}
+	mp := &meteredProvider{Provider: p, meter: m, op: op}
+	if sp, ok := p.(llm.StreamingProvider); ok {
+		return &meteredStreamingProvider{meteredProvider: mp, stream: sp}
+	}
+	return mp
+}
+
+type meteredProvider struct {
+	llm.Provider
+	meter *Meter
+	op    string
+}
+
+func (p *meteredProvider) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	start := time.Now()
+	resp, err := p.Provider.Generate(ctx, req)
+	p.record(ctx, req, resp, err, time.Since(start))
+	return resp, err
+}
+
+// record records a call, estimating the tokens the provider did not report.
+// A failed call is recorded with the prompt it was sent and no completion.
+func (p *meteredProvider) record(ctx context.Context, req *llm.Request, resp *llm.Response, err error, latency time.Duration) {
+	r := Record{
+		Operation: p.op,
+		Provider:  p.Name(),
+		Latency:   latency,
+		Success:   err == nil,
+	}
+	if resp != nil {
+		r.Model = resp.Model
+		r.PromptTokens = resp.Usage.PromptTokens
+		r.CompletionTokens = resp.Usage.CompletionTokens
+	}
+	if r.PromptTokens == 0 && r.CompletionTokens == 0 {
+		r.Estimated = true
+		r.PromptTokens = promptTokens(req)
+		if resp != nil {
+			r.CompletionTokens = EstimateTokens(resp.Text)
+		}
+	}
+	p.meter.Record(ctx, r)
+}
+
+type meteredStreamingProvider struct {
+	*meteredProvider
+	stream llm.StreamingProvider
+}
+
+func (p *meteredStreamingProvider) Stream(ctx context.Context, req *llm.Request, onChunk func(llm.Chunk) error) (*llm.Response, error) {
+	start := time.Now()
+	resp, err := p.stream.Stream(ctx, req, onChunk)
+	p.record(ctx, req, resp, err, time.Since(start))
+	return resp, err
+}
+
+func promptTokens(req *llm.Request) int {
+	if req == nil {
+		return 0
+	}
+	n := EstimateTokens(req.SystemPrompt)
+	for _, msg := range req.Messages {
+		n += EstimateTokens(msg.Content)
+	}
+	return n
+}
+
+// Embedder returns e recording each call with m as an OpEmbedding. Embedding
+// APIs rarely report tokens, so they are estimated from the texts. With a
+// nil m, e is returned as is.
+func Embedder(e llm.Embedder, m *Meter) llm.Embedder {
+	if m == nil || e == nil {
+		return e
+	}
+	return &meteredEmbedder{Embedder: e, meter: m}
+}
+
+type meteredEmbedder struct {
+	llm.Embedder
+	meter *Meter
+}
+
+func (e *meteredEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	start := time.Now()
+	vectors, err := e.Embedder.Embed(ctx, texts)
+	tokens := 0
+	for _, text := range texts {
+		tokens += EstimateTokens(text)
+	}
+	e.meter.Record(ctx, Record{
+		Operation:    OpEmbedding,
+		Provider:     e.Name(),
+		PromptTokens: tokens,
+		Estimated:    true,
+		Latency:      time.Since(start),
+		Success:      err == nil,
+	})
+	return vectors, err
+}
@@ -0,0 +1,86 @@
+// internal/common/usage/store.go
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Store keeps records and sums them up for budgets.
+type Store interface {
+	Insert(ctx context.Context, r Record) error
+	// Spent sums the calls made for userID since the given time.
+	Spent(ctx context.Context, userID string, since time.Time) (Spend, error)
+}
+
+// Spend is what a user's AI calls used.
+type Spend struct {
+	Tokens  int
+	CostUSD float64
+}
+
+// PostgresStore keeps records in the ai_usage table:
+//
+//	CREATE TABLE ai_usage (
+//	    id                   BIGSERIAL PRIMARY KEY,
+//	    created_at           TIMESTAMPTZ NOT NULL,
+//	    user_id              TEXT,
+//	    conversation_id      TEXT,
+//	    process_instance_key BIGINT,
+//	    task_type            TEXT,
+//	    operation            TEXT NOT NULL,
+//	    provider             TEXT NOT NULL,
+//	    model                TEXT,
+//	    prompt_tokens        INTEGER NOT NULL,
+//	    completion_tokens    INTEGER NOT NULL,
+//	    estimated            BOOLEAN NOT NULL,
+//	    latency_ms           INTEGER NOT NULL,
+//	    cost_usd             NUMERIC(12, 6) NOT NULL,
+//	    success              BOOLEAN NOT NULL
+//	);
+//	CREATE INDEX ai_usage_user_created ON ai_usage (user_id, created_at);
+//
+// Calls made outside a job, or for a job without userId, have a NULL user.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Insert(ctx context.Context, r Record) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO ai_usage (created_at, user_id, conversation_id, process_instance_key, task_type,
+		     operation, provider, model, prompt_tokens, completion_tokens, estimated, latency_ms, cost_usd, success)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		r.CreatedAt, nullString(r.UserID), nullString(r.ConversationID), nullInt64(r.ProcessInstanceKey), nullString(r.TaskType),
+		r.Operation, r.Provider, nullString(r.Model), r.PromptTokens, r.CompletionTokens, r.Estimated,
+		r.Latency.Milliseconds(), r.CostUSD, r.Success)
+	if err != nil {
+		return fmt.Errorf("insert ai usage: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Spent(ctx context.Context, userID string, since time.Time) (Spend, error) {
+	var spend Spend
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(prompt_tokens + completion_tokens), 0), COALESCE(SUM(cost_usd), 0)
+		 FROM ai_usage WHERE user_id = $1 AND created_at >= $2`,
+		userID, since).Scan(&spend.Tokens, &spend.CostUSD)
+	if err != nil {
+		return Spend{}, fmt.Errorf("sum ai usage of %s: %w", userID, err)
+	}
+	return spend, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullInt64(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: n != 0}
+}
@@ -0,0 +1,169 @@
+// internal/common/usage/usage.go
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/metrics"
+)
+
+// Operations an AI call is recorded under.
+const (
+	OpIntent              = "intent"
+	OpSynthesis           = "synthesis"
+	OpEmbedding           = "embedding"
+	OpPageSummary         = "page_summary"
+	OpConversationSummary = "conversation_summary"
+)
+
+// storeTimeout bounds writing a record, which outlives a job that timed out.
+const storeTimeout = 2 * time.Second
+
+// Scope is who an AI call is made for.
+type Scope struct {
+	UserID             string
+	ConversationID     string
+	ProcessInstanceKey int64
+	TaskType           string
+}
+
+type scopeKey struct{}
+
+type operationKey struct{}
+
+// WithScope returns ctx carrying scope, which the calls made with it are
+// recorded under.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopeFrom returns the scope of ctx, empty when it has none.
+func ScopeFrom(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// WithOperation returns ctx recording calls under op rather than the
+// operation the provider was metered with, e.g. for a conversation summary
+// made with the answer provider.
+func WithOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// Record is one AI call.
+type Record struct {
+	Scope
+	Operation        string
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	// Estimated is set when the provider reported no token counts and they
+	// were estimated from the text.
+	Estimated bool
+	Latency   time.Duration
+	CostUSD   float64
+	Success   bool
+	CreatedAt time.Time
+}
+
+// Meter records AI calls in the Prometheus counters and, with a Store, in
+// the ai_usage table. A nil Meter records nothing.
+type Meter struct {
+	Store  Store
+	Prices Prices
+	// Logger reports records the Store failed to keep; nil drops them
+	// silently.
+	Logger logger.Logger
+}
+
+// New returns the Meter configured under usage, or nil when usage is
+// disabled. Without db the ai_usage table is not written.
+func New(cfg config.UsageConfig, db *sql.DB, log logger.Logger) *Meter {
+	if !cfg.Enabled {
+		return nil
+	}
+	m := &Meter{Prices: make(Prices, len(cfg.Prices)), Logger: log}
+	for name, p := range cfg.Prices {
+		m.Prices[strings.ToLower(name)] = Price{Prompt: p.Prompt, Completion: p.Completion}
+	}
+	if cfg.Store == "postgres" && db != nil {
+		m.Store = NewPostgresStore(db)
+	}
+	return m
+}
+
+// Record prices r and records it. The scope and operation of ctx fill in
+// what r leaves empty. Recording never fails the call: a record the Store
+// cannot keep is logged and dropped.
+func (m *Meter) Record(ctx context.Context, r Record) {
+	if m == nil {
+		return
+	}
+	if r.Scope == (Scope{}) {
+		r.Scope = ScopeFrom(ctx)
+	}
+	if op, ok := ctx.Value(operationKey{}).(string); ok {
+		r.Operation = op
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	r.CostUSD = m.Prices.Cost(r.Provider, r.Model, r.PromptTokens, r.CompletionTokens)
+
+	status := "success"
+	if !r.Success {
+		status = "error"
+	}
+	metrics.AIRequests.WithLabelValues(r.Operation, r.Provider, r.Model, status).Inc()
+	metrics.AIRequestDuration.WithLabelValues(r.Operation, r.Provider).Observe(r.Latency.Seconds())
+	metrics.AITokens.WithLabelValues(r.Operation, r.Provider, r.Model, "prompt").Add(float64(r.PromptTokens))
+	metrics.AITokens.WithLabelValues(r.Operation, r.Provider, r.Model, "completion").Add(float64(r.CompletionTokens))
+	metrics.AICost.WithLabelValues(r.Operation, r.Provider, r.Model).Add(r.CostUSD)
+
+	if m.Store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storeTimeout)
+	defer cancel()
+	if err := m.Store.Insert(ctx, r); err != nil && m.Logger != nil {
+		m.Logger.Warn("failed to record AI usage", map[string]interface{}{
+			"operation": r.Operation,
+			"error":     err.Error(),
+		})
+	}
+}
+
+// Price is the price of a model in US dollars per million tokens.
+type Price struct {
+	Prompt     float64
+	Completion float64
+}
+
+// Prices are keyed by lowercase model name, or by provider name for calls
+// that report no model or a model without a price.
+type Prices map[string]Price
+
+// Cost returns the estimated cost of a call in US dollars, zero for a model
+// without a price.
+func (p Prices) Cost(provider, model string, promptTokens, completionTokens int) float64 {
+	price, ok := p[strings.ToLower(model)]
+	if !ok {
+		price, ok = p[strings.ToLower(provider)]
+	}
+	if !ok {
+		return 0
+	}
+	return (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1e6
+}
+
+// EstimateTokens approximates the token count of s at four characters per
+// token, for providers that report none.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
@@ -3,8 +3,22 @@ package validation
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Mode selects how schema violations are handled at runtime.
+type Mode string
+
+const (
+	ModeStrict Mode = "strict" // violations fail the job
+	ModeWarn   Mode = "warn"   // violations are logged and the job continues
+	ModeOff    Mode = "off"    // no validation
 )
 
 // JSONSchema defines the structure for input/output schemas
@@ -13,22 +27,90 @@ type JSONSchema struct {
 	Properties           map[string]Property `json:"properties"`
 	Required             []string            `json:"required,omitempty"`
 	AdditionalProperties bool                `json:"additionalProperties,omitempty"`
-	PatternProperties    map[string]Property `json:"patternProperties,omitempty"`
+	PatternProperties    map[string]Property `json:"patternProperties,omitempty"` // keyed by regular expression
 }
 
 type Property struct {
-	Type        string              `json:"type"`
-	Description string              `json:"description,omitempty"`
-	Default     interface{}         `json:"default,omitempty"`
-	Minimum     *float64            `json:"minimum,omitempty"`
-	Maximum     *float64            `json:"maximum,omitempty"`
-	Enum        []string            `json:"enum,omitempty"`
-	Pattern     *string             `json:"pattern,omitempty"`
-	MinLength   *int                `json:"minLength,omitempty"`
-	MaxLength   *int                `json:"maxLength,omitempty"`
-	Items       *Property           `json:"items,omitempty"`      // For array validation
-	Properties  map[string]Property `json:"properties,omitempty"` // For nested objects
-	Required    []string            `json:"required,omitempty"`   // For nested objects
+	Type             string              `json:"type"`
+	Types            []string            `json:"-"` // set when "type" is a list, e.g. ["string", "null"]
+	Description      string              `json:"description,omitempty"`
+	Default          interface{}         `json:"default,omitempty"`
+	Minimum          *float64            `json:"minimum,omitempty"`
+	Maximum          *float64            `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64            `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64            `json:"exclusiveMaximum,omitempty"`
+	Enum             []interface{}       `json:"enum,omitempty"`
+	Pattern          *string             `json:"pattern,omitempty"`
+	Format           string              `json:"format,omitempty"` // email, uri, date, date-time or uuid
+	MinLength        *int                `json:"minLength,omitempty"`
+	MaxLength        *int                `json:"maxLength,omitempty"`
+	Items            *Property           `json:"items,omitempty"`      // For array validation
+	MinItems         *int                `json:"minItems,omitempty"`   // For array validation
+	MaxItems         *int                `json:"maxItems,omitempty"`   // For array validation
+	Properties       map[string]Property `json:"properties,omitempty"` // For nested objects
+	Required         []string            `json:"required,omitempty"`   // For nested objects
+
+	// PatternProperties validates nested object fields whose names match a
+	// regular expression key; matching fields are not additional.
+	PatternProperties map[string]Property `json:"patternProperties,omitempty"`
+
+	// AdditionalProperties restricts nested objects to their declared
+	// properties when set to false. Nested objects are open by default.
+	AdditionalProperties *bool `json:"-"`
+}
+
+// UnmarshalJSON accepts the JSON Schema forms the struct fields cannot hold
+// directly: a list of types and an object-valued additionalProperties.
+func (p *Property) UnmarshalJSON(data []byte) error {
+	type plain Property
+	var raw struct {
+		plain
+		Type                 json.RawMessage `json:"type"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Property(raw.plain)
+
+	if len(raw.Type) > 0 {
+		if err := json.Unmarshal(raw.Type, &p.Type); err != nil {
+			if err := json.Unmarshal(raw.Type, &p.Types); err != nil {
+				return fmt.Errorf("type must be a string or a list of strings: %w", err)
+			}
+			p.Type = ""
+		}
+	}
+	if allowed, ok := additionalAllowed(raw.AdditionalProperties); ok {
+		p.AdditionalProperties = &allowed
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts additionalProperties as a boolean or a schema
+// object; an object allows additional properties.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	type plain JSONSchema
+	var raw struct {
+		plain
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = JSONSchema(raw.plain)
+	s.AdditionalProperties, _ = additionalAllowed(raw.AdditionalProperties)
+	return nil
+}
+
+func additionalAllowed(raw json.RawMessage) (allowed bool, set bool) {
+	if len(raw) == 0 {
+		return false, false
+	}
+	if err := json.Unmarshal(raw, &allowed); err != nil {
+		return true, true
+	}
+	return allowed, true
 }
 
 type ValidationResult struct {
@@ -44,13 +126,30 @@ type ValidationError struct {
 
 // ValidateInput validates input against JSON schema with detailed errors
 func ValidateInput(input map[string]interface{}, schema JSONSchema) *ValidationResult {
+	errors := validateObject("", input, schema.Properties, schema.PatternProperties, schema.Required, schema.AdditionalProperties)
+
+	sort.SliceStable(errors, func(i, j int) bool {
+		return errors[i].Field < errors[j].Field
+	})
+
+	return &ValidationResult{
+		Valid:  len(errors) == 0,
+		Errors: errors,
+	}
+}
+
+// validateObject checks input against its declared properties. As in JSON
+// Schema, a field is validated against its property and against every
+// pattern property whose expression matches its name, and is only additional
+// when neither applies.
+func validateObject(prefix string, input map[string]interface{}, properties, patterns map[string]Property, required []string, additional bool) []ValidationError {
 	errors := []ValidationError{}
 
 	// Check required fields
-	for _, requiredField := range schema.Required {
+	for _, requiredField := range required {
 		if _, exists := input[requiredField]; !exists {
 			errors = append(errors, ValidationError{
-				Field:   requiredField,
+				Field:   prefix + requiredField,
 				Message: "required field missing",
 				Code:    "REQUIRED_FIELD_MISSING",
 			})
@@ -59,34 +158,38 @@ func ValidateInput(input map[string]interface{}, schema JSONSchema) *ValidationR
 
 	// Validate field types and constraints
 	for fieldName, value := range input {
-		prop, exists := schema.Properties[fieldName]
-		if !exists {
-			if !schema.AdditionalProperties {
-				errors = append(errors, ValidationError{
-					Field:   fieldName,
-					Message: "field not allowed in schema",
-					Code:    "EXTRA_FIELD",
-				})
+		prop, exists := properties[fieldName]
+		if exists {
+			errors = append(errors, validateField(prefix+fieldName, value, prop)...)
+		}
+
+		matched := false
+		for pattern, patternProp := range patterns {
+			re, err := compilePattern(pattern)
+			if err != nil || !re.MatchString(fieldName) {
+				continue
 			}
-			continue
+			matched = true
+			errors = append(errors, validateField(prefix+fieldName, value, patternProp)...)
 		}
 
-		if fieldErrors := validateField(fieldName, value, prop); len(fieldErrors) > 0 {
-			errors = append(errors, fieldErrors...)
+		if !exists && !matched && !additional {
+			errors = append(errors, ValidationError{
+				Field:   prefix + fieldName,
+				Message: "field not allowed in schema",
+				Code:    "EXTRA_FIELD",
+			})
 		}
 	}
 
-	return &ValidationResult{
-		Valid:  len(errors) == 0,
-		Errors: errors,
-	}
+	return errors
 }
 
 func validateField(fieldName string, value interface{}, prop Property) []ValidationError {
 	errors := []ValidationError{}
 
 	// Type validation
-	if typeErr := validateType(value, prop.Type); typeErr != nil {
+	if typeErr := validateTypes(value, prop); typeErr != nil {
 		errors = append(errors, ValidationError{
 			Field:   fieldName,
 			Message: typeErr.Error(),
@@ -94,18 +197,31 @@ func validateField(fieldName string, value interface{}, prop Property) []Validat
 		})
 		return errors // Return early if type is wrong
 	}
+	if value == nil {
+		return errors
+	}
+
+	// Enum validation
+	if len(prop.Enum) > 0 && !inEnum(value, prop.Enum) {
+		errors = append(errors, ValidationError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("value must be one of %v", prop.Enum),
+			Code:    "INVALID_ENUM_VALUE",
+		})
+	}
 
 	// String validations
 	if strVal, ok := value.(string); ok {
 		// Length validation
-		if prop.MinLength != nil && len(strVal) < *prop.MinLength {
+		length := utf8.RuneCountInString(strVal)
+		if prop.MinLength != nil && length < *prop.MinLength {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Message: fmt.Sprintf("value must be at least %d characters", *prop.MinLength),
 				Code:    "MIN_LENGTH_VIOLATION",
 			})
 		}
-		if prop.MaxLength != nil && len(strVal) > *prop.MaxLength {
+		if prop.MaxLength != nil && length > *prop.MaxLength {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
 				Message: fmt.Sprintf("value must be at most %d characters", *prop.MaxLength),
@@ -115,8 +231,8 @@ func validateField(fieldName string, value interface{}, prop Property) []Validat
 
 		// Pattern validation
 		if prop.Pattern != nil {
-			matched, err := regexp.MatchString(*prop.Pattern, strVal)
-			if err != nil || !matched {
+			re, err := compilePattern(*prop.Pattern)
+			if err != nil || !re.MatchString(strVal) {
 				errors = append(errors, ValidationError{
 					Field:   fieldName,
 					Message: fmt.Sprintf("value must match pattern %s", *prop.Pattern),
@@ -125,72 +241,99 @@ func validateField(fieldName string, value interface{}, prop Property) []Validat
 			}
 		}
 
-		// Enum validation
-		if prop.Enum != nil && len(prop.Enum) > 0 {
-			found := false
-			for _, enumVal := range prop.Enum {
-				if strVal == enumVal {
-					found = true
-					break
-				}
-			}
-			if !found {
-				errors = append(errors, ValidationError{
-					Field:   fieldName,
-					Message: fmt.Sprintf("value must be one of %v", prop.Enum),
-					Code:    "INVALID_ENUM_VALUE",
-				})
-			}
+		// Format validation
+		if prop.Format != "" && !validFormat(prop.Format, strVal) {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("value must be a valid %s", prop.Format),
+				Code:    "INVALID_FORMAT",
+			})
 		}
 	}
 
 	// Number range validation
-	if numVal, ok := value.(float64); ok {
+	if numVal, ok := toFloat(value); ok {
 		if prop.Minimum != nil && numVal < *prop.Minimum {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
-				Message: fmt.Sprintf("value must be >= %f", *prop.Minimum),
+				Message: fmt.Sprintf("value must be >= %v", *prop.Minimum),
 				Code:    "MINIMUM_VIOLATION",
 			})
 		}
 		if prop.Maximum != nil && numVal > *prop.Maximum {
 			errors = append(errors, ValidationError{
 				Field:   fieldName,
-				Message: fmt.Sprintf("value must be <= %f", *prop.Maximum),
+				Message: fmt.Sprintf("value must be <= %v", *prop.Maximum),
 				Code:    "MAXIMUM_VIOLATION",
 			})
 		}
-	}
-
-	// Array validation
-	if arrVal, ok := value.([]interface{}); ok && prop.Items != nil {
-		for i, item := range arrVal {
-			itemErrors := validateField(fmt.Sprintf("%s[%d]", fieldName, i), item, *prop.Items)
-			errors = append(errors, itemErrors...)
+		if prop.ExclusiveMinimum != nil && numVal <= *prop.ExclusiveMinimum {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("value must be > %v", *prop.ExclusiveMinimum),
+				Code:    "MINIMUM_VIOLATION",
+			})
+		}
+		if prop.ExclusiveMaximum != nil && numVal >= *prop.ExclusiveMaximum {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("value must be < %v", *prop.ExclusiveMaximum),
+				Code:    "MAXIMUM_VIOLATION",
+			})
 		}
 	}
 
-	// Nested object validation
-	if objVal, ok := value.(map[string]interface{}); ok && prop.Properties != nil {
-		nestedSchema := JSONSchema{
-			Type:                 "object",
-			Properties:           prop.Properties,
-			Required:             prop.Required,
-			AdditionalProperties: true, // Default to allow additional properties in nested objects
+	// Array validation
+	if arrVal, ok := value.([]interface{}); ok {
+		if prop.MinItems != nil && len(arrVal) < *prop.MinItems {
+			errors = append(errors, ValidationError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("array must have at least %d items", *prop.MinItems),
+				Code:    "MIN_ITEMS_VIOLATION",
+			})
 		}
-		nestedResult := ValidateInput(objVal, nestedSchema)
-		for _, nestedErr := range nestedResult.Errors {
+		if prop.MaxItems != nil && len(arrVal) > *prop.MaxItems {
 			errors = append(errors, ValidationError{
-				Field:   fmt.Sprintf("%s.%s", fieldName, nestedErr.Field),
-				Message: nestedErr.Message,
-				Code:    nestedErr.Code,
+				Field:   fieldName,
+				Message: fmt.Sprintf("array must have at most %d items", *prop.MaxItems),
+				Code:    "MAX_ITEMS_VIOLATION",
 			})
 		}
+		if prop.Items != nil {
+			for i, item := range arrVal {
+				itemErrors := validateField(fmt.Sprintf("%s[%d]", fieldName, i), item, *prop.Items)
+				errors = append(errors, itemErrors...)
+			}
+		}
+	}
+
+	// Nested object validation
+	if objVal, ok := value.(map[string]interface{}); ok {
+		additional := prop.AdditionalProperties == nil || *prop.AdditionalProperties
+		errors = append(errors, validateObject(fieldName+".", objVal, prop.Properties, prop.PatternProperties, prop.Required, additional)...)
 	}
 
 	return errors
 }
 
+// validateTypes checks value against the property's type or list of types.
+// A property without a type accepts any value.
+func validateTypes(value interface{}, prop Property) error {
+	if len(prop.Types) == 0 {
+		if prop.Type == "" {
+			return nil
+		}
+		return validateType(value, prop.Type)
+	}
+
+	for _, t := range prop.Types {
+		if validateType(value, t) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of %v, got %T", prop.Types, value)
+}
+
 func validateType(value interface{}, expectedType string) error {
 	switch expectedType {
 	case "string":
@@ -198,23 +341,14 @@ func validateType(value interface{}, expectedType string) error {
 			return fmt.Errorf("expected string, got %T", value)
 		}
 	case "number":
-		if _, ok := value.(float64); !ok {
-			// Try to convert from int
-			if _, ok := value.(int); !ok {
-				if _, ok := value.(int32); !ok {
-					if _, ok := value.(int64); !ok {
-						return fmt.Errorf("expected number, got %T", value)
-					}
-				}
-			}
+		if _, ok := toFloat(value); !ok {
+			return fmt.Errorf("expected number, got %T", value)
 		}
 	case "integer":
-		if _, ok := value.(int); !ok {
-			if _, ok := value.(int32); !ok {
-				if _, ok := value.(int64); !ok {
-					return fmt.Errorf("expected integer, got %T", value)
-				}
-			}
+		// JSON decodes every number to float64, so accept integral floats.
+		f, ok := toFloat(value)
+		if !ok || f != math.Trunc(f) {
+			return fmt.Errorf("expected integer, got %T", value)
 		}
 	case "boolean":
 		if _, ok := value.(bool); !ok {
@@ -236,6 +370,84 @@ func validateType(value interface{}, expectedType string) error {
 	return nil
 }
 
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	num, isNum := toFloat(value)
+	for _, allowed := range enum {
+		if allowedNum, ok := toFloat(allowed); ok && isNum {
+			if num == allowedNum {
+				return true
+			}
+			continue
+		}
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	dateTimeFormats = []string{time.RFC3339, time.RFC3339Nano}
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// validFormat checks the string formats used in the activity registry.
+// Unknown formats are accepted, as JSON Schema treats them as annotations.
+func validFormat(format, value string) bool {
+	switch format {
+	case "email":
+		return ValidateEmail(value)
+	case "uri", "url":
+		return ValidateURL(value)
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		for _, layout := range dateTimeFormats {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+var patternCache sync.Map // pattern string -> *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
 // ValidateActivityNaming validates activity ID follows naming convention
 func ValidateActivityNaming(activityId string) error {
 	namingPattern := regexp.MustCompile(`^[a-z]+\.[a-z]+\.[a-z]+$`)
@@ -252,6 +464,28 @@ func GetSchemaFromJSON(schemaJSON string) (JSONSchema, error) {
 	return schema, err
 }
 
+// SchemaFromMap converts a decoded schema, such as an activity's inputSchema
+// in the registry, into a JSONSchema. It returns nil for an empty schema.
+// As in JSON Schema, additional properties are allowed unless the schema
+// sets additionalProperties to false.
+func SchemaFromMap(m map[string]interface{}) (*JSONSchema, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := GetSchemaFromJSON(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if _, set := m["additionalProperties"]; !set {
+		schema.AdditionalProperties = true
+	}
+	return &schema, nil
+}
+
 // GetErrorMessages returns a simple list of error messages
 func (vr *ValidationResult) GetErrorMessages() []string {
 	messages := make([]string, len(vr.Errors))
@@ -0,0 +1,284 @@
+// internal/common/validation/schema_test.go
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// violation is a field and code pair reported by ValidateInput.
+type violation struct {
+	Field string
+	Code  string
+}
+
+func violations(result *ValidationResult) []violation {
+	found := []violation{}
+	for _, err := range result.Errors {
+		found = append(found, violation{Field: err.Field, Code: err.Code})
+	}
+	return found
+}
+
+func TestValidateInput_Keywords(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		input    map[string]interface{}
+		expected []violation
+	}{
+		{
+			name:     "required field present",
+			schema:   `{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			input:    map[string]interface{}{"id": "a"},
+			expected: []violation{},
+		},
+		{
+			name:     "required field missing",
+			schema:   `{"type": "object", "properties": {"id": {"type": "string"}}, "required": ["id"]}`,
+			input:    map[string]interface{}{},
+			expected: []violation{{"id", "REQUIRED_FIELD_MISSING"}},
+		},
+		{
+			name:     "extra field rejected by default",
+			schema:   `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			input:    map[string]interface{}{"id": "a", "other": 1.0},
+			expected: []violation{{"other", "EXTRA_FIELD"}},
+		},
+		{
+			name:     "extra field allowed",
+			schema:   `{"type": "object", "properties": {"id": {"type": "string"}}, "additionalProperties": true}`,
+			input:    map[string]interface{}{"id": "a", "other": 1.0},
+			expected: []violation{},
+		},
+		{
+			name:     "wrong type",
+			schema:   `{"type": "object", "properties": {"count": {"type": "integer"}}}`,
+			input:    map[string]interface{}{"count": 1.5},
+			expected: []violation{{"count", "INVALID_TYPE"}},
+		},
+		{
+			name:     "type list accepts null",
+			schema:   `{"type": "object", "properties": {"note": {"type": ["string", "null"]}}}`,
+			input:    map[string]interface{}{"note": nil},
+			expected: []violation{},
+		},
+		{
+			name:     "enum value allowed",
+			schema:   `{"type": "object", "properties": {"tier": {"type": "string", "enum": ["free", "premium"]}}}`,
+			input:    map[string]interface{}{"tier": "premium"},
+			expected: []violation{},
+		},
+		{
+			name:     "enum value rejected",
+			schema:   `{"type": "object", "properties": {"tier": {"type": "string", "enum": ["free", "premium"]}}}`,
+			input:    map[string]interface{}{"tier": "gold"},
+			expected: []violation{{"tier", "INVALID_ENUM_VALUE"}},
+		},
+		{
+			name:     "numeric enum compares by value",
+			schema:   `{"type": "object", "properties": {"level": {"type": "integer", "enum": [1, 2]}}}`,
+			input:    map[string]interface{}{"level": 2},
+			expected: []violation{},
+		},
+		{
+			name:     "pattern matched",
+			schema:   `{"type": "object", "properties": {"code": {"type": "string", "pattern": "^[A-Z]{3}$"}}}`,
+			input:    map[string]interface{}{"code": "ABC"},
+			expected: []violation{},
+		},
+		{
+			name:     "pattern mismatch",
+			schema:   `{"type": "object", "properties": {"code": {"type": "string", "pattern": "^[A-Z]{3}$"}}}`,
+			input:    map[string]interface{}{"code": "abcd"},
+			expected: []violation{{"code", "PATTERN_MISMATCH"}},
+		},
+		{
+			name:     "string length bounds",
+			schema:   `{"type": "object", "properties": {"short": {"type": "string", "minLength": 3}, "long": {"type": "string", "maxLength": 3}}}`,
+			input:    map[string]interface{}{"short": "ab", "long": "abcd"},
+			expected: []violation{{"long", "MAX_LENGTH_VIOLATION"}, {"short", "MIN_LENGTH_VIOLATION"}},
+		},
+		{
+			name:     "length counts runes",
+			schema:   `{"type": "object", "properties": {"city": {"type": "string", "maxLength": 4}}}`,
+			input:    map[string]interface{}{"city": "Nîme"},
+			expected: []violation{},
+		},
+		{
+			name:     "inclusive number bounds",
+			schema:   `{"type": "object", "properties": {"low": {"type": "number", "minimum": 1}, "high": {"type": "number", "maximum": 10}, "edge": {"type": "number", "minimum": 1, "maximum": 10}}}`,
+			input:    map[string]interface{}{"low": 0.5, "high": 11.0, "edge": 10.0},
+			expected: []violation{{"high", "MAXIMUM_VIOLATION"}, {"low", "MINIMUM_VIOLATION"}},
+		},
+		{
+			name:     "exclusive number bounds",
+			schema:   `{"type": "object", "properties": {"low": {"type": "number", "exclusiveMinimum": 1}, "high": {"type": "number", "exclusiveMaximum": 10}}}`,
+			input:    map[string]interface{}{"low": 1.0, "high": 10.0},
+			expected: []violation{{"high", "MAXIMUM_VIOLATION"}, {"low", "MINIMUM_VIOLATION"}},
+		},
+		{
+			name:     "array item count bounds",
+			schema:   `{"type": "object", "properties": {"few": {"type": "array", "minItems": 2}, "many": {"type": "array", "maxItems": 1}}}`,
+			input:    map[string]interface{}{"few": []interface{}{"a"}, "many": []interface{}{"a", "b"}},
+			expected: []violation{{"few", "MIN_ITEMS_VIOLATION"}, {"many", "MAX_ITEMS_VIOLATION"}},
+		},
+		{
+			name:     "array items validated by index",
+			schema:   `{"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string", "maxLength": 3}}}}`,
+			input:    map[string]interface{}{"tags": []interface{}{"ok", 4.0, "toolong"}},
+			expected: []violation{{"tags[1]", "INVALID_TYPE"}, {"tags[2]", "MAX_LENGTH_VIOLATION"}},
+		},
+		{
+			name: "nested object fields",
+			schema: `{"type": "object", "properties": {"address": {"type": "object",
+				"properties": {"city": {"type": "string"}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}},
+				"required": ["city"]}}}`,
+			input: map[string]interface{}{
+				"address": map[string]interface{}{"zip": "7500", "country": "FR"},
+			},
+			expected: []violation{{"address.city", "REQUIRED_FIELD_MISSING"}, {"address.zip", "PATTERN_MISMATCH"}},
+		},
+		{
+			name: "closed nested object",
+			schema: `{"type": "object", "properties": {"address": {"type": "object",
+				"properties": {"city": {"type": "string"}}, "additionalProperties": false}}}`,
+			input: map[string]interface{}{
+				"address": map[string]interface{}{"city": "Lyon", "country": "FR"},
+			},
+			expected: []violation{{"address.country", "EXTRA_FIELD"}},
+		},
+		{
+			name: "objects nested in array items",
+			schema: `{"type": "object", "properties": {"entities": {"type": "array", "items": {"type": "object",
+				"properties": {"type": {"type": "string", "enum": ["location", "budget"]}}, "required": ["type"]}}}}`,
+			input: map[string]interface{}{
+				"entities": []interface{}{
+					map[string]interface{}{"type": "location"},
+					map[string]interface{}{"type": "brand"},
+					map[string]interface{}{},
+				},
+			},
+			expected: []violation{{"entities[1].type", "INVALID_ENUM_VALUE"}, {"entities[2].type", "REQUIRED_FIELD_MISSING"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := GetSchemaFromJSON(tt.schema)
+			require.NoError(t, err)
+
+			result := ValidateInput(tt.input, schema)
+
+			assert.Equal(t, len(tt.expected) == 0, result.Valid)
+			assert.Equal(t, tt.expected, violations(result))
+		})
+	}
+}
+
+func TestValidateInput_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		valid  []string
+		bad    []string
+	}{
+		{"email", []string{"jane@example.com"}, []string{"jane@", "example.com"}},
+		{"uri", []string{"https://example.com/path"}, []string{"example.com", "http://"}},
+		{"url", []string{"http://example.com"}, []string{"not a url"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567", "123e4567-e89b-12d3-a456-42661417400g"}},
+		{"date", []string{"2024-02-29"}, []string{"2023-02-29", "29/02/2024"}},
+		{"date-time", []string{"2024-02-29T10:00:00Z", "2024-02-29T10:00:00.123+01:00"}, []string{"2024-02-29", "2024-02-29 10:00:00"}},
+		{"hostname", []string{"anything goes"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			schema := JSONSchema{
+				Type:       "object",
+				Properties: map[string]Property{"value": {Type: "string", Format: tt.format}},
+			}
+			for _, value := range tt.valid {
+				result := ValidateInput(map[string]interface{}{"value": value}, schema)
+				assert.True(t, result.Valid, "%q should be a valid %s", value, tt.format)
+			}
+			for _, value := range tt.bad {
+				result := ValidateInput(map[string]interface{}{"value": value}, schema)
+				assert.Equal(t, []violation{{"value", "INVALID_FORMAT"}}, violations(result), "%q should not be a valid %s", value, tt.format)
+			}
+		})
+	}
+}
+
+func TestValidateInput_PatternProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		input    map[string]interface{}
+		expected []violation
+	}{
+		{
+			name:     "matching fields are not extra",
+			schema:   `{"type": "object", "properties": {"id": {"type": "string"}}, "patternProperties": {"^x-": {"type": "string"}}}`,
+			input:    map[string]interface{}{"id": "a", "x-trace": "abc"},
+			expected: []violation{},
+		},
+		{
+			name:     "matching fields are validated",
+			schema:   `{"type": "object", "patternProperties": {"^score_": {"type": "number", "maximum": 100}}}`,
+			input:    map[string]interface{}{"score_fit": 120.0, "score_budget": "high"},
+			expected: []violation{{"score_budget", "INVALID_TYPE"}, {"score_fit", "MAXIMUM_VIOLATION"}},
+		},
+		{
+			name:     "unmatched fields are still extra",
+			schema:   `{"type": "object", "patternProperties": {"^x-": {"type": "string"}}}`,
+			input:    map[string]interface{}{"trace": "abc"},
+			expected: []violation{{"trace", "EXTRA_FIELD"}},
+		},
+		{
+			name:     "declared properties also match patterns",
+			schema:   `{"type": "object", "properties": {"x-id": {"type": "string"}}, "patternProperties": {"^x-": {"type": "string", "minLength": 3}}}`,
+			input:    map[string]interface{}{"x-id": "a"},
+			expected: []violation{{"x-id", "MIN_LENGTH_VIOLATION"}},
+		},
+		{
+			name: "nested objects",
+			schema: `{"type": "object", "properties": {"budgets": {"type": "object", "additionalProperties": false,
+				"patternProperties": {"^[a-z]+$": {"type": "integer", "minimum": 0}}}}}`,
+			input: map[string]interface{}{
+				"budgets": map[string]interface{}{"free": 100.0, "premium": -1.0, "Gold": 5.0},
+			},
+			expected: []violation{{"budgets.Gold", "EXTRA_FIELD"}, {"budgets.premium", "MINIMUM_VIOLATION"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := GetSchemaFromJSON(tt.schema)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, violations(ValidateInput(tt.input, schema)))
+		})
+	}
+}
+
+func TestSchemaFromMap(t *testing.T) {
+	schema, err := SchemaFromMap(nil)
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+
+	schema, err = SchemaFromMap(map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, schema.AdditionalProperties, "additional properties are allowed unless disabled")
+
+	schema, err = SchemaFromMap(map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": false,
+	})
+	require.NoError(t, err)
+	assert.False(t, schema.AdditionalProperties)
+}
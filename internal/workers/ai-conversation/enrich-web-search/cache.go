@@ -0,0 +1,47 @@
+// internal/workers/ai-conversation/enrich-web-search/cache.go
+package enrichwebsearch
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"camunda-workers/internal/common/llm"
+)
+
+// cacheKey identifies the results of query: the same normalized text with
+// another freshness or result count is another search.
+func (h *Handler) cacheKey(query SearchQuery) string {
+	return "ai:websearch:" + h.provider.Name() + ":" + query.Freshness + ":" + strconv.Itoa(query.Count) + ":" + llm.NormalizeQuery(query.Text)
+}
+
+// cachedResults returns the results cached for query. A cache failure is a
+// miss.
+func (h *Handler) cachedResults(ctx context.Context, query SearchQuery) ([]Result, bool) {
+	if h.config.Cache == nil {
+		return nil, false
+	}
+	val, err := h.config.Cache.Get(ctx, h.cacheKey(query)).Result()
+	if err != nil {
+		return nil, false
+	}
+	var results []Result
+	if err := json.Unmarshal([]byte(val), &results); err != nil {
+		return nil, false
+	}
+	return results, true
+}
+
+// cacheResults caches non-empty results; an empty page may be a provider
+// hiccup and is searched again.
+func (h *Handler) cacheResults(ctx context.Context, query SearchQuery, results []Result) {
+	if h.config.Cache == nil || len(results) == 0 {
+		return
+	}
+	data, _ := json.Marshal(results)
+	if err := h.config.Cache.Set(ctx, h.cacheKey(query), data, h.config.CacheTTL).Err(); err != nil {
+		h.logger.Warn("failed to cache web search results", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
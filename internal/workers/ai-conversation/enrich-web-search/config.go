@@ -1,7 +1,13 @@
 // internal/workers/ai-conversation/enrich-web-search/config.go
 package enrichwebsearch
 
-import "time"
+import (
+	"time"
+
+	"camunda-workers/internal/common/llm"
+
+	"github.com/redis/go-redis/v9"
+)
 
 type Config struct {
 	SearchAPIBaseURL string
@@ -10,6 +16,36 @@ type Config struct {
 	Timeout          time.Duration
 	MaxResults       int
 	MinRelevance     float64
+
+	// Provider runs the searches; nil searches Google Custom Search with
+	// the settings above.
+	Provider SearchProvider
+
+	// Cache keeps provider results for CacheTTL by normalized query; nil
+	// disables caching.
+	Cache    redis.UniversalClient
+	CacheTTL time.Duration
+
+	// AllowDomains, when set, limits results to these domains and their
+	// subdomains; DenyDomains drops results from them.
+	AllowDomains []string
+	DenyDomains  []string
+
+	// Recency maps a primary intent to the freshness its searches are
+	// restricted to, e.g. market_research to month.
+	Recency map[string]string
+
+	// DeepPages is the number of top results whose pages are fetched,
+	// within DeepTimeout and the job deadline, and turned into excerpts;
+	// 0 keeps search snippets only. DeepIntents limits this to some
+	// primary intents, and Summarizer summarizes the pages; without one
+	// the excerpt is the start of the page text.
+	DeepPages    int
+	DeepIntents  []string
+	DeepTimeout  time.Duration
+	MaxPageBytes int64
+	UserAgent    string
+	Summarizer   llm.Provider
 }
 
 func LoadConfig() *Config {
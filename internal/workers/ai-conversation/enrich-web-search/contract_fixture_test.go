@@ -0,0 +1,48 @@
+// internal/workers/ai-conversation/enrich-web-search/contract_fixture_test.go
+package enrichwebsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(createSearchAPIResponse([]map[string]interface{}{
+					{"link": "https://example.com/franchise", "title": "Franchise", "snippet": "Franchise opportunities", "mime": "text/html"},
+				})))
+			}))
+			t.Cleanup(server.Close)
+
+			config := createTestConfig()
+			config.SearchAPIBaseURL = server.URL
+			return NewHandler(config, NewTestLogger(t)).search(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"WEB_SEARCH_TIMEOUT": func(t *testing.T, ctx context.Context, input *Input) error {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					<-r.Context().Done()
+				}))
+				t.Cleanup(server.Close)
+
+				config := createTestConfig()
+				config.SearchAPIBaseURL = server.URL
+				config.Timeout = 50 * time.Millisecond
+				_, err := NewHandler(config, NewTestLogger(t)).search(ctx, input)
+				return err
+			},
+		},
+		Fallback: "WEB_SEARCH_FAILED",
+		Mappings: errorMappings,
+	}
+}
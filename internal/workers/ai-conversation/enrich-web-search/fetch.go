@@ -0,0 +1,225 @@
+// internal/workers/ai-conversation/enrich-web-search/fetch.go
+package enrichwebsearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	errRobotsDisallowed = errors.New("disallowed by robots.txt")
+	errDomainBlocked    = errors.New("domain is not allowed")
+	errPrivateAddress   = errors.New("address is not public")
+)
+
+// maxRedirects is how many redirects a page or robots.txt fetch follows.
+const maxRedirects = 5
+
+type fetchError struct {
+	status int
+}
+
+func (e *fetchError) Error() string {
+	return fmt.Sprintf("page returned %d", e.status)
+}
+
+// newFetchClients returns the clients of page and robots.txt fetches. Both
+// dial only addresses h.dialAllowed accepts, so neither a result URL nor a
+// redirect reaches the worker's own network, and both check every redirect
+// against the domain lists; page redirects are checked against robots.txt
+// too. Fetches are bounded by the deep mode deadline instead of a timeout.
+func (h *Handler) newFetchClients() (pages, robots *http.Client) {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !h.dialAllowed(ip) {
+				return fmt.Errorf("%w: %s", errPrivateAddress, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // a proxy would be dialed instead of the page
+	transport.DialContext = dialer.DialContext
+
+	pages = &http.Client{Transport: transport, CheckRedirect: h.checkRedirect(true)}
+	robots = &http.Client{Transport: transport, CheckRedirect: h.checkRedirect(false)}
+	return pages, robots
+}
+
+// checkRedirect applies the checks of a result URL to a redirect target.
+// robots.txt redirects skip the robots.txt check, which would wait on the
+// read it is part of.
+func (h *Handler) checkRedirect(robots bool) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return fmt.Errorf("unsupported redirect to %q", req.URL)
+		}
+		if !h.allowedDomain(req.URL.String()) {
+			return errDomainBlocked
+		}
+		if robots && !h.robots.allowed(req.Context(), req.URL) {
+			return errRobotsDisallowed
+		}
+		return nil
+	}
+}
+
+// publicAddress reports whether ip is a public unicast address, excluding
+// loopback, private, link-local (cloud metadata), shared and unspecified
+// ranges.
+func publicAddress(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		if ip4[0] == 0 || (ip4[0] == 100 && ip4[1]&0xc0 == 64) {
+			return false
+		}
+		ip = ip4
+	}
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// fetchPage downloads an HTML or plain text page, reading at most MaxPageBytes
+// of it, and returns its readable text.
+func (h *Handler) fetchPage(ctx context.Context, link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("unsupported URL %q", link)
+	}
+	if !h.robots.allowed(ctx, u) {
+		return "", errRobotsDisallowed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", h.userAgent())
+	req.Header.Set("Accept", "text/html,text/plain;q=0.9")
+	resp, err := h.fetchClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &fetchError{status: resp.StatusCode}
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	limit := h.config.MaxPageBytes
+	if limit <= 0 {
+		limit = defaultMaxPageBytes
+	}
+	body := io.LimitReader(resp.Body, limit)
+	switch mediaType {
+	case "text/html", "application/xhtml+xml", "":
+		return extractText(body)
+	case "text/plain":
+		data, err := io.ReadAll(body)
+		return collapseSpace(string(data)), err
+	}
+	return "", fmt.Errorf("unsupported content type %q", mediaType)
+}
+
+// skippedElements hold no readable text.
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Svg: true, atom.Iframe: true, atom.Button: true, atom.Select: true,
+}
+
+// blockElements end a line of text.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Section: true, atom.Article: true, atom.Main: true, atom.Blockquote: true, atom.Pre: true,
+	atom.Table: true, atom.Ul: true, atom.Ol: true, atom.Dd: true, atom.Dt: true,
+}
+
+// extractText returns the text of an HTML page without scripts, navigation
+// and other chrome. The text of <main> or <article> is preferred when the
+// page has one; a page cut off at the size limit is read as far as it goes.
+func extractText(r io.Reader) (string, error) {
+	var all, content strings.Builder
+	skip, inContent := 0, 0
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); err != io.EOF && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return "", err
+			}
+			text := collapseSpace(content.String())
+			if len(text) < 200 {
+				text = collapseSpace(all.String())
+			}
+			return text, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if skippedElements[a] && tt == html.StartTagToken {
+				skip++
+			}
+			if a == atom.Main || a == atom.Article {
+				inContent++
+			}
+			if blockElements[a] {
+				all.WriteByte('\n')
+				content.WriteByte('\n')
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			a := atom.Lookup(name)
+			if skippedElements[a] && skip > 0 {
+				skip--
+			}
+			if (a == atom.Main || a == atom.Article) && inContent > 0 {
+				inContent--
+			}
+			if blockElements[a] {
+				all.WriteByte('\n')
+				content.WriteByte('\n')
+			}
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := string(z.Text())
+			all.WriteString(text)
+			if inContent > 0 {
+				content.WriteString(text)
+			}
+		}
+	}
+}
+
+// collapseSpace reduces runs of spaces to one and drops empty lines.
+func collapseSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
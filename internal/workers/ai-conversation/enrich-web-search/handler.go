@@ -3,17 +3,13 @@ package enrichwebsearch
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
 	"sort"
 	"strings"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const (
@@ -33,120 +29,81 @@ type Logger interface {
 }
 
 type Handler struct {
-	config *Config
-	client *http.Client
-	logger Logger
+	config      *Config
+	client      *http.Client
+	provider    SearchProvider
+	fetchClient *http.Client
+	robots      *robotsCache
+	dialAllowed func(net.IP) bool
+	logger      Logger
 }
 
 func NewHandler(config *Config, log Logger) *Handler {
-	return &Handler{
-		config: config,
-		client: &http.Client{
-			Timeout: config.Timeout,
-		},
+	client := &http.Client{
+		Timeout: config.Timeout,
+	}
+	provider := config.Provider
+	if provider == nil {
+		provider = &GoogleCSE{
+			BaseURL:  config.SearchAPIBaseURL,
+			APIKey:   config.SearchAPIKey,
+			EngineID: config.SearchEngineID,
+			Client:   client,
+		}
+	}
+	h := &Handler{
+		config:      config,
+		client:      client,
+		provider:    provider,
+		dialAllowed: publicAddress,
 		logger: log.With(map[string]interface{}{
 			"taskType": TaskType,
 		}),
 	}
-}
-
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, fmt.Errorf("parse input: %w", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		if errors.Is(err, ErrWebSearchTimeout) {
-			h.failJob(client, job, err, 0)
-		} else {
-			h.logger.Warn("web search failed, returning empty results", map[string]interface{}{
-				"error": err.Error(),
-			})
-			output = &Output{WebData: WebData{Sources: []Source{}, Summary: ""}}
-			h.completeJob(client, job, output)
-		}
-		return
+	if config.DeepPages > 0 {
+		var robotsClient *http.Client
+		h.fetchClient, robotsClient = h.newFetchClients()
+		h.robots = newRobotsCache(robotsClient, h.userAgent())
 	}
+	return h
+}
 
-	h.completeJob(client, job, output)
+func (h *Handler) userAgent() string {
+	return or(h.config.UserAgent, DefaultUserAgent)
 }
 
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
-	query := h.buildQuery(input.Question, input.Entities)
-	searchURL := h.buildSearchURL(query)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
-	if err != nil {
-		return nil, err
+	query := SearchQuery{
+		Text:      h.buildQuery(input.Question, input.Entities),
+		Count:     h.config.MaxResults,
+		Freshness: h.config.Recency[input.Intent.PrimaryIntent],
 	}
 
-	resp, err := h.client.Do(req)
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded ||
-			strings.Contains(err.Error(), "timeout") ||
-			strings.Contains(err.Error(), "deadline") ||
-			strings.Contains(err.Error(), "Client.Timeout") {
-			return nil, ErrWebSearchTimeout
-		}
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search API returned %d", resp.StatusCode)
-	}
-
-	var apiResponse struct {
-		Items []struct {
-			Link    string `json:"link"`
-			Title   string `json:"title"`
-			Snippet string `json:"snippet"`
-			Mime    string `json:"mime"`
-		} `json:"items"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, err
-	}
-
-	// Convert apiResponse.Items to the expected type for processResults
-	items := make([]struct {
-		Link    string
-		Title   string
-		Snippet string
-		Mime    string
-	}, len(apiResponse.Items))
-
-	for i, item := range apiResponse.Items {
-		items[i] = struct {
-			Link    string
-			Title   string
-			Snippet string
-			Mime    string
-		}{
-			Link:    item.Link,
-			Title:   item.Title,
-			Snippet: item.Snippet,
-			Mime:    item.Mime,
+	items, cached := h.cachedResults(ctx, query)
+	if !cached {
+		var err error
+		items, err = h.provider.Search(ctx, query)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded ||
+				strings.Contains(err.Error(), "timeout") ||
+				strings.Contains(err.Error(), "deadline") ||
+				strings.Contains(err.Error(), "Client.Timeout") {
+				return nil, ErrWebSearchTimeout
+			}
+			return nil, err
 		}
+		h.cacheResults(ctx, query, items)
 	}
 
 	sources := h.processResults(items)
+	h.deepen(ctx, input, sources)
 	summary := h.generateSummary(sources)
 
 	h.logger.Info("web search completed", map[string]interface{}{
-		"query":       query,
+		"query":       query.Text,
+		"provider":    h.provider.Name(),
+		"freshness":   query.Freshness,
+		"cached":      cached,
 		"resultCount": len(sources),
 	})
 
@@ -173,23 +130,7 @@ func (h *Handler) buildQuery(question string, entities []Entity) string {
 	return query
 }
 
-func (h *Handler) buildSearchURL(query string) string {
-	baseURL, _ := url.Parse(h.config.SearchAPIBaseURL)
-	params := url.Values{}
-	params.Add("key", h.config.SearchAPIKey)
-	params.Add("cx", h.config.SearchEngineID)
-	params.Add("q", query)
-	params.Add("num", fmt.Sprintf("%d", h.config.MaxResults))
-	baseURL.RawQuery = params.Encode()
-	return baseURL.String()
-}
-
-func (h *Handler) processResults(items []struct {
-	Link    string
-	Title   string
-	Snippet string
-	Mime    string
-}) []Source {
+func (h *Handler) processResults(items []Result) []Source {
 	seen := make(map[string]bool)
 	var sources []Source
 
@@ -199,6 +140,10 @@ func (h *Handler) processResults(items []struct {
 			continue
 		}
 
+		if !h.allowedDomain(item.Link) {
+			continue
+		}
+
 		// Dedupe by URL
 		if seen[item.Link] {
 			continue
@@ -237,67 +182,73 @@ func (h *Handler) processResults(items []struct {
 	return sources
 }
 
-func (h *Handler) generateSummary(sources []Source) string {
-	if len(sources) == 0 {
-		return ""
+// allowedDomain applies the allow and deny lists to a result URL. A domain
+// covers its subdomains; deny wins over allow.
+func (h *Handler) allowedDomain(link string) bool {
+	if len(h.config.AllowDomains) == 0 && len(h.config.DenyDomains) == 0 {
+		return true
 	}
-	// In real system, use LLM or summarization model
-	// For now, return first snippet
-	return sources[0].Snippet
-}
-
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-
+	u, err := url.Parse(link)
 	if err != nil {
-		h.logger.Error("Failed to complete job", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  err.Error(),
-		})
+		return false
 	}
-
-	if _, sendErr := cmd.Send(context.Background()); sendErr != nil {
-		h.logger.Error("Failed to send complete job", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  sendErr.Error(),
-		})
+	host := strings.ToLower(u.Hostname())
+	for _, d := range h.config.DenyDomains {
+		if inDomain(host, d) {
+			return false
+		}
 	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, err error, retries int32) {
-	errorCode := "UNKNOWN_ERROR"
-	if errors.Is(err, ErrWebSearchTimeout) {
-		errorCode = "WEB_SEARCH_TIMEOUT"
+	if len(h.config.AllowDomains) == 0 {
+		return true
 	}
+	for _, d := range h.config.AllowDomains {
+		if inDomain(host, d) {
+			return true
+		}
+	}
+	return false
+}
 
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":    job.Key,
-		"error":     err.Error(),
-		"errorCode": errorCode,
-	})
+func inDomain(host, domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
 
-	_, _ = client.NewFailJobCommand().
-		JobKey(job.Key).
-		Retries(retries).
-		ErrorMessage(err.Error()).
-		Send(context.Background())
+func (h *Handler) generateSummary(sources []Source) string {
+	if len(sources) == 0 {
+		return ""
+	}
+	// The excerpt of a fetched page says more than its snippet
+	if sources[0].Excerpt != "" {
+		return sources[0].Excerpt
+	}
+	return sources[0].Snippet
 }
 
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
 
+// search runs the web search for a job. Only timeouts fail the job; any other
+// search error degrades to an empty result so the conversation can continue.
+func (h *Handler) search(ctx context.Context, input *Input) (*Output, error) {
+	output, err := h.execute(ctx, input)
+	if err != nil && !errors.Is(err, ErrWebSearchTimeout) {
+		h.logger.Warn("web search failed, returning empty results", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return &Output{WebData: WebData{Sources: []Source{}, Summary: ""}}, nil
+	}
+	return output, err
+}
+
 // // internal/workers/ai-conversation/enrich-web-search/handler.go
 // package enrichwebsearch
 
 // import (
 // 	"context"
-// 	"encoding/json"
-// 	"errors"
-// 	"fmt"
-// 	"net/http"
+// // 	"errors"
+// // 	"net/http"
 // 	"net/url"
 // 	"regexp"
 // 	"sort"
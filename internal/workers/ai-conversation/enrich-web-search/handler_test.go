@@ -5,11 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"camunda-workers/internal/common/llm"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -235,14 +242,18 @@ func TestHandler_BuildQuery(t *testing.T) {
 	}
 }
 
-func TestHandler_BuildSearchURL(t *testing.T) {
-	handler := NewHandler(createTestConfig(), NewTestLogger(t))
-	url := handler.buildSearchURL("test query")
+func TestGoogleCSE_SearchURL(t *testing.T) {
+	google := NewHandler(createTestConfig(), NewTestLogger(t)).provider.(*GoogleCSE)
+	url := google.searchURL(SearchQuery{Text: "test query", Count: 5})
 
 	assert.Contains(t, url, "key=test-api-key")
 	assert.Contains(t, url, "cx=test-engine-id")
 	assert.Contains(t, url, "q=test+query")
 	assert.Contains(t, url, "num=5")
+	assert.NotContains(t, url, "dateRestrict")
+
+	url = google.searchURL(SearchQuery{Text: "test query", Count: 5, Freshness: FreshnessMonth})
+	assert.Contains(t, url, "dateRestrict=m1")
 }
 
 func TestHandler_ProcessResults(t *testing.T) {
@@ -286,15 +297,16 @@ func TestHandler_GenerateSummary(t *testing.T) {
 
 func TestHandler_EdgeCases(t *testing.T) {
 	handler := NewHandler(createTestConfig(), NewTestLogger(t))
+	google := handler.provider.(*GoogleCSE)
 
 	t.Run("empty question", func(t *testing.T) {
 		query := handler.buildQuery("", []Entity{})
-		assert.NotPanics(t, func() { handler.buildSearchURL(query) })
+		assert.NotPanics(t, func() { google.searchURL(SearchQuery{Text: query}) })
 	})
 
 	t.Run("special characters in query", func(t *testing.T) {
 		query := handler.buildQuery("What about \"McDonald's\"?", []Entity{})
-		url := handler.buildSearchURL(query)
+		url := google.searchURL(SearchQuery{Text: query})
 		assert.NotEmpty(t, url)
 	})
 
@@ -343,6 +355,588 @@ func TestHandler_FullWorkflow(t *testing.T) {
 	assert.GreaterOrEqual(t, len(output.WebData.Sources), 1)
 }
 
+// ==========================
+// Provider Tests
+// ==========================
+
+func TestProviders_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  func(url string) SearchProvider
+		check     func(t *testing.T, r *http.Request)
+		response  string
+		freshness string
+	}{
+		{
+			name: "bing",
+			provider: func(url string) SearchProvider {
+				return &Bing{BaseURL: url, APIKey: "bing-key", Client: http.DefaultClient}
+			},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "bing-key", r.Header.Get("Ocp-Apim-Subscription-Key"))
+				assert.Equal(t, "Week", r.URL.Query().Get("freshness"))
+				assert.Equal(t, "3", r.URL.Query().Get("count"))
+			},
+			response:  `{"webPages":{"value":[{"url":"https://example.com/a","name":"Bean There","snippet":"Coffee franchise"}]}}`,
+			freshness: FreshnessWeek,
+		},
+		{
+			name: "brave",
+			provider: func(url string) SearchProvider {
+				return &Brave{BaseURL: url, APIKey: "brave-key", Client: http.DefaultClient}
+			},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "brave-key", r.Header.Get("X-Subscription-Token"))
+				assert.Equal(t, "pw", r.URL.Query().Get("freshness"))
+				assert.Equal(t, "3", r.URL.Query().Get("count"))
+			},
+			response:  `{"web":{"results":[{"url":"https://example.com/a","title":"Bean There","description":"Coffee franchise"}]}}`,
+			freshness: FreshnessWeek,
+		},
+		{
+			name: "searxng",
+			provider: func(url string) SearchProvider {
+				return &SearXNG{BaseURL: url, Client: http.DefaultClient}
+			},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "json", r.URL.Query().Get("format"))
+				assert.Equal(t, "week", r.URL.Query().Get("time_range"))
+			},
+			response:  `{"results":[{"url":"https://example.com/a","title":"Bean There","content":"Coffee franchise"}]}`,
+			freshness: FreshnessWeek,
+		},
+		{
+			name: "google",
+			provider: func(url string) SearchProvider {
+				return &GoogleCSE{BaseURL: url, APIKey: "google-key", EngineID: "cx", Client: http.DefaultClient}
+			},
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "google-key", r.URL.Query().Get("key"))
+				assert.Equal(t, "w1", r.URL.Query().Get("dateRestrict"))
+			},
+			response:  `{"items":[{"link":"https://example.com/a","title":"Bean There","snippet":"Coffee franchise","mime":"text/html"}]}`,
+			freshness: FreshnessWeek,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "coffee franchise", r.URL.Query().Get("q"))
+				tt.check(t, r)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			provider := tt.provider(server.URL)
+			assert.Equal(t, tt.name, provider.Name())
+			results, err := provider.Search(context.Background(), SearchQuery{Text: "coffee franchise", Count: 3, Freshness: tt.freshness})
+
+			assert.NoError(t, err)
+			if assert.Len(t, results, 1) {
+				assert.Equal(t, "https://example.com/a", results[0].Link)
+				assert.Equal(t, "Bean There", results[0].Title)
+				assert.Equal(t, "Coffee franchise", results[0].Snippet)
+			}
+		})
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	p, err := NewProvider("", "", "key", "cx", http.DefaultClient)
+	assert.NoError(t, err)
+	assert.Equal(t, ProviderGoogle, p.Name())
+
+	p, err = NewProvider(ProviderBrave, "", "key", "", http.DefaultClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.search.brave.com/res/v1/web/search", p.(*Brave).BaseURL)
+
+	_, err = NewProvider(ProviderSearXNG, "", "", "", http.DefaultClient)
+	assert.Error(t, err, "searxng has no public endpoint")
+
+	_, err = NewProvider("altavista", "", "", "", http.DefaultClient)
+	assert.Error(t, err)
+}
+
+// ==========================
+// Caching and Filter Tests
+// ==========================
+
+func TestHandler_CachesResultsByNormalizedQuery(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createSearchAPIResponse([]map[string]interface{}{
+			{"link": "https://example.com", "title": "Test", "snippet": "Test snippet", "mime": "text/html"},
+		})))
+	}))
+	defer server.Close()
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer mr.Close()
+
+	config := createTestConfig()
+	config.SearchAPIBaseURL = server.URL
+	config.Cache = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	config.CacheTTL = time.Hour
+	handler := NewHandler(config, NewTestLogger(t))
+
+	first, err := handler.execute(context.Background(), &Input{Question: "Coffee franchise costs?"})
+	assert.NoError(t, err)
+	second, err := handler.execute(context.Background(), &Input{Question: "  coffee   franchise COSTS"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, calls, "second question is served from the cache")
+	assert.Equal(t, first, second)
+	assert.True(t, mr.Exists("ai:websearch:google::5:coffee franchise costs"))
+	assert.InDelta(t, time.Hour.Seconds(), mr.TTL("ai:websearch:google::5:coffee franchise costs").Seconds(), 1)
+
+	// Another recency is another search.
+	config.Recency = map[string]string{"market_research": FreshnessMonth}
+	_, err = handler.execute(context.Background(), &Input{Question: "coffee franchise costs", Intent: Intent{PrimaryIntent: "market_research"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// So is another result count.
+	config.MaxResults = 10
+	_, err = NewHandler(config, NewTestLogger(t)).execute(context.Background(), &Input{Question: "coffee franchise costs"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.True(t, mr.Exists("ai:websearch:google::10:coffee franchise costs"))
+}
+
+func TestHandler_CacheFailureStillSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createSearchAPIResponse([]map[string]interface{}{
+			{"link": "https://example.com", "title": "Test", "snippet": "Test snippet", "mime": "text/html"},
+		})))
+	}))
+	defer server.Close()
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+	addr := mr.Addr()
+	mr.Close() // redis is down
+
+	config := createTestConfig()
+	config.SearchAPIBaseURL = server.URL
+	config.Cache = redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	config.CacheTTL = time.Hour
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{Question: "coffee"})
+	assert.NoError(t, err)
+	assert.Len(t, output.WebData.Sources, 1)
+}
+
+func TestHandler_RecencyByIntent(t *testing.T) {
+	var restrict []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restrict = append(restrict, r.URL.Query().Get("dateRestrict"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createSearchAPIResponse(nil)))
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.SearchAPIBaseURL = server.URL
+	config.Recency = map[string]string{"market_research": FreshnessWeek}
+	handler := NewHandler(config, NewTestLogger(t))
+
+	_, err := handler.execute(context.Background(), &Input{Question: "coffee trends", Intent: Intent{PrimaryIntent: "market_research"}})
+	assert.NoError(t, err)
+	_, err = handler.execute(context.Background(), &Input{Question: "coffee costs", Intent: Intent{PrimaryIntent: "cost_inquiry"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"w1", ""}, restrict)
+}
+
+func TestHandler_DomainFilters(t *testing.T) {
+	items := []Result{
+		{Link: "https://www.ftc.gov/franchise", Title: "FTC"},
+		{Link: "https://franchise.org/costs", Title: "IFA"},
+		{Link: "https://spam.example.com/top10", Title: "Spam"},
+		{Link: "https://example.com/page", Title: "Example"},
+		{Link: "https://notftc.gov.evil.com/page", Title: "Lookalike"},
+	}
+
+	t.Run("deny list", func(t *testing.T) {
+		config := createTestConfig()
+		config.DenyDomains = []string{"example.com"}
+		sources := NewHandler(config, NewTestLogger(t)).processResults(items)
+
+		var titles []string
+		for _, s := range sources {
+			titles = append(titles, s.Title)
+		}
+		assert.ElementsMatch(t, []string{"FTC", "IFA", "Lookalike"}, titles)
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		config := createTestConfig()
+		config.AllowDomains = []string{"ftc.gov", "franchise.org", "example.com"}
+		config.DenyDomains = []string{"spam.example.com"}
+		sources := NewHandler(config, NewTestLogger(t)).processResults(items)
+
+		var titles []string
+		for _, s := range sources {
+			titles = append(titles, s.Title)
+		}
+		assert.ElementsMatch(t, []string{"FTC", "IFA", "Example"}, titles)
+	})
+}
+
+// ==========================
+// Deep Mode Tests
+// ==========================
+
+// fakeSummarizer answers every page with a fixed summary and records the
+// requests.
+type fakeSummarizer struct {
+	mu       sync.Mutex
+	requests []*llm.Request
+	err      error
+}
+
+func (f *fakeSummarizer) Name() string { return "fake" }
+
+func (f *fakeSummarizer) Generate(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &llm.Response{Text: "Specialty coffee franchises grew 8% in 2025."}, nil
+}
+
+const articlePage = `<html><head><title>Coffee</title><script>var tracking = 1;</script><style>p{}</style></head>
+<body><nav><a href="/">Home</a> | <a href="/about">About</a></nav>
+<main><h1>Coffee franchise market</h1>
+<p>Specialty coffee franchises grew 8% in 2025, led by drive-through formats.</p>
+<p>Average unit volumes reached $1.2 million, and new openings were concentrated in Texas &amp; Florida.</p>
+<p>Operators expect growth to continue as remote work keeps suburban demand high.</p></main>
+<footer>Copyright 2026</footer></body></html>`
+
+// newSite serves robots.txt disallowing /private, an article, a private
+// page and a slow page, and counts the requests per path.
+func newSite(t *testing.T) (*httptest.Server, map[string]int, *sync.Mutex) {
+	hits := make(map[string]int)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		case "/article", "/private/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(articlePage))
+		case "/slow":
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, hits, &mu
+}
+
+func newDeepHandler(t *testing.T, site string, links ...string) (*Handler, *Config) {
+	items := make([]map[string]interface{}, len(links))
+	for i, link := range links {
+		items[i] = map[string]interface{}{"link": site + link, "title": "Page " + link, "snippet": "Snippet of " + link, "mime": "text/html"}
+	}
+	search := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createSearchAPIResponse(items)))
+	}))
+	t.Cleanup(search.Close)
+
+	config := createTestConfig()
+	config.SearchAPIBaseURL = search.URL
+	config.DeepPages = 2
+	config.DeepTimeout = 2 * time.Second
+	handler := NewHandler(config, NewTestLogger(t))
+	handler.dialAllowed = allowLoopback
+	return handler, config
+}
+
+// allowLoopback lets page fetches reach httptest servers.
+func allowLoopback(ip net.IP) bool { return ip.IsLoopback() }
+
+func sourceByURL(sources []Source, url string) Source {
+	for _, s := range sources {
+		if s.URL == url {
+			return s
+		}
+	}
+	return Source{}
+}
+
+func TestHandler_Deep_SummarizesTopPages(t *testing.T) {
+	site, hits, mu := newSite(t)
+	summarizer := &fakeSummarizer{}
+	handler, config := newDeepHandler(t, site.URL, "/article", "/private/page", "/other")
+	config.Summarizer = summarizer
+
+	output, err := handler.execute(context.Background(), &Input{Question: "How is the coffee franchise market?"})
+
+	assert.NoError(t, err)
+	article := sourceByURL(output.WebData.Sources, site.URL+"/article")
+	assert.Equal(t, "Specialty coffee franchises grew 8% in 2025.", article.Excerpt)
+	assert.Equal(t, "Snippet of /article", article.Snippet)
+	assert.Equal(t, article.Excerpt, output.WebData.Summary)
+
+	// robots.txt disallows /private; /other is beyond the top two pages.
+	assert.Empty(t, sourceByURL(output.WebData.Sources, site.URL+"/private/page").Excerpt)
+	assert.Empty(t, sourceByURL(output.WebData.Sources, site.URL+"/other").Excerpt)
+	mu.Lock()
+	assert.Equal(t, 0, hits["/private/page"])
+	assert.Equal(t, 0, hits["/other"])
+	assert.Equal(t, 1, hits["/robots.txt"])
+	mu.Unlock()
+
+	if assert.Len(t, summarizer.requests, 1) {
+		page := summarizer.requests[0].Messages[0].Content
+		assert.Contains(t, page, "Question: How is the coffee franchise market?")
+		assert.Contains(t, page, "Average unit volumes reached $1.2 million")
+		assert.NotContains(t, page, "tracking")
+		assert.NotContains(t, page, "Copyright")
+	}
+}
+
+func TestHandler_Deep_WithoutSummarizerKeepsPageText(t *testing.T) {
+	site, _, _ := newSite(t)
+	handler, _ := newDeepHandler(t, site.URL, "/article")
+
+	output, err := handler.execute(context.Background(), &Input{Question: "coffee market"})
+
+	assert.NoError(t, err)
+	excerpt := output.WebData.Sources[0].Excerpt
+	assert.True(t, strings.HasPrefix(excerpt, "Coffee franchise market\nSpecialty coffee franchises grew 8% in 2025"), excerpt)
+	assert.Contains(t, excerpt, "Texas & Florida")
+	assert.NotContains(t, excerpt, "Home")
+}
+
+func TestHandler_Deep_SummarizerFailureKeepsPageText(t *testing.T) {
+	site, _, _ := newSite(t)
+	handler, config := newDeepHandler(t, site.URL, "/article")
+	config.Summarizer = &fakeSummarizer{err: errors.New("LLM_TIMEOUT")}
+
+	output, err := handler.execute(context.Background(), &Input{Question: "coffee market"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, output.WebData.Sources[0].Excerpt, "Specialty coffee franchises grew 8%")
+}
+
+func TestHandler_Deep_SlowPageKeepsSnippetWithinDeadline(t *testing.T) {
+	site, _, _ := newSite(t)
+	handler, config := newDeepHandler(t, site.URL, "/slow", "/article")
+	config.DeepTimeout = 0 // bounded by the job deadline only
+
+	ctx, cancel := context.WithTimeout(context.Background(), 800*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	output, err := handler.execute(ctx, &Input{Question: "coffee market"})
+
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 800*time.Millisecond)
+	assert.Empty(t, sourceByURL(output.WebData.Sources, site.URL+"/slow").Excerpt)
+	assert.NotEmpty(t, sourceByURL(output.WebData.Sources, site.URL+"/article").Excerpt)
+}
+
+func TestHandler_Deep_OnlyForConfiguredIntents(t *testing.T) {
+	site, hits, mu := newSite(t)
+	handler, config := newDeepHandler(t, site.URL, "/article")
+	config.DeepIntents = []string{"market_research", "competitor_analysis"}
+
+	output, err := handler.execute(context.Background(), &Input{Question: "coffee costs", Intent: Intent{PrimaryIntent: "cost_inquiry"}})
+	assert.NoError(t, err)
+	assert.Empty(t, output.WebData.Sources[0].Excerpt)
+	mu.Lock()
+	assert.Equal(t, 0, hits["/article"])
+	mu.Unlock()
+
+	output, err = handler.execute(context.Background(), &Input{Question: "coffee trends", Intent: Intent{PrimaryIntent: "market_research"}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, output.WebData.Sources[0].Excerpt)
+}
+
+func TestHandler_Deep_ReadsAtMostMaxPageBytes(t *testing.T) {
+	page := "<html><body><main><p>" + strings.Repeat("coffee ", 100) + "</p><p>END OF PAGE</p></main></body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r) // no robots.txt allows everything
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.DeepPages = 1
+	config.MaxPageBytes = 300
+	handler := NewHandler(config, NewTestLogger(t))
+	handler.dialAllowed = allowLoopback
+
+	text, err := handler.fetchPage(context.Background(), server.URL+"/big")
+	assert.NoError(t, err)
+	assert.Contains(t, text, "coffee coffee")
+	assert.NotContains(t, text, "END OF PAGE")
+}
+
+func TestHandler_Deep_ChecksEveryRedirect(t *testing.T) {
+	var site *httptest.Server
+	site = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		case "/to-private":
+			http.Redirect(w, r, "/private/page", http.StatusFound)
+		case "/to-denied":
+			http.Redirect(w, r, strings.Replace(site.URL, "127.0.0.1", "localhost", 1)+"/article", http.StatusFound)
+		case "/to-metadata":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case "/to-file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/to-article":
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(articlePage))
+		}
+	}))
+	defer site.Close()
+
+	config := createTestConfig()
+	config.DeepPages = 1
+	config.DenyDomains = []string{"localhost"}
+	handler := NewHandler(config, NewTestLogger(t))
+	handler.dialAllowed = allowLoopback
+
+	tests := []struct {
+		path     string
+		expected error
+	}{
+		{"/to-private", errRobotsDisallowed},
+		{"/to-denied", errDomainBlocked},
+		{"/to-metadata", errRobotsDisallowed}, // its robots.txt cannot be dialed
+		{"/to-file", nil},
+		{"/loop", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err := handler.fetchPage(ctx, site.URL+tt.path)
+			assert.Error(t, err)
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+			}
+		})
+	}
+
+	text, err := handler.fetchPage(context.Background(), site.URL+"/to-article")
+	assert.NoError(t, err)
+	assert.Contains(t, text, "Specialty coffee franchises grew 8%")
+}
+
+func TestHandler_Deep_DialsOnlyPublicAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.DeepPages = 1
+	pages, robots := NewHandler(config, NewTestLogger(t)).newFetchClients()
+
+	for _, client := range []*http.Client{pages, robots} {
+		_, err := client.Get(server.URL)
+		assert.ErrorIs(t, err, errPrivateAddress)
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.expected, publicAddress(net.ParseIP(tt.ip)))
+		})
+	}
+}
+
+func TestRobots(t *testing.T) {
+	robots := `# comment
+User-agent: *
+Disallow: /private
+Allow: /private/open
+Disallow: /*.pdf$
+
+User-agent: FranchiseHubBot
+User-agent: OtherBot
+Disallow: /bots-keep-out
+`
+	own := parseRobots(strings.NewReader(robots), DefaultUserAgent)
+	assert.Equal(t, []robotsRule{{allow: false, path: "/bots-keep-out"}}, own, "a named group replaces *")
+
+	rules := parseRobots(strings.NewReader(robots), "SomeCrawler/2.0")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/private", false},
+		{"/private/page", false},
+		{"/private/open/page", true},
+		{"/docs/fdd.pdf", false},
+		{"/docs/fdd.pdf?download=1", true},
+		{"/public", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, allowedBy(rules, tt.path), tt.path)
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	text, err := extractText(strings.NewReader(articlePage))
+	assert.NoError(t, err)
+	assert.Equal(t, "Coffee franchise market\n"+
+		"Specialty coffee franchises grew 8% in 2025, led by drive-through formats.\n"+
+		"Average unit volumes reached $1.2 million, and new openings were concentrated in Texas & Florida.\n"+
+		"Operators expect growth to continue as remote work keeps suburban demand high.", text)
+
+	// Without a <main> the whole body is read.
+	text, err = extractText(strings.NewReader(`<body><div>Short page</div><script>x()</script><p>Two lines</p></body>`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Short page\nTwo lines", text)
+}
+
 // ==========================
 // Benchmark
 // ==========================
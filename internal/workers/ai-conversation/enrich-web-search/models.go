@@ -4,6 +4,7 @@ package enrichwebsearch
 type Input struct {
 	Question string   `json:"question"`
 	Entities []Entity `json:"entities"`
+	Intent   Intent   `json:"intent"`
 }
 
 type Output struct {
@@ -15,6 +16,12 @@ type Entity struct {
 	Value string `json:"value"`
 }
 
+// Intent is the parsed intent; its primary intent selects the recency of
+// the search.
+type Intent struct {
+	PrimaryIntent string `json:"primaryIntent"`
+}
+
 type WebData struct {
 	Sources []Source `json:"sources"`
 	Summary string   `json:"summary"`
@@ -24,5 +31,6 @@ type Source struct {
 	URL       string  `json:"url"`
 	Title     string  `json:"title"`
 	Snippet   string  `json:"snippet"`
+	Excerpt   string  `json:"excerpt,omitempty"`
 	Relevance float64 `json:"relevance"`
 }
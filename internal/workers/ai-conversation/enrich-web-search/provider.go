@@ -0,0 +1,271 @@
+// internal/workers/ai-conversation/enrich-web-search/provider.go
+package enrichwebsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Search providers selectable with apis.web_search.provider.
+const (
+	ProviderGoogle  = "google"
+	ProviderBing    = "bing"
+	ProviderBrave   = "brave"
+	ProviderSearXNG = "searxng"
+)
+
+// Freshness restricts results to pages published within the last day, week,
+// month or year. Empty means any time.
+const (
+	FreshnessDay   = "day"
+	FreshnessWeek  = "week"
+	FreshnessMonth = "month"
+	FreshnessYear  = "year"
+)
+
+// SearchProvider runs a web search.
+type SearchProvider interface {
+	Name() string
+	Search(ctx context.Context, query SearchQuery) ([]Result, error)
+}
+
+// SearchQuery is one search a provider runs.
+type SearchQuery struct {
+	Text      string
+	Count     int
+	Freshness string
+}
+
+// Result is a search hit. Mime is empty when the provider does not report
+// it.
+type Result = struct {
+	Link    string
+	Title   string
+	Snippet string
+	Mime    string
+}
+
+// NewProvider returns the provider called name. An empty baseURL uses the
+// provider's public endpoint; SearXNG has none and needs one.
+func NewProvider(name, baseURL, apiKey, engineID string, client *http.Client) (SearchProvider, error) {
+	switch name {
+	case "", ProviderGoogle:
+		return &GoogleCSE{BaseURL: or(baseURL, "https://www.googleapis.com/customsearch/v1"), APIKey: apiKey, EngineID: engineID, Client: client}, nil
+	case ProviderBing:
+		return &Bing{BaseURL: or(baseURL, "https://api.bing.microsoft.com/v7.0/search"), APIKey: apiKey, Client: client}, nil
+	case ProviderBrave:
+		return &Brave{BaseURL: or(baseURL, "https://api.search.brave.com/res/v1/web/search"), APIKey: apiKey, Client: client}, nil
+	case ProviderSearXNG:
+		if baseURL == "" {
+			return nil, fmt.Errorf("web search provider %q needs a base_url", name)
+		}
+		return &SearXNG{BaseURL: baseURL, Client: client}, nil
+	}
+	return nil, fmt.Errorf("unknown web search provider %q", name)
+}
+
+// ValidFreshness reports whether f is a freshness providers understand.
+func ValidFreshness(f string) bool {
+	switch f {
+	case "", FreshnessDay, FreshnessWeek, FreshnessMonth, FreshnessYear:
+		return true
+	}
+	return false
+}
+
+func or(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// GoogleCSE searches with the Google Custom Search JSON API.
+type GoogleCSE struct {
+	BaseURL  string
+	APIKey   string
+	EngineID string
+	Client   *http.Client
+}
+
+func (g *GoogleCSE) Name() string { return ProviderGoogle }
+
+func (g *GoogleCSE) searchURL(query SearchQuery) string {
+	baseURL, _ := url.Parse(g.BaseURL)
+	params := url.Values{}
+	params.Add("key", g.APIKey)
+	params.Add("cx", g.EngineID)
+	params.Add("q", query.Text)
+	params.Add("num", fmt.Sprintf("%d", query.Count))
+	if restrict, ok := map[string]string{
+		FreshnessDay: "d1", FreshnessWeek: "w1", FreshnessMonth: "m1", FreshnessYear: "y1",
+	}[query.Freshness]; ok {
+		params.Add("dateRestrict", restrict)
+	}
+	baseURL.RawQuery = params.Encode()
+	return baseURL.String()
+}
+
+func (g *GoogleCSE) Search(ctx context.Context, query SearchQuery) ([]Result, error) {
+	var resp struct {
+		Items []struct {
+			Link    string `json:"link"`
+			Title   string `json:"title"`
+			Snippet string `json:"snippet"`
+			Mime    string `json:"mime"`
+		} `json:"items"`
+	}
+	if err := getJSON(ctx, g.Client, g.searchURL(query), nil, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(resp.Items))
+	for i, item := range resp.Items {
+		results[i] = Result{Link: item.Link, Title: item.Title, Snippet: item.Snippet, Mime: item.Mime}
+	}
+	return results, nil
+}
+
+// Bing searches with the Bing Web Search API.
+type Bing struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+func (b *Bing) Name() string { return ProviderBing }
+
+func (b *Bing) Search(ctx context.Context, query SearchQuery) ([]Result, error) {
+	params := url.Values{}
+	params.Add("q", query.Text)
+	params.Add("count", strconv.Itoa(query.Count))
+	params.Add("responseFilter", "Webpages")
+	switch query.Freshness {
+	case FreshnessDay, FreshnessWeek, FreshnessMonth:
+		params.Add("freshness", map[string]string{FreshnessDay: "Day", FreshnessWeek: "Week", FreshnessMonth: "Month"}[query.Freshness])
+	case FreshnessYear:
+		// Bing has no year value but takes a date range.
+		now := time.Now().UTC()
+		params.Add("freshness", now.AddDate(-1, 0, 0).Format("2006-01-02")+".."+now.Format("2006-01-02"))
+	}
+
+	var resp struct {
+		WebPages struct {
+			Value []struct {
+				URL     string `json:"url"`
+				Name    string `json:"name"`
+				Snippet string `json:"snippet"`
+			} `json:"value"`
+		} `json:"webPages"`
+	}
+	headers := map[string]string{"Ocp-Apim-Subscription-Key": b.APIKey}
+	if err := getJSON(ctx, b.Client, b.BaseURL+"?"+params.Encode(), headers, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(resp.WebPages.Value))
+	for i, page := range resp.WebPages.Value {
+		results[i] = Result{Link: page.URL, Title: page.Name, Snippet: page.Snippet}
+	}
+	return results, nil
+}
+
+// Brave searches with the Brave Search API.
+type Brave struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+func (b *Brave) Name() string { return ProviderBrave }
+
+func (b *Brave) Search(ctx context.Context, query SearchQuery) ([]Result, error) {
+	params := url.Values{}
+	params.Add("q", query.Text)
+	params.Add("count", strconv.Itoa(query.Count))
+	if freshness, ok := map[string]string{
+		FreshnessDay: "pd", FreshnessWeek: "pw", FreshnessMonth: "pm", FreshnessYear: "py",
+	}[query.Freshness]; ok {
+		params.Add("freshness", freshness)
+	}
+
+	var resp struct {
+		Web struct {
+			Results []struct {
+				URL         string `json:"url"`
+				Title       string `json:"title"`
+				Description string `json:"description"`
+			} `json:"results"`
+		} `json:"web"`
+	}
+	headers := map[string]string{"X-Subscription-Token": b.APIKey, "Accept": "application/json"}
+	if err := getJSON(ctx, b.Client, b.BaseURL+"?"+params.Encode(), headers, &resp); err != nil {
+		return nil, err
+	}
+	results := make([]Result, len(resp.Web.Results))
+	for i, r := range resp.Web.Results {
+		results[i] = Result{Link: r.URL, Title: r.Title, Snippet: r.Description}
+	}
+	return results, nil
+}
+
+// SearXNG searches a SearXNG instance, or anything serving its JSON format,
+// such as the stub the flow tests run.
+type SearXNG struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func (s *SearXNG) Name() string { return ProviderSearXNG }
+
+func (s *SearXNG) Search(ctx context.Context, query SearchQuery) ([]Result, error) {
+	params := url.Values{}
+	params.Add("q", query.Text)
+	params.Add("format", "json")
+	if query.Freshness != "" {
+		params.Add("time_range", query.Freshness)
+	}
+
+	var resp struct {
+		Results []struct {
+			URL     string `json:"url"`
+			Title   string `json:"title"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, s.Client, s.BaseURL+"?"+params.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	// SearXNG takes no result count.
+	if query.Count > 0 && len(resp.Results) > query.Count {
+		resp.Results = resp.Results[:query.Count]
+	}
+	results := make([]Result, len(resp.Results))
+	for i, r := range resp.Results {
+		results[i] = Result{Link: r.URL, Title: r.Title, Snippet: r.Content}
+	}
+	return results, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, searchURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
+	if err != nil {
+		return err
+	}
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("search API returned %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
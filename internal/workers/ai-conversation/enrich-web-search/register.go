@@ -0,0 +1,97 @@
+// internal/workers/ai-conversation/enrich-web-search/register.go
+package enrichwebsearch
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/usage"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrWebSearchTimeout, Code: "WEB_SEARCH_TIMEOUT"},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		cfg := deps.Config.APIs.WebSearch
+		conf := &Config{
+			SearchAPIBaseURL: cfg.BaseURL,
+			SearchAPIKey:     cfg.APIKey,
+			SearchEngineID:   cfg.EngineID,
+			Timeout:          3 * time.Second,
+			MaxResults:       5,
+			MinRelevance:     0.5,
+			AllowDomains:     cfg.AllowDomains,
+			DenyDomains:      cfg.DenyDomains,
+			Recency:          cfg.Recency,
+		}
+		provider, err := NewProvider(cfg.Provider, cfg.BaseURL, cfg.APIKey, cfg.EngineID, &http.Client{Timeout: conf.Timeout})
+		if err != nil {
+			return nil, err
+		}
+		conf.Provider = provider
+		for intent, freshness := range cfg.Recency {
+			if !ValidFreshness(freshness) {
+				return nil, fmt.Errorf("web search recency %q of intent %s is not day, week, month or year", freshness, intent)
+			}
+		}
+		if cfg.CacheTTL > 0 && deps.Redis != nil {
+			conf.Cache = deps.Redis.Client
+			conf.CacheTTL = time.Duration(cfg.CacheTTL) * time.Minute
+		}
+		if deep := cfg.Deep; deep.Pages > 0 {
+			conf.DeepPages = deep.Pages
+			conf.DeepIntents = deep.Intents
+			conf.DeepTimeout = config.GetDuration(deep.Timeout)
+			conf.MaxPageBytes = int64(deep.MaxPageBytes)
+			conf.UserAgent = deep.UserAgent
+			if deep.Summarize {
+				if conf.Summarizer, err = newSummarizer(deps.Config); err != nil {
+					return nil, err
+				}
+				conf.Summarizer = usage.Provider(conf.Summarizer, deps.Usage(), usage.OpPageSummary)
+			}
+		}
+
+		handler := NewHandler(conf, &loggerAdapter{deps.Logger})
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("WEB_SEARCH_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.search))), nil
+	})
+}
+
+// newSummarizer builds the apis.llm provider pages are summarized with. The
+// genai provider defaults to the apis.genai endpoint and key.
+func newSummarizer(cfg *config.Config) (llm.Provider, error) {
+	opts := llm.Options{
+		BaseURL: cfg.APIs.LLM.BaseURL,
+		APIKey:  cfg.APIs.LLM.APIKey,
+		Model:   cfg.APIs.LLM.Model,
+		Timeout: config.GetDuration(cfg.APIs.LLM.Timeout),
+	}
+	name := cfg.APIs.LLM.Provider
+	if name == "" || name == llm.ProviderGenAI {
+		if opts.BaseURL == "" {
+			opts.BaseURL = cfg.APIs.GenAI.BaseURL
+		}
+		if opts.APIKey == "" {
+			opts.APIKey = cfg.APIs.GenAI.APIKey
+		}
+	}
+	return llm.New(name, opts)
+}
+
+// loggerAdapter lets the shared logger.Logger satisfy this package's Logger interface.
+type loggerAdapter struct {
+	logger.Logger
+}
+
+func (a *loggerAdapter) With(fields map[string]interface{}) Logger {
+	return &loggerAdapter{a.Logger.With(fields)}
+}
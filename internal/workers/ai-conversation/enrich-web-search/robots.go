@@ -0,0 +1,213 @@
+// internal/workers/ai-conversation/enrich-web-search/robots.go
+package enrichwebsearch
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsTTL is how long a host's robots.txt is kept, the longest RFC 9309
+// allows.
+const robotsTTL = 24 * time.Hour
+
+// robotsRule is an Allow or Disallow line of robots.txt.
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+type robotsEntry struct {
+	ready   chan struct{} // closed once rules and err are set
+	rules   []robotsRule
+	err     error
+	fetched time.Time
+}
+
+// robotsCache answers whether a URL may be fetched, reading each host's
+// robots.txt once per robotsTTL. Concurrent fetches of one host wait for a
+// single read.
+type robotsCache struct {
+	client    *http.Client
+	userAgent string
+
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+func newRobotsCache(client *http.Client, userAgent string) *robotsCache {
+	return &robotsCache{client: client, userAgent: userAgent, entries: make(map[string]*robotsEntry)}
+}
+
+// allowed reports whether the user agent may fetch u. A missing robots.txt
+// allows everything; an unreachable one allows nothing.
+func (c *robotsCache) allowed(ctx context.Context, u *url.URL) bool {
+	entry := c.entry(ctx, u.Scheme+"://"+u.Host)
+	select {
+	case <-entry.ready:
+	case <-ctx.Done():
+		return false
+	}
+	if entry.err != nil {
+		return false
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return allowedBy(entry.rules, path)
+}
+
+// entry returns the robots.txt entry of origin, starting its read when it
+// is missing or expired. A failed read is forgotten so the next job retries.
+func (c *robotsCache) entry(ctx context.Context, origin string) *robotsEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[origin]; ok {
+		select {
+		case <-entry.ready:
+			if time.Since(entry.fetched) <= robotsTTL {
+				return entry
+			}
+		default:
+			return entry
+		}
+	}
+
+	entry := &robotsEntry{ready: make(chan struct{})}
+	c.entries[origin] = entry
+	go func() {
+		entry.rules, entry.err = c.fetch(ctx, origin)
+		entry.fetched = time.Now()
+		close(entry.ready)
+		if entry.err != nil {
+			c.mu.Lock()
+			if c.entries[origin] == entry {
+				delete(c.entries, origin)
+			}
+			c.mu.Unlock()
+		}
+	}()
+	return entry
+}
+
+func (c *robotsCache) fetch(ctx context.Context, origin string) ([]robotsRule, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, &fetchError{status: resp.StatusCode}
+	case resp.StatusCode >= 400:
+		return nil, nil
+	}
+	return parseRobots(io.LimitReader(resp.Body, 512<<10), c.userAgent), nil
+}
+
+// parseRobots returns the rules of the group for the product token of
+// userAgent, or of the * group when no group names it.
+func parseRobots(r io.Reader, userAgent string) []robotsRule {
+	token := strings.ToLower(strings.SplitN(userAgent, "/", 2)[0])
+
+	var own, wildcard []robotsRule
+	var agents []string
+	inRules := false
+	ownFound := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+			ownFound = ownFound || strings.ToLower(value) == token
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // an empty Disallow allows everything
+			}
+			rule := robotsRule{allow: key == "allow", path: value}
+			for _, agent := range agents {
+				switch {
+				case agent == token:
+					own = append(own, rule)
+				case agent == "*":
+					wildcard = append(wildcard, rule)
+				}
+			}
+		}
+	}
+	if ownFound {
+		return own
+	}
+	return wildcard
+}
+
+// allowedBy applies the most specific matching rule; Allow wins a tie.
+func allowedBy(rules []robotsRule, path string) bool {
+	allowed, longest := true, -1
+	for _, r := range rules {
+		if !robotsMatch(r.path, path) {
+			continue
+		}
+		if n := len(r.path); n > longest || (n == longest && r.allow) {
+			allowed, longest = r.allow, n
+		}
+	}
+	return allowed
+}
+
+// robotsMatch matches a rule path with * wildcards and a $ end anchor.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for _, part := range parts[1:] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	if !anchored {
+		return true
+	}
+	if len(parts) > 1 {
+		// The last part may match further along than its first occurrence.
+		return strings.HasSuffix(path, parts[len(parts)-1])
+	}
+	return rest == ""
+}
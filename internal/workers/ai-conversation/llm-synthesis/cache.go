@@ -0,0 +1,353 @@
+// internal/workers/ai-conversation/llm-synthesis/cache.go
+package llmsynthesis
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/guardrails"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Matches of a cached answer.
+const (
+	MatchExact   = "exact"
+	MatchSimilar = "similar"
+)
+
+// FranchiseVersions reports when franchises were last updated, so that
+// answers citing them can be invalidated.
+type FranchiseVersions interface {
+	// Versions returns the updated_at of each franchise found; missing ones
+	// are left out.
+	Versions(ctx context.Context, ids []string) (map[string]time.Time, error)
+}
+
+// PostgresFranchiseVersions reads updated_at from the franchises table.
+type PostgresFranchiseVersions struct {
+	DB *sql.DB
+}
+
+func (v PostgresFranchiseVersions) Versions(ctx context.Context, ids []string) (map[string]time.Time, error) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+	rows, err := v.DB.QueryContext(ctx,
+		`SELECT id, updated_at FROM franchises WHERE id IN (`+strings.Join(placeholders, ",")+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read franchise versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]time.Time, len(ids))
+	for rows.Next() {
+		var id string
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return nil, fmt.Errorf("read franchise versions: %w", err)
+		}
+		versions[id] = updatedAt
+	}
+	return versions, rows.Err()
+}
+
+// cachedAnswer is an answer in the cache with the updated_at of the
+// franchises it cites when it was generated.
+type cachedAnswer struct {
+	Output     *Output              `json:"output"`
+	Franchises map[string]time.Time `json:"franchises,omitempty"`
+}
+
+// cachedQuestion is a cached question compared by similarity. raw is the
+// list element it was read from, and similarity that to the looked up
+// question.
+type cachedQuestion struct {
+	Key       string    `json:"key"`
+	Embedding []float32 `json:"embedding"`
+
+	raw        string
+	similarity float64
+}
+
+// cacheLookup is the outcome of looking an answer up, kept to cache the
+// answer generated on a miss.
+type cacheLookup struct {
+	status    *CacheStatus
+	key       string
+	questions string
+	embedding []float32
+}
+
+// entityFingerprint identifies the entities of the question independently
+// of their order and case.
+func entityFingerprint(entities []Entity) string {
+	keys := make([]string, 0, len(entities))
+	for _, e := range entities {
+		keys = append(keys, e.Type+"="+strings.ToLower(strings.TrimSpace(e.Value)))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "|")
+}
+
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cacheKeys returns the key of the answer to input and of the list of
+// questions about the same intent, tier and entities.
+func cacheKeys(input *Input) (key, questions string) {
+	scope := "ai:answer:" + digest(input.Intent.PrimaryIntent+"\x00"+input.TierLevel+"\x00"+entityFingerprint(input.Entities))
+	return scope + ":" + digest(llm.NormalizeQuery(input.Question)), scope + ":questions"
+}
+
+// cachedAnswer looks the answer to input up: by exact question first, then
+// by similarity among the questions about the same entities. A cache or
+// embedding failure is a miss. Without a cache both results are nil, as
+// they are for a question following earlier turns of conv: its answer
+// depends on them, so it is neither served from nor kept in the cache.
+func (h *Handler) cachedAnswer(ctx context.Context, input *Input, conv *conversation.Conversation) (*Output, *cacheLookup) {
+	if h.config.AnswerCache == nil || hasHistory(conv) {
+		return nil, nil
+	}
+	lookup := &cacheLookup{status: &CacheStatus{}}
+	lookup.key, lookup.questions = cacheKeys(input)
+
+	if output, _ := h.freshAnswer(ctx, lookup.key, lookup.status); output != nil {
+		return h.hit(output, lookup, MatchExact, 0)
+	}
+	if h.config.AnswerEmbedder == nil || h.config.AnswerSimilarity <= 0 {
+		return h.miss(lookup)
+	}
+
+	vectors, err := h.config.AnswerEmbedder.Embed(ctx, []string{llm.NormalizeQuery(input.Question)})
+	if err != nil {
+		h.logger.Warn("failed to embed question, answer cache matches exact questions only", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return h.miss(lookup)
+	}
+	lookup.embedding = vectors[0]
+
+	// Answers of similar questions may have expired or been invalidated
+	// since; their questions are dropped and the next most similar tried.
+	tried := map[string]bool{lookup.key: true}
+	for _, q := range h.similarQuestions(ctx, lookup) {
+		if tried[q.Key] {
+			continue
+		}
+		tried[q.Key] = true
+		output, gone := h.freshAnswer(ctx, q.Key, lookup.status)
+		if output != nil {
+			return h.hit(output, lookup, MatchSimilar, q.similarity)
+		}
+		if gone {
+			h.config.AnswerCache.LRem(ctx, lookup.questions, 0, q.raw)
+		}
+	}
+	return h.miss(lookup)
+}
+
+// hasHistory reports whether conv has turns or a summary the answer would
+// be generated with.
+func hasHistory(conv *conversation.Conversation) bool {
+	return conv != nil && (len(conv.Turns) > 0 || conv.Summary != "")
+}
+
+func (h *Handler) hit(output *Output, lookup *cacheLookup, match string, similarity float64) (*Output, *cacheLookup) {
+	lookup.status.Hit = true
+	lookup.status.Match = match
+	lookup.status.Similarity = similarity
+	output.Cache = lookup.status
+	metrics.AnswerCacheRequests.WithLabelValues("hit", match).Inc()
+	return output, lookup
+}
+
+func (h *Handler) miss(lookup *cacheLookup) (*Output, *cacheLookup) {
+	metrics.AnswerCacheRequests.WithLabelValues("miss", "none").Inc()
+	return nil, lookup
+}
+
+// similarQuestions returns the cached questions at least AnswerSimilarity
+// similar to the looked up one, most similar first.
+func (h *Handler) similarQuestions(ctx context.Context, lookup *cacheLookup) []cachedQuestion {
+	candidates := h.config.AnswerCandidates
+	if candidates <= 0 {
+		candidates = 50
+	}
+	vals, err := h.config.AnswerCache.LRange(ctx, lookup.questions, 0, int64(candidates-1)).Result()
+	if err != nil {
+		return nil
+	}
+	var similar []cachedQuestion
+	for _, val := range vals {
+		var q cachedQuestion
+		if err := json.Unmarshal([]byte(val), &q); err != nil {
+			continue
+		}
+		if q.similarity = cosine(lookup.embedding, q.Embedding); q.similarity >= h.config.AnswerSimilarity {
+			q.raw = val
+			similar = append(similar, q)
+		}
+	}
+	sort.SliceStable(similar, func(i, j int) bool { return similar[i].similarity > similar[j].similarity })
+	return similar
+}
+
+// freshAnswer returns the answer cached under key unless a franchise it
+// cites was updated since, in which case the answer is dropped. gone
+// reports an answer that expired, was dropped or cannot be read, as opposed
+// to one that could not be checked.
+func (h *Handler) freshAnswer(ctx context.Context, key string, status *CacheStatus) (output *Output, gone bool) {
+	val, err := h.config.AnswerCache.Get(ctx, key).Result()
+	if err != nil {
+		return nil, errors.Is(err, redis.Nil)
+	}
+	var entry cachedAnswer
+	if err := json.Unmarshal([]byte(val), &entry); err != nil || entry.Output == nil {
+		return nil, true
+	}
+	if len(entry.Franchises) == 0 || h.config.FranchiseVersions == nil {
+		return entry.Output, false
+	}
+
+	ids := make([]string, 0, len(entry.Franchises))
+	for id := range entry.Franchises {
+		ids = append(ids, id)
+	}
+	current, err := h.config.FranchiseVersions.Versions(ctx, ids)
+	if err != nil {
+		h.logger.Warn("failed to check cited franchises, answer generated again", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, false
+	}
+	for id, updatedAt := range entry.Franchises {
+		if v, ok := current[id]; !ok || !v.Equal(updatedAt) {
+			h.config.AnswerCache.Del(ctx, key)
+			status.Invalidated = true
+			metrics.AnswerCacheInvalidations.Inc()
+			h.logger.Info("cached answer invalidated", map[string]interface{}{
+				"franchiseId": id,
+			})
+			return nil, true
+		}
+	}
+	return entry.Output, false
+}
+
+// cacheAnswer caches output for the looked up question, with the
+// updated_at of the franchises it cites. Only the guardrail decisions about
+// the answer are kept; those about the input belong to each question. An
+// answer whose franchises cannot be read is not cached, as it could not be
+// invalidated.
+func (h *Handler) cacheAnswer(ctx context.Context, lookup *cacheLookup, input *Input, output *Output, answerDecisions []guardrails.Decision) {
+	if lookup == nil {
+		return
+	}
+	entry := cachedAnswer{Output: &Output{}}
+	*entry.Output = *output
+	entry.Output.Usage = nil
+	entry.Output.Cache = nil
+	entry.Output.Guardrails = answerDecisions
+
+	if ids := answerFranchiseIDs(input); len(ids) > 0 && h.config.FranchiseVersions != nil {
+		versions, err := h.config.FranchiseVersions.Versions(ctx, ids)
+		if err != nil {
+			h.logger.Warn("failed to read cited franchises, answer not cached", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+		entry.Franchises = versions
+	}
+
+	data, _ := json.Marshal(entry)
+	pipe := h.config.AnswerCache.TxPipeline()
+	pipe.Set(ctx, lookup.key, data, h.config.AnswerCacheTTL)
+	if lookup.embedding != nil {
+		q, _ := json.Marshal(cachedQuestion{Key: lookup.key, Embedding: lookup.embedding})
+		candidates := h.config.AnswerCandidates
+		if candidates <= 0 {
+			candidates = 50
+		}
+		pipe.LPush(ctx, lookup.questions, q)
+		pipe.LTrim(ctx, lookup.questions, 0, int64(candidates-1))
+		pipe.Expire(ctx, lookup.questions, h.config.AnswerCacheTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		h.logger.Warn("failed to cache answer", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// publishCached publishes a cached answer as a single progress event when
+// answers stream, so that the frontend shows it as it would a generated one.
+func (h *Handler) publishCached(ctx context.Context, output *Output, requestID string) {
+	if _, ok := h.provider.(llm.StreamingProvider); !h.config.Stream || !ok || h.config.Progress == nil || requestID == "" {
+		return
+	}
+	progress := &progressWriter{
+		publisher: h.config.Progress,
+		requestID: requestID,
+		logger:    h.logger,
+	}
+	progress.pending.WriteString(output.LLMResponse)
+	progress.finish(ctx, output.FinishReason)
+}
+
+// answerFranchiseIDs are the franchises of the internal data and passages
+// the answer was given, once each.
+func answerFranchiseIDs(input *Input) []string {
+	ids := citedFranchiseIDs(input.InternalData)
+	for _, p := range input.Passages {
+		if p.FranchiseID != "" {
+			ids = append(ids, p.FranchiseID)
+		}
+	}
+	seen := make(map[string]bool, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// cosine is the cosine similarity of two vectors, 0 when their sizes
+// differ or either is zero.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -1,14 +1,67 @@
 // internal/workers/ai-conversation/llm-synthesis/config.go
 package llmsynthesis
 
-import "time"
+import (
+	"time"
+
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/prompts"
+	"camunda-workers/internal/common/usage"
+
+	"github.com/redis/go-redis/v9"
+)
 
 type Config struct {
-	GenAIBaseURL string
-	Timeout      time.Duration
-	MaxRetries   int
-	MaxTokens    int
-	Temperature  float64
+	GenAIBaseURL  string
+	Timeout       time.Duration
+	MaxRetries    int
+	MaxTokens     int
+	Temperature   float64
+	StopSequences []string
+	// Provider generates the answers. Nil uses the GenAI service at
+	// GenAIBaseURL.
+	Provider llm.Provider
+	// Stream consumes the answer as it is generated when the provider
+	// supports it, publishing the tokens to Progress every ProgressInterval.
+	Stream           bool
+	Progress         ProgressPublisher
+	ProgressInterval time.Duration
+	// Memory, when set, keeps the turns of a conversation. The last
+	// HistoryTurns turns are sent with each question; older ones are
+	// summarized once the conversation exceeds TokenBudget tokens.
+	Memory       conversation.Store
+	HistoryTurns int
+	TokenBudget  int
+	// Prompts, when set, supplies the prompt by intent and tier in place
+	// of the built-in one.
+	Prompts *prompts.Registry
+	// Grounding checks the claims of each answer against its input data,
+	// cites its sentences and caps the confidence of answers with
+	// unsupported claims at UngroundedConfidence.
+	Grounding            bool
+	UngroundedConfidence float64
+	// Guardrails redacts PII and drops injected web sources before the
+	// prompt is built, and removes answer sentences in BlockedCategories.
+	// A streamed answer is checked once complete, after its tokens were
+	// published.
+	Guardrails        bool
+	BlockedCategories []string
+	// Usage records the tokens and cost of each answer and conversation
+	// summary; nil records nothing.
+	Usage *usage.Meter
+	// AnswerCache keeps answers for AnswerCacheTTL by normalized question,
+	// intent, tier and entities; nil generates every answer. With an
+	// AnswerEmbedder, a reworded question about the same entities matches
+	// one of the last AnswerCandidates cached questions whose embedding is
+	// at least AnswerSimilarity similar. Cached answers citing a franchise
+	// FranchiseVersions reports updated since are generated again.
+	AnswerCache       redis.UniversalClient
+	AnswerCacheTTL    time.Duration
+	AnswerEmbedder    llm.Embedder
+	AnswerSimilarity  float64
+	AnswerCandidates  int
+	FranchiseVersions FranchiseVersions
 }
 
 func LoadConfig() *Config {
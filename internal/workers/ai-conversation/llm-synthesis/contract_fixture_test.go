@@ -0,0 +1,67 @@
+// internal/workers/ai-conversation/llm-synthesis/contract_fixture_test.go
+package llmsynthesis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return contractHandler(t, http.StatusOK).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"LLM_TIMEOUT": func(t *testing.T, ctx context.Context, input *Input) error {
+				release := make(chan struct{})
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-r.Context().Done():
+					case <-release:
+					}
+				}))
+				t.Cleanup(server.Close)
+				t.Cleanup(func() { close(release) })
+
+				// The handler is bounded by the job deadline only.
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				defer cancel()
+
+				config := createTestConfig()
+				config.GenAIBaseURL = server.URL
+				_, err := NewHandler(config, NewTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+			"LLM_SYNTHESIS_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := contractHandler(t, http.StatusInternalServerError).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "LLM_SYNTHESIS_FAILED",
+		Mappings: errorMappings,
+	}
+}
+
+// contractHandler returns a handler whose GenAI endpoint answers with status.
+func contractHandler(t *testing.T, status int) *Handler {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createLLMAPIResponse("Franchise fees start at 45,000 EUR.", 0.9, []string{"internal"})))
+	}))
+	t.Cleanup(server.Close)
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	return NewHandler(config, NewTestLogger(t))
+}
@@ -0,0 +1,372 @@
+// internal/workers/ai-conversation/llm-synthesis/grounding.go
+package llmsynthesis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The claims grounding checks: dollar amounts and ranges, percentages, and
+// franchise names, i.e. a capitalized name followed by franchise, brand,
+// outlet or location, or a quoted name.
+var (
+	amountPattern  = regexp.MustCompile(`(?i)\$\s?(\d[\d,]*(?:\.\d+)?)\s*(k|m|million|thousand|billion|bn)?\b(?:\s*(?:-|–|to)\s*(\$)?\s?(\d[\d,]*(?:\.\d+)?)\s*(k|m|million|thousand|billion|bn)?\b)?`)
+	percentPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s?%`)
+	namePattern    = regexp.MustCompile(`((?:[A-Z][\w'’&.-]*)(?:\s+[A-Z][\w'’&.-]*)*)\s+(?:franchises?|brand|outlets?|locations?)\b`)
+	quotedPattern  = regexp.MustCompile(`"([^"]{2,60})"`)
+	numberPattern  = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(k|m|million|thousand|billion|bn)?\b`)
+	wordPattern    = regexp.MustCompile(`[a-z][a-z'’-]{3,}`)
+)
+
+// determiners are dropped from the front of a matched name; a name that is
+// only a determiner is no name.
+var determiners = []string{
+	"The", "A", "An", "This", "That", "These", "Those", "Each", "Every", "Any", "All",
+	"Many", "Most", "Some", "Several", "Other", "Our", "Your", "Its", "Their",
+}
+
+// stopWords carry no evidence when matching uncited sentences to sources.
+var stopWords = map[string]bool{
+	"franchise": true, "franchises": true, "about": true, "also": true, "from": true,
+	"have": true, "that": true, "their": true, "there": true, "these": true, "this": true,
+	"with": true, "which": true, "will": true, "would": true, "could": true, "should": true,
+	"your": true, "what": true, "when": true, "where": true, "been": true, "more": true,
+	"than": true, "into": true, "they": true, "data": true, "information": true,
+}
+
+// claim is a checkable statement in an answer.
+type claim struct {
+	text   string    // as written in the answer
+	name   string    // lowercased franchise name, for name claims
+	values []float64 // amounts or percentages, for numeric claims
+}
+
+// evidence is one citable piece of the data the answer was given.
+type evidence struct {
+	ref     string
+	text    string // lowercased
+	numbers []float64
+}
+
+// ground checks the answer against the data it was given. Every claim must
+// be found in the internal data, passages or web sources, or in the
+// question; each sentence is cited to the evidence it draws on.
+func ground(input *Input, answer string) ([]Citation, *Grounding) {
+	evidences := collectEvidence(input)
+	question := evidenceOf("question", input.Question)
+
+	report := &Grounding{}
+	var citations []Citation
+	for _, sentence := range splitSentences(answer) {
+		citation := Citation{Sentence: sentence, Sources: []string{}}
+		claims := extractClaims(sentence)
+		for _, c := range claims {
+			report.Claims++
+			refs, ok := support(c, evidences, question)
+			if !ok {
+				citation.Unsupported = append(citation.Unsupported, c.text)
+				report.Unsupported = append(report.Unsupported, c.text)
+				continue
+			}
+			report.Supported++
+			citation.Sources = appendUnique(citation.Sources, refs...)
+		}
+		if len(claims) == 0 {
+			if ref := bestOverlap(sentence, evidences); ref != "" {
+				citation.Sources = append(citation.Sources, ref)
+			}
+		}
+		citations = append(citations, citation)
+	}
+
+	report.Score = 1
+	if report.Claims > 0 {
+		report.Score = float64(report.Supported) / float64(report.Claims)
+	}
+	return citations, report
+}
+
+// groundedConfidence scales confidence by the share of supported claims and
+// caps it at ceiling when any claim is unsupported, so that the answer is
+// presented as tentative.
+func groundedConfidence(confidence float64, report *Grounding, ceiling float64) float64 {
+	if len(report.Unsupported) == 0 {
+		return confidence
+	}
+	return math.Min(confidence*report.Score, ceiling)
+}
+
+// citedSources are the sources the citations refer to, in order of first
+// citation.
+func citedSources(citations []Citation) []string {
+	sources := []string{}
+	for _, c := range citations {
+		sources = appendUnique(sources, c.Sources...)
+	}
+	return sources
+}
+
+// support returns the evidence backing a claim. A claim taken from the
+// question is supported without a citation.
+func support(c claim, evidences []evidence, question evidence) ([]string, bool) {
+	var refs []string
+	for _, e := range evidences {
+		if e.backs(c) {
+			refs = append(refs, e.ref)
+		}
+	}
+	return refs, len(refs) > 0 || question.backs(c)
+}
+
+func (e evidence) backs(c claim) bool {
+	if c.name != "" {
+		return strings.Contains(e.text, c.name)
+	}
+	for _, v := range c.values {
+		found := false
+		for _, n := range e.numbers {
+			if math.Abs(n-v) <= 0.005*math.Max(math.Abs(v), 1) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(c.values) > 0
+}
+
+// bestOverlap returns the evidence sharing the most content words with the
+// sentence, at least two, for sentences without checkable claims.
+func bestOverlap(sentence string, evidences []evidence) string {
+	words := contentWords(sentence)
+	best, ref := 1, ""
+	for _, e := range evidences {
+		n := 0
+		for w := range words {
+			if strings.Contains(e.text, w) {
+				n++
+			}
+		}
+		if n > best {
+			best, ref = n, e.ref
+		}
+	}
+	return ref
+}
+
+func contentWords(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(strings.ToLower(s), -1) {
+		if !stopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+func extractClaims(sentence string) []claim {
+	var claims []claim
+	for _, m := range amountPattern.FindAllStringSubmatch(sentence, -1) {
+		unit := m[2]
+		if unit == "" && m[3] == "" {
+			unit = m[5] // "$1-2 million"
+		}
+		values := []float64{parseAmount(m[1], unit)}
+		if m[4] != "" {
+			values = append(values, parseAmount(m[4], m[5]))
+		}
+		claims = append(claims, claim{text: strings.TrimSpace(m[0]), values: values})
+	}
+	for _, m := range percentPattern.FindAllStringSubmatch(sentence, -1) {
+		claims = append(claims, claim{text: m[0], values: []float64{parseAmount(m[1], "")}})
+	}
+	names := namePattern.FindAllStringSubmatch(sentence, -1)
+	names = append(names, quotedPattern.FindAllStringSubmatch(sentence, -1)...)
+	for _, m := range names {
+		name := m[1]
+		for _, d := range determiners {
+			if name == d {
+				name = ""
+			}
+			name = strings.TrimPrefix(name, d+" ")
+		}
+		if name == "" || !strings.ContainsAny(name[:1], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+			continue
+		}
+		claims = append(claims, claim{text: name, name: strings.ToLower(name)})
+	}
+	return claims
+}
+
+func parseAmount(number, unit string) float64 {
+	v, _ := strconv.ParseFloat(strings.ReplaceAll(number, ",", ""), 64)
+	switch strings.ToLower(unit) {
+	case "k", "thousand":
+		v *= 1e3
+	case "m", "million":
+		v *= 1e6
+	case "bn", "billion":
+		v *= 1e9
+	}
+	return v
+}
+
+// collectEvidence turns the data given to the provider into citable
+// evidence: each internal data row, cited as internal:<key>/<id>, each
+// passage by its ID and each web source, with its excerpt, by its URL.
+func collectEvidence(input *Input) []evidence {
+	var evidences []evidence
+
+	keys := make([]string, 0, len(input.InternalData))
+	for k := range input.InternalData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rows := asRows(input.InternalData[key])
+		if rows == nil {
+			evidences = append(evidences, evidenceOf("internal:"+key, input.InternalData[key]))
+			continue
+		}
+		for i, row := range rows {
+			ref := fmt.Sprintf("internal:%s/%d", key, i)
+			if id := rowID(row); id != "" {
+				ref = "internal:" + key + "/" + id
+			}
+			evidences = append(evidences, evidenceOf(ref, row))
+		}
+	}
+
+	for _, p := range input.Passages {
+		evidences = append(evidences, evidenceOf(p.ID, p.Title+"\n"+p.Text))
+	}
+	for _, src := range input.WebData.Sources {
+		evidences = append(evidences, evidenceOf(src.URL, src.Title+"\n"+src.Excerpt))
+	}
+	if input.WebData.Summary != "" {
+		evidences = append(evidences, evidenceOf("web:summary", input.WebData.Summary))
+	}
+	return evidences
+}
+
+func asRows(v interface{}) []map[string]interface{} {
+	switch rows := v.(type) {
+	case []map[string]interface{}:
+		return rows
+	case []interface{}:
+		out := make([]map[string]interface{}, 0, len(rows))
+		for _, row := range rows {
+			m, ok := row.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			out = append(out, m)
+		}
+		return out
+	}
+	return nil
+}
+
+func rowID(row map[string]interface{}) string {
+	for _, field := range []string{"id", "franchiseId"} {
+		if id, ok := row[field]; ok && id != nil {
+			return fmt.Sprint(id)
+		}
+	}
+	return ""
+}
+
+// evidenceOf flattens a value into searchable text and the numbers in it.
+func evidenceOf(ref string, v interface{}) evidence {
+	e := evidence{ref: ref}
+	var b strings.Builder
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch x := v.(type) {
+		case string:
+			b.WriteString(x + "\n")
+			for _, m := range numberPattern.FindAllStringSubmatch(x, -1) {
+				e.numbers = append(e.numbers, parseAmount(m[1], m[2]))
+			}
+		case float64:
+			e.numbers = append(e.numbers, x)
+		case float32:
+			e.numbers = append(e.numbers, float64(x))
+		case int:
+			e.numbers = append(e.numbers, float64(x))
+		case int64:
+			e.numbers = append(e.numbers, float64(x))
+		case json.Number:
+			if f, err := x.Float64(); err == nil {
+				e.numbers = append(e.numbers, f)
+			}
+		case map[string]interface{}:
+			for _, item := range x {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range x {
+				walk(item)
+			}
+		case []map[string]interface{}:
+			for _, item := range x {
+				walk(item)
+			}
+		case nil:
+		default:
+			walk(fmt.Sprint(x))
+		}
+	}
+	walk(v)
+	e.text = strings.ToLower(b.String())
+	return e
+}
+
+// splitSentences splits text after ., ! or ? followed by whitespace, and at
+// line breaks.
+func splitSentences(text string) []string {
+	var sentences []string
+	var b strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			sentences = append(sentences, s)
+		}
+		b.Reset()
+	}
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == '\n' {
+			flush()
+			continue
+		}
+		b.WriteRune(r)
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || runes[i+1] == ' ' || runes[i+1] == '\n') {
+			flush()
+		}
+	}
+	flush()
+	return sentences
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, s := range list {
+			if s == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,78 @@
+// internal/workers/ai-conversation/llm-synthesis/guard.go
+package llmsynthesis
+
+import (
+	"encoding/json"
+
+	"camunda-workers/internal/common/guardrails"
+)
+
+// guardInput returns a copy of the input safe to prompt with: PII is
+// redacted from the question and the web data, and web sources carrying
+// prompt-injection phrases are dropped. Internal data and passages come
+// from our own stores and pass unchanged.
+func (h *Handler) guardInput(input *Input) (*Input, []guardrails.Decision) {
+	guarded := *input
+	var decisions []guardrails.Decision
+	redact := func(text, target string) string {
+		text, d := guardrails.Redact(text, target)
+		decisions = append(decisions, d...)
+		return text
+	}
+
+	guarded.Question = redact(input.Question, "question")
+
+	guarded.WebData.Sources = make([]Source, 0, len(input.WebData.Sources))
+	for _, src := range input.WebData.Sources {
+		if phrase, found := guardrails.DetectInjection(src.Title + "\n" + src.Snippet + "\n" + src.Excerpt); found {
+			decisions = append(decisions, guardrails.Decision{
+				Stage:  guardrails.StageInput,
+				Rule:   guardrails.RulePromptInjection,
+				Action: guardrails.ActionDropped,
+				Target: "web_source:" + src.URL,
+				Detail: phrase,
+			})
+			continue
+		}
+		src.Title = redact(src.Title, "web_source:"+src.URL)
+		src.Snippet = redact(src.Snippet, "web_source:"+src.URL)
+		src.Excerpt = redact(src.Excerpt, "web_source:"+src.URL)
+		guarded.WebData.Sources = append(guarded.WebData.Sources, src)
+	}
+
+	if phrase, found := guardrails.DetectInjection(input.WebData.Summary); found {
+		guarded.WebData.Summary = ""
+		decisions = append(decisions, guardrails.Decision{
+			Stage:  guardrails.StageInput,
+			Rule:   guardrails.RulePromptInjection,
+			Action: guardrails.ActionDropped,
+			Target: "web_summary",
+			Detail: phrase,
+		})
+	} else {
+		guarded.WebData.Summary = redact(input.WebData.Summary, "web_summary")
+	}
+
+	h.logDecisions(decisions)
+	return &guarded, decisions
+}
+
+// guardOutput removes the sentences of the answer in a blocked category.
+// Contact data is checked against everything the answer was generated from.
+func (h *Handler) guardOutput(answer string, input *Input) (string, []guardrails.Decision) {
+	known, _ := json.Marshal(input)
+	answer, decisions := guardrails.CheckAnswer(answer, string(known), h.config.BlockedCategories)
+	h.logDecisions(decisions)
+	return answer, decisions
+}
+
+func (h *Handler) logDecisions(decisions []guardrails.Decision) {
+	for _, d := range decisions {
+		h.logger.Warn("guardrail applied", map[string]interface{}{
+			"stage":  d.Stage,
+			"rule":   d.Rule,
+			"action": d.Action,
+			"target": d.Target,
+		})
+	}
+}
@@ -2,106 +2,94 @@
 package llmsynthesis
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/guardrails"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/prompts"
+	"camunda-workers/internal/common/usage"
 )
 
 const (
 	TaskType = "llm-synthesis"
 )
 
+// The provider errors carry the BPMN error codes this worker reports.
 var (
-	ErrLLMTimeout         = errors.New("LLM_TIMEOUT")
-	ErrLLMSynthesisFailed = errors.New("LLM_SYNTHESIS_FAILED")
+	ErrLLMTimeout         = llm.ErrTimeout
+	ErrLLMSynthesisFailed = llm.ErrFailed
 )
 
+// systemPrompt sets the role of the model for every provider.
+const systemPrompt = "You are a helpful franchise advisor. Answer the user's question based ONLY on the provided data."
+
 // Logger interface definition
 type Logger interface {
 	Info(msg string, fields map[string]interface{})
+	Warn(msg string, fields map[string]interface{})
 	Error(msg string, fields map[string]interface{})
 	With(fields map[string]interface{}) Logger
 }
 
 type Handler struct {
-	config *Config
-	client *http.Client
-	logger Logger
+	config   *Config
+	provider llm.Provider
+	logger   Logger
 }
 
-// Updated constructor with Logger interface
+// Updated constructor with Logger interface. Without a configured provider
+// the handler calls the internal GenAI service at config.GenAIBaseURL.
 func NewHandler(config *Config, log Logger) *Handler {
+	provider := config.Provider
+	if provider == nil {
+		// No HTTP client timeout - rely only on context
+		provider = llm.NewGenAI(config.GenAIBaseURL, "", &http.Client{})
+	}
+	provider = usage.Provider(provider, config.Usage, usage.OpSynthesis)
 	return &Handler{
-		config: config,
-		client: &http.Client{
-			// Remove HTTP client timeout completely - rely only on context
-		},
+		config:   config,
+		provider: provider,
 		logger: log.With(map[string]interface{}{
 			"taskType": TaskType,
+			"provider": provider.Name(),
 		}),
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, fmt.Errorf("parse input: %w", err), 0)
-		return
+func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
+	var decisions []guardrails.Decision
+	if h.config.Guardrails {
+		input, decisions = h.guardInput(input)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
+	conv := h.loadConversation(ctx, input.ConversationID)
 
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		retries := int32(0)
-		if errors.Is(err, ErrLLMTimeout) {
-			retries = 1
-		} else if errors.Is(err, ErrLLMSynthesisFailed) {
-			retries = 1
+	cached, lookup := h.cachedAnswer(ctx, input, conv)
+	if cached != nil {
+		cached.Guardrails = append(decisions, cached.Guardrails...)
+		h.publishCached(ctx, cached, input.RequestID)
+		h.logger.Info("LLM synthesis served from cache", map[string]interface{}{
+			"match":      cached.Cache.Match,
+			"similarity": cached.Cache.Similarity,
+		})
+		if conv != nil {
+			h.remember(ctx, conv, input, cached.LLMResponse)
 		}
-		h.failJob(client, job, err, retries)
-		return
+		return cached, nil
 	}
 
-	h.completeJob(client, job, output)
-}
+	req, prompt := h.buildRequest(ctx, input, conv)
 
-func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
-	prompt := h.buildPrompt(input)
-	requestBody := map[string]interface{}{
-		"prompt": prompt,
-		"context": map[string]interface{}{
-			"internal": input.InternalData,
-			"external": input.WebData,
-			"intent":   input.Intent,
-		},
-		"max_tokens":  h.config.MaxTokens,
-		"temperature": h.config.Temperature,
-	}
-
-	body, _ := json.Marshal(requestBody)
-	req, err := http.NewRequestWithContext(ctx, "POST", h.config.GenAIBaseURL+"/api/ai/generate", bytes.NewBuffer(body))
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrLLMSynthesisFailed, err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	var resp *http.Response
-	var lastErr error
+	var resp *llm.Response
+	var streamed bool
+	var blocked []guardrails.Decision
+	var err error
 
 	for attempt := 0; attempt <= h.config.MaxRetries; attempt++ {
 		if attempt > 0 {
@@ -115,16 +103,10 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 			}
 		}
 
-		resp, lastErr = h.client.Do(req)
-		if lastErr == nil {
-			// Check if response is successful
-			if resp.StatusCode == http.StatusOK {
-				break
-			}
-			// For non-OK status codes, treat as error and retry
-			resp.Body.Close()
-			lastErr = fmt.Errorf("status %d", resp.StatusCode)
-			resp = nil
+		resp, streamed, blocked, err = h.generate(ctx, req, input)
+		// Tokens already shown to the user cannot be taken back by a retry.
+		if err == nil || streamed || !llm.IsRetryable(err) {
+			break
 		}
 
 		// Check if error is due to context cancellation/timeout
@@ -133,54 +115,212 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 		}
 	}
 
-	if lastErr != nil {
+	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, ErrLLMTimeout
 		}
-		return nil, fmt.Errorf("%w: %v", ErrLLMSynthesisFailed, lastErr)
+		return nil, err
+	}
+
+	if h.config.Guardrails {
+		// A streamed answer was guarded sentence by sentence as it was published.
+		if !streamed {
+			resp.Text, blocked = h.guardOutput(resp.Text, input)
+		}
+		decisions = append(decisions, blocked...)
 	}
 
-	if resp == nil {
-		return nil, fmt.Errorf("%w: no successful response after retries", ErrLLMSynthesisFailed)
+	// Validate response
+	answered := strings.TrimSpace(resp.Text) != ""
+	if !answered {
+		resp.Text = "I don't have enough information to answer that question."
+		resp.Confidence = 0.1
 	}
-	defer resp.Body.Close()
 
-	var apiResponse struct {
-		Text       string   `json:"text"`
-		Confidence float64  `json:"confidence"`
-		Sources    []string `json:"sources"`
+	// Chat providers report no confidence (-1) and fall back to 0.5 here.
+	if resp.Confidence < 0.0 || resp.Confidence > 1.0 {
+		resp.Confidence = 0.5
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, fmt.Errorf("%w: decode error: %v", ErrLLMSynthesisFailed, err)
+
+	sources := resp.Sources
+	if sources == nil {
+		sources = []string{}
 	}
 
-	// Validate response
-	if strings.TrimSpace(apiResponse.Text) == "" {
-		apiResponse.Text = "I don't have enough information to answer that question."
-		apiResponse.Confidence = 0.1
+	var usage *TokenUsage
+	if resp.Usage != (llm.Usage{}) {
+		usage = &TokenUsage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			TotalTokens:      resp.Usage.PromptTokens + resp.Usage.CompletionTokens,
+		}
 	}
 
-	if apiResponse.Confidence < 0.0 || apiResponse.Confidence > 1.0 {
-		apiResponse.Confidence = 0.5
+	output := &Output{
+		LLMResponse:  resp.Text,
+		Confidence:   resp.Confidence,
+		Sources:      sources,
+		FinishReason: resp.FinishReason,
+		Usage:        usage,
+		Guardrails:   decisions,
+	}
+	if prompt != nil {
+		output.PromptID, output.PromptVersion = prompt.ID, prompt.Version
+	}
+	if h.config.Grounding {
+		h.applyGrounding(output, input)
+	}
+	if lookup != nil {
+		output.Cache = lookup.status
+		// The fallback answer stands for missing data, which may come later.
+		if answered {
+			h.cacheAnswer(ctx, lookup, input, output, blocked)
+		}
 	}
 
 	h.logger.Info("LLM synthesis completed", map[string]interface{}{
-		"confidence":  apiResponse.Confidence,
-		"sourceCount": len(apiResponse.Sources),
+		"confidence":    resp.Confidence,
+		"sourceCount":   len(sources),
+		"finishReason":  resp.FinishReason,
+		"promptId":      output.PromptID,
+		"promptVersion": output.PromptVersion,
 	})
 
-	return &Output{
-		LLMResponse: apiResponse.Text,
-		Confidence:  apiResponse.Confidence,
-		Sources:     apiResponse.Sources,
-	}, nil
+	if conv != nil {
+		h.remember(ctx, conv, input, resp.Text)
+	}
+
+	return output, nil
 }
 
+// applyGrounding cites the answer's sentences, replaces the provider's
+// sources with the cited ones and lowers the confidence of an answer making
+// claims its data does not support.
+func (h *Handler) applyGrounding(output *Output, input *Input) {
+	output.Citations, output.Grounding = ground(input, output.LLMResponse)
+	output.Sources = citedSources(output.Citations)
+	confidence := groundedConfidence(output.Confidence, output.Grounding, h.config.UngroundedConfidence)
+	if confidence < output.Confidence {
+		h.logger.Warn("answer makes unsupported claims", map[string]interface{}{
+			"unsupported":        output.Grounding.Unsupported,
+			"confidence":         output.Confidence,
+			"groundedConfidence": confidence,
+		})
+		output.Confidence = confidence
+	}
+}
+
+// generate asks the provider for the answer. In streaming mode, with a
+// provider that can stream and a request ID to publish under, progress is
+// published while the answer comes in; streamed reports whether any of it
+// was published. With guardrails on, a streamed answer is guarded before it
+// is published, and blocked lists the sentences removed from it.
+func (h *Handler) generate(ctx context.Context, req *llm.Request, input *Input) (resp *llm.Response, streamed bool, blocked []guardrails.Decision, err error) {
+	sp, ok := h.provider.(llm.StreamingProvider)
+	if !h.config.Stream || !ok || h.config.Progress == nil || input.RequestID == "" {
+		resp, err = h.provider.Generate(ctx, req)
+		return resp, false, nil, err
+	}
+
+	progress := &progressWriter{
+		publisher: h.config.Progress,
+		requestID: input.RequestID,
+		interval:  h.config.ProgressInterval,
+		logger:    h.logger,
+		last:      time.Now(),
+	}
+	if h.config.Guardrails {
+		progress.guard = func(text string) (string, []guardrails.Decision) {
+			return h.guardOutput(text, input)
+		}
+	}
+	resp, err = sp.Stream(ctx, req, func(chunk llm.Chunk) error {
+		progress.write(ctx, chunk.Delta)
+		return nil
+	})
+	streamed = progress.sequence > 0 || progress.pending.Len() > 0
+	if err != nil {
+		if streamed {
+			progress.finish(context.WithoutCancel(ctx), "error")
+		}
+		return nil, streamed, nil, err
+	}
+	progress.finish(ctx, resp.FinishReason)
+	if len(progress.blocked) > 0 {
+		resp.Text = strings.TrimSpace(progress.published.String())
+	}
+	return resp, true, progress.blocked, nil
+}
+
+// buildRequest turns the job input into a provider request: the system
+// prompt, the recent turns of a remembered conversation, the data-grounded
+// question and the GenAI context. The summary of older turns goes into the
+// system prompt. It also returns the prompt template used, nil for the
+// built-in prompt.
+func (h *Handler) buildRequest(ctx context.Context, input *Input, conv *conversation.Conversation) (*llm.Request, *prompts.Template) {
+	system, user, prompt := h.renderPrompt(ctx, input)
+	if conv != nil && conv.Summary != "" {
+		system += "\n\nConversation so far: " + conv.Summary
+	}
+	messages := append(h.historyMessages(conv), llm.Message{Role: llm.RoleUser, Content: user})
+	return &llm.Request{
+		SystemPrompt: system,
+		Messages:     messages,
+		MaxTokens:    h.config.MaxTokens,
+		Temperature:  h.config.Temperature,
+		Stop:         h.config.StopSequences,
+		Context: map[string]interface{}{
+			"internal": input.InternalData,
+			"passages": input.Passages,
+			"external": input.WebData,
+			"intent":   input.Intent,
+		},
+	}, prompt
+}
+
+// renderPrompt renders the registry's template for the intent and tier of
+// the input. Without a registry, or when no template can be selected or
+// rendered, it falls back to the built-in prompt.
+func (h *Handler) renderPrompt(ctx context.Context, input *Input) (system, user string, prompt *prompts.Template) {
+	if h.config.Prompts != nil {
+		t, err := h.config.Prompts.Select(ctx, input.Intent.PrimaryIntent, input.TierLevel, variantKey(input))
+		if err == nil {
+			system, user, err = t.Render(input)
+		}
+		if err == nil {
+			return system, user, t
+		}
+		h.logger.Warn("prompt template unavailable, using built-in prompt", map[string]interface{}{
+			"intent": input.Intent.PrimaryIntent,
+			"tier":   input.TierLevel,
+			"error":  err.Error(),
+		})
+	}
+	return systemPrompt, h.buildUserPrompt(input), nil
+}
+
+// variantKey buckets A/B prompt variants per conversation, falling back to
+// the request and then the question.
+func variantKey(input *Input) string {
+	switch {
+	case input.ConversationID != "":
+		return input.ConversationID
+	case input.RequestID != "":
+		return input.RequestID
+	default:
+		return input.Question
+	}
+}
+
+// buildPrompt is the complete prompt, as the GenAI service receives it.
 func (h *Handler) buildPrompt(input *Input) string {
+	return systemPrompt + "\n\n" + h.buildUserPrompt(input)
+}
+
+func (h *Handler) buildUserPrompt(input *Input) string {
 	var parts []string
 
-	parts = append(parts, "You are a helpful franchise advisor. Answer the user's question based ONLY on the provided data.")
-	parts = append(parts, fmt.Sprintf("\nUser Question: %s", input.Question))
+	parts = append(parts, fmt.Sprintf("User Question: %s", input.Question))
 
 	// Internal data
 	if len(input.InternalData) > 0 {
@@ -189,11 +329,22 @@ func (h *Handler) buildPrompt(input *Input) string {
 		parts = append(parts, string(internalJSON))
 	}
 
+	// Passages retrieved for the question, cited by ID
+	if len(input.Passages) > 0 {
+		parts = append(parts, "\nRetrieved Passages:")
+		for _, p := range input.Passages {
+			parts = append(parts, fmt.Sprintf("[%s] %s: %s", p.ID, p.Title, p.Text))
+		}
+	}
+
 	// Web data
 	if len(input.WebData.Sources) > 0 {
 		parts = append(parts, "\nExternal Web Sources:")
 		for _, src := range input.WebData.Sources {
 			parts = append(parts, fmt.Sprintf("- %s: %s", src.Title, src.URL))
+			if src.Excerpt != "" {
+				parts = append(parts, "  "+src.Excerpt)
+			}
 		}
 		if input.WebData.Summary != "" {
 			parts = append(parts, fmt.Sprintf("Summary: %s", input.WebData.Summary))
@@ -211,50 +362,6 @@ func (h *Handler) buildPrompt(input *Input) string {
 	return strings.Join(parts, "\n")
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-
-	if err != nil {
-		h.logger.Error("Failed to create complete job command", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  err.Error(),
-		})
-		return
-	}
-
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("Failed to send complete job command", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  err.Error(),
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, err error, retries int32) {
-	errorCode := "UNKNOWN_ERROR"
-	if errors.Is(err, ErrLLMTimeout) {
-		errorCode = "LLM_TIMEOUT"
-	} else if errors.Is(err, ErrLLMSynthesisFailed) {
-		errorCode = "LLM_SYNTHESIS_FAILED"
-	}
-
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":    job.Key,
-		"error":     err.Error(),
-		"errorCode": errorCode,
-		"retries":   retries,
-	})
-
-	_, _ = client.NewFailJobCommand().
-		JobKey(job.Key).
-		Retries(retries).
-		ErrorMessage(err.Error()).
-		Send(context.Background())
-}
-
 // Execute method for direct usage
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
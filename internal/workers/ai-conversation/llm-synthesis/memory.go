@@ -0,0 +1,126 @@
+// internal/workers/ai-conversation/llm-synthesis/memory.go
+package llmsynthesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/usage"
+)
+
+// summaryPrompt asks the provider to fold old turns into the running summary.
+const summaryPrompt = "Summarize this conversation between a user and a franchise advisor in a few sentences. Keep franchise names, locations, amounts and the user's goals."
+
+// loadConversation returns the remembered conversation, or nil without
+// memory or conversation ID. Memory is best effort: a failed load is logged
+// and the question answered on its own.
+func (h *Handler) loadConversation(ctx context.Context, id string) *conversation.Conversation {
+	if h.config.Memory == nil || id == "" {
+		return nil
+	}
+	conv, err := h.config.Memory.Load(ctx, id)
+	if err != nil {
+		h.logger.Warn("failed to load conversation", map[string]interface{}{
+			"conversationId": id,
+			"error":          err.Error(),
+		})
+		return nil
+	}
+	return conv
+}
+
+// remember records the question and answer with the franchises the answer
+// drew on, summarizing older turns once the conversation outgrows its token
+// budget.
+func (h *Handler) remember(ctx context.Context, conv *conversation.Conversation, input *Input, answer string) {
+	conv.AddTurn(conversation.RoleUser, input.Question)
+	conv.AddTurn(conversation.RoleAssistant, answer)
+	conv.AddFranchiseIDs(citedFranchiseIDs(input.InternalData)...)
+	for _, p := range input.Passages {
+		conv.AddFranchiseIDs(p.FranchiseID)
+	}
+
+	if conversation.Compact(ctx, conv, h.config.TokenBudget, h.config.HistoryTurns, h.summarize) {
+		h.logger.Info("conversation summarized", map[string]interface{}{
+			"conversationId": conv.ID,
+			"summaryTokens":  conversation.EstimateTokens(conv.Summary),
+		})
+	}
+
+	if err := h.config.Memory.Save(ctx, conv); err != nil {
+		h.logger.Warn("failed to save conversation", map[string]interface{}{
+			"conversationId": conv.ID,
+			"error":          err.Error(),
+		})
+	}
+}
+
+// summarize is the conversation.Summarizer backed by the answer provider.
+func (h *Handler) summarize(ctx context.Context, summary string, turns []conversation.Turn) (string, error) {
+	var b strings.Builder
+	if summary != "" {
+		fmt.Fprintf(&b, "Summary so far: %s\n\n", summary)
+	}
+	for _, t := range turns {
+		fmt.Fprintf(&b, "%s: %s\n", t.Role, t.Content)
+	}
+	ctx = usage.WithOperation(ctx, usage.OpConversationSummary)
+	resp, err := h.provider.Generate(ctx, &llm.Request{
+		SystemPrompt: summaryPrompt,
+		Messages:     []llm.Message{{Role: llm.RoleUser, Content: b.String()}},
+		MaxTokens:    h.config.TokenBudget / 4,
+		Temperature:  0,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Text, nil
+}
+
+// historyMessages is the window of recent turns sent before the question,
+// bounded by HistoryTurns and half the token budget.
+func (h *Handler) historyMessages(conv *conversation.Conversation) []llm.Message {
+	if conv == nil {
+		return nil
+	}
+	turns := conv.History(h.config.HistoryTurns, h.config.TokenBudget/2)
+	messages := make([]llm.Message, 0, len(turns))
+	for _, t := range turns {
+		role := llm.RoleUser
+		if t.Role == conversation.RoleAssistant {
+			role = llm.RoleAssistant
+		}
+		messages = append(messages, llm.Message{Role: role, Content: t.Content})
+	}
+	return messages
+}
+
+// citedFranchiseIDs collects the franchise IDs in query-internal-data's
+// franchises and outlets.
+func citedFranchiseIDs(internalData map[string]interface{}) []string {
+	var ids []string
+	collect := func(key, field string) {
+		switch rows := internalData[key].(type) {
+		case []interface{}:
+			for _, row := range rows {
+				if m, ok := row.(map[string]interface{}); ok {
+					if id, ok := m[field].(string); ok {
+						ids = append(ids, id)
+					}
+				}
+			}
+		case []map[string]interface{}:
+			for _, m := range rows {
+				if id, ok := m[field].(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	collect("franchises", "id")
+	collect("outlets", "franchiseId")
+	return ids
+}
@@ -1,17 +1,88 @@
 // internal/workers/ai-conversation/llm-synthesis/models.go
 package llmsynthesis
 
+import "camunda-workers/internal/common/guardrails"
+
 type Input struct {
-	Question     string                 `json:"question"`
-	InternalData map[string]interface{} `json:"internalData"`
-	WebData      WebData                `json:"webData"`
-	Intent       Intent                 `json:"intent"`
+	RequestID      string                 `json:"requestId"`
+	ConversationID string                 `json:"conversationId"`
+	Question       string                 `json:"question"`
+	InternalData   map[string]interface{} `json:"internalData"`
+	Passages       []Passage              `json:"passages"`
+	WebData        WebData                `json:"webData"`
+	Intent         Intent                 `json:"intent"`
+	TierLevel      string                 `json:"tierLevel"`
+	// Entities are what parse-user-intent found in the question; the
+	// answer cache tells questions about different entities apart by them.
+	Entities []Entity `json:"entities"`
 }
 
 type Output struct {
-	LLMResponse string   `json:"llmResponse"`
-	Confidence  float64  `json:"confidence"`
+	LLMResponse  string      `json:"llmResponse"`
+	Confidence   float64     `json:"confidence"`
+	Sources      []string    `json:"sources"`
+	FinishReason string      `json:"finishReason,omitempty"`
+	Usage        *TokenUsage `json:"usage,omitempty"`
+	// PromptID and PromptVersion identify the prompt template used; both
+	// are empty for the built-in prompt.
+	PromptID      string `json:"promptId,omitempty"`
+	PromptVersion string `json:"promptVersion,omitempty"`
+	// Citations and Grounding are set when answers are grounded; Sources
+	// then lists the cited sources in place of the provider's.
+	Citations []Citation `json:"citations,omitempty"`
+	Grounding *Grounding `json:"grounding,omitempty"`
+	// Guardrails records each redaction, dropped source and blocked
+	// sentence, for audit.
+	Guardrails []guardrails.Decision `json:"guardrails,omitempty"`
+	// Cache is set when the answer cache is on, except for questions that
+	// follow earlier turns of their conversation, which are not cached.
+	Cache *CacheStatus `json:"cache,omitempty"`
+}
+
+// CacheStatus tells whether the answer came from the answer cache. Match is
+// exact or similar on a hit; Similarity is that of the matched question.
+// Invalidated reports a cached answer dropped because a franchise it cites
+// was updated.
+type CacheStatus struct {
+	Hit         bool    `json:"hit"`
+	Match       string  `json:"match,omitempty"`
+	Similarity  float64 `json:"similarity,omitempty"`
+	Invalidated bool    `json:"invalidated,omitempty"`
+}
+
+// Citation ties a sentence of the answer to the internal data rows
+// (internal:<key>/<id>), passage IDs or web source URLs it draws on.
+// Unsupported lists its claims found in none of them.
+type Citation struct {
+	Sentence    string   `json:"sentence"`
 	Sources     []string `json:"sources"`
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// Grounding counts the amounts, percentages and franchise names in the
+// answer and how many of them the given data supports.
+type Grounding struct {
+	Claims      int      `json:"claims"`
+	Supported   int      `json:"supported"`
+	Unsupported []string `json:"unsupported,omitempty"`
+	Score       float64  `json:"score"` // Supported/Claims, 1 without claims
+}
+
+// TokenUsage is the token count reported by the provider.
+type TokenUsage struct {
+	PromptTokens     int `json:"promptTokens"`
+	CompletionTokens int `json:"completionTokens"`
+	TotalTokens      int `json:"totalTokens"`
+}
+
+// Passage is a franchise document passage query-internal-data retrieved
+// for the question.
+type Passage struct {
+	ID          string `json:"id"`
+	FranchiseID string `json:"franchiseId"`
+	Title       string `json:"title"`
+	Text        string `json:"text"`
+	URL         string `json:"url,omitempty"`
 }
 
 type WebData struct {
@@ -20,8 +91,17 @@ type WebData struct {
 }
 
 type Source struct {
-	URL   string `json:"url"`
-	Title string `json:"title"`
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	Snippet string `json:"snippet,omitempty"`
+	// Excerpt summarizes the page when enrich-web-search fetched it.
+	Excerpt string `json:"excerpt,omitempty"`
+}
+
+// Entity is an entity of the question, as parse-user-intent returns it.
+type Entity struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
 
 type Intent struct {
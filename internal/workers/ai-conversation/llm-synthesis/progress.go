@@ -0,0 +1,188 @@
+// internal/workers/ai-conversation/llm-synthesis/progress.go
+package llmsynthesis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode"
+
+	"camunda-workers/internal/common/guardrails"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/zbc"
+	"github.com/redis/go-redis/v9"
+)
+
+// ProgressMessage is the name of the Zeebe message ZeebeProgress publishes.
+const ProgressMessage = "llm-progress"
+
+// ProgressEvent is a batch of tokens streamed for a request. The frontend
+// appends the deltas in sequence order; the last event has Done set and
+// lists the sentences guardrails kept out of the deltas.
+type ProgressEvent struct {
+	RequestID    string                `json:"requestId"`
+	Sequence     int                   `json:"sequence"`
+	Delta        string                `json:"delta,omitempty"`
+	Done         bool                  `json:"done"`
+	FinishReason string                `json:"finishReason,omitempty"`
+	Guardrails   []guardrails.Decision `json:"guardrails,omitempty"`
+}
+
+// ProgressPublisher delivers progress events while an answer streams in.
+type ProgressPublisher interface {
+	Publish(ctx context.Context, event ProgressEvent) error
+}
+
+// ProgressChannel is the Redis pub/sub channel for a request's progress.
+func ProgressChannel(requestID string) string {
+	return "llm:progress:" + requestID
+}
+
+// RedisProgress publishes events as JSON on ProgressChannel(requestId).
+type RedisProgress struct {
+	client redis.UniversalClient
+}
+
+func NewRedisProgress(client redis.UniversalClient) *RedisProgress {
+	return &RedisProgress{client: client}
+}
+
+func (p *RedisProgress) Publish(ctx context.Context, event ProgressEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return p.client.Publish(ctx, ProgressChannel(event.RequestID), payload).Err()
+}
+
+// ZeebeProgress publishes events as ProgressMessage messages correlated by
+// requestId, with the event in the "progress" variable.
+type ZeebeProgress struct {
+	client zbc.Client
+	ttl    time.Duration
+}
+
+func NewZeebeProgress(client zbc.Client, ttl time.Duration) *ZeebeProgress {
+	return &ZeebeProgress{client: client, ttl: ttl}
+}
+
+func (p *ZeebeProgress) Publish(ctx context.Context, event ProgressEvent) error {
+	cmd, err := p.client.NewPublishMessageCommand().
+		MessageName(ProgressMessage).
+		CorrelationKey(event.RequestID).
+		TimeToLive(p.ttl).
+		VariablesFromMap(map[string]interface{}{"progress": event})
+	if err != nil {
+		return err
+	}
+	_, err = cmd.Send(ctx)
+	return err
+}
+
+// progressWriter batches streamed chunks into events, publishing at most one
+// event per interval. Publishing is best effort: failures are logged and
+// never fail the job.
+type progressWriter struct {
+	publisher ProgressPublisher
+	requestID string
+	interval  time.Duration
+	logger    Logger
+
+	// guard, when set, checks every finished sentence before it is
+	// published, so that blocked content never reaches the frontend. The
+	// unfinished sentence is held back until it ends or the stream does.
+	guard func(text string) (string, []guardrails.Decision)
+
+	held      strings.Builder
+	pending   strings.Builder
+	published strings.Builder
+	blocked   []guardrails.Decision
+	sequence  int
+	last      time.Time
+}
+
+func (w *progressWriter) write(ctx context.Context, delta string) {
+	if w.guard == nil {
+		w.pending.WriteString(delta)
+	} else {
+		w.held.WriteString(delta)
+		held := w.held.String()
+		if end := finishedSentences(held); end > 0 {
+			w.release(held[:end])
+			w.held.Reset()
+			w.held.WriteString(held[end:])
+		}
+	}
+	if time.Since(w.last) >= w.interval {
+		w.flush(ctx, false, "")
+	}
+}
+
+// release guards finished sentences and queues what remains of them.
+func (w *progressWriter) release(text string) {
+	guarded, blocked := w.guard(text)
+	if len(blocked) > 0 {
+		w.blocked = append(w.blocked, blocked...)
+		trailing := text[len(strings.TrimRightFunc(text, unicode.IsSpace)):]
+		if guarded = strings.TrimSpace(guarded); guarded != "" {
+			guarded += trailing
+		}
+	}
+	w.pending.WriteString(guarded)
+}
+
+// finish publishes what is left together with the done event.
+func (w *progressWriter) finish(ctx context.Context, finishReason string) {
+	if w.guard != nil && w.held.Len() > 0 {
+		w.release(w.held.String())
+		w.held.Reset()
+	}
+	w.flush(ctx, true, finishReason)
+}
+
+// finishedSentences returns the length of the prefix of text made of
+// finished sentences, ending at a line break or at the space after ., ! or
+// ?, as guardrails splits sentences. It is 0 while the first sentence is
+// still streaming.
+func finishedSentences(text string) int {
+	for i := len(text) - 1; i > 0; i-- {
+		switch text[i] {
+		case '\n':
+			return i + 1
+		case ' ':
+			if strings.ContainsRune(".!?", rune(text[i-1])) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func (w *progressWriter) flush(ctx context.Context, done bool, finishReason string) {
+	if w.pending.Len() == 0 && !done {
+		return
+	}
+	event := ProgressEvent{
+		RequestID:    w.requestID,
+		Sequence:     w.sequence,
+		Delta:        w.pending.String(),
+		Done:         done,
+		FinishReason: finishReason,
+	}
+	if done {
+		event.Guardrails = w.blocked
+	}
+	w.published.WriteString(event.Delta)
+	w.pending.Reset()
+	w.sequence++
+	w.last = time.Now()
+
+	if err := w.publisher.Publish(ctx, event); err != nil {
+		w.logger.Warn("failed to publish progress", map[string]interface{}{
+			"requestId": w.requestID,
+			"sequence":  event.Sequence,
+			"error":     err.Error(),
+		})
+	}
+}
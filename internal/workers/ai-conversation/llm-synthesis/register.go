@@ -0,0 +1,227 @@
+// internal/workers/ai-conversation/llm-synthesis/register.go
+package llmsynthesis
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/guardrails"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/prompts"
+	"camunda-workers/internal/common/retrieval"
+	"camunda-workers/internal/common/usage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+// Provider failures that retrying will not fix are reported by
+// permanentFailures before these mappings apply.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrLLMTimeout, Code: "LLM_TIMEOUT", Retryable: true},
+	{Err: ErrLLMSynthesisFailed, Code: "LLM_SYNTHESIS_FAILED", Retryable: true},
+}
+
+// permanentFailures reports provider failures that llm.IsRetryable rules
+// out, such as an exhausted quota, other 4xx statuses and undecodable
+// answers, as non-retryable LLM_SYNTHESIS_FAILED errors, so that the job
+// throws at once instead of failing the same way on every retry. It runs
+// inside MapErrors.
+func permanentFailures(next camunda.JobFunc) camunda.JobFunc {
+	return func(ctx context.Context, job *camunda.Job) error {
+		err := next(ctx, job)
+		if err == nil || !stderrors.Is(err, ErrLLMSynthesisFailed) || llm.IsRetryable(err) {
+			return err
+		}
+		failed := errors.NewLLMSynthesisFailedError(err)
+		failed.Retryable = false
+		return failed
+	}
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		provider, err := newProvider(deps.Config)
+		if err != nil {
+			return nil, err
+		}
+		memory, err := newConversationStore(deps)
+		if err != nil {
+			return nil, err
+		}
+		prompt, err := newPromptRegistry(deps)
+		if err != nil {
+			return nil, err
+		}
+		conf := &Config{
+			GenAIBaseURL: deps.Config.APIs.GenAI.BaseURL,
+			Timeout:      5 * time.Second,
+			MaxRetries:   1,
+			MaxTokens:    500,
+			Temperature:  0.7,
+			Provider:     provider,
+
+			Stream:           deps.Config.APIs.LLM.Stream,
+			Progress:         newProgressPublisher(deps),
+			ProgressInterval: config.GetDuration(deps.Config.APIs.LLM.ProgressInterval),
+
+			Memory:       memory,
+			HistoryTurns: deps.Config.Conversation.HistoryTurns,
+			TokenBudget:  deps.Config.Conversation.TokenBudget,
+
+			Prompts: prompt,
+
+			Grounding:            deps.Config.APIs.LLM.Grounding,
+			UngroundedConfidence: deps.Config.APIs.LLM.UngroundedConfidence,
+
+			Guardrails:        deps.Config.Guardrails.Enabled,
+			BlockedCategories: blockedCategories(deps.Config),
+
+			Usage: deps.Usage(),
+		}
+		if err := configureAnswerCache(deps, conf); err != nil {
+			return nil, err
+		}
+		handler := NewHandler(conf, &loggerAdapter{deps.Logger})
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("LLM_SYNTHESIS_FAILED", errorMappings...), permanentFailures)
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
+
+// newProvider builds the provider selected by apis.llm. The genai provider
+// defaults to the apis.genai endpoint and key.
+func newProvider(cfg *config.Config) (llm.Provider, error) {
+	opts := llm.Options{
+		BaseURL: cfg.APIs.LLM.BaseURL,
+		APIKey:  cfg.APIs.LLM.APIKey,
+		Model:   cfg.APIs.LLM.Model,
+		Timeout: config.GetDuration(cfg.APIs.LLM.Timeout),
+	}
+	name := cfg.APIs.LLM.Provider
+	if name == "" || name == llm.ProviderGenAI {
+		if opts.BaseURL == "" {
+			opts.BaseURL = cfg.APIs.GenAI.BaseURL
+		}
+		if opts.APIKey == "" {
+			opts.APIKey = cfg.APIs.GenAI.APIKey
+		}
+	}
+	return llm.New(name, opts)
+}
+
+// newProgressPublisher returns where streamed tokens are published, or nil
+// when streaming is off or the configured transport is not connected.
+func newProgressPublisher(deps *camunda.Dependencies) ProgressPublisher {
+	cfg := deps.Config.APIs.LLM
+	if !cfg.Stream {
+		return nil
+	}
+	switch cfg.Progress {
+	case "zeebe":
+		if deps.Camunda != nil {
+			return NewZeebeProgress(deps.Camunda.GetClient(), time.Minute)
+		}
+	case "", "redis":
+		if deps.Redis != nil {
+			return NewRedisProgress(deps.Redis.Client)
+		}
+	}
+	deps.Logger.Warn("LLM streaming enabled without a progress transport, answers are not streamed", map[string]interface{}{
+		"progress": cfg.Progress,
+	})
+	return nil
+}
+
+// configureAnswerCache turns on the answer cache configured under
+// apis.llm.answer_cache. It needs redis; reworded questions are matched with
+// the retrieval embedder and cached answers are checked against the
+// franchises in postgres when those are configured.
+func configureAnswerCache(deps *camunda.Dependencies, conf *Config) error {
+	cfg := deps.Config.APIs.LLM.AnswerCache
+	if cfg.TTL <= 0 {
+		return nil
+	}
+	if deps.Redis == nil {
+		deps.Logger.Warn("answer cache enabled without redis, answers are not cached", map[string]interface{}{
+			"ttl": cfg.TTL,
+		})
+		return nil
+	}
+	conf.AnswerCache = deps.Redis.Client
+	conf.AnswerCacheTTL = time.Duration(cfg.TTL) * time.Minute
+	conf.AnswerCandidates = cfg.Candidates
+	if deps.Postgres != nil {
+		conf.FranchiseVersions = PostgresFranchiseVersions{DB: deps.Postgres.DB}
+	}
+	if cfg.Similarity > 0 {
+		embedder, err := retrieval.NewEmbedder(deps.Config)
+		if err != nil {
+			return fmt.Errorf("answer cache embedder: %w", err)
+		}
+		conf.AnswerEmbedder = usage.Embedder(embedder, deps.Usage())
+		conf.AnswerSimilarity = cfg.Similarity
+	}
+	return nil
+}
+
+// newConversationStore returns the conversation store selected by
+// conversation.store, or nil when memory is off.
+func newConversationStore(deps *camunda.Dependencies) (conversation.Store, error) {
+	var rdb redis.UniversalClient
+	if deps.Redis != nil {
+		rdb = deps.Redis.Client
+	}
+	var db *sql.DB
+	if deps.Postgres != nil {
+		db = deps.Postgres.DB
+	}
+	cfg := deps.Config.Conversation
+	return conversation.NewStore(cfg.Store, rdb, db, time.Duration(cfg.TTL)*time.Minute)
+}
+
+// newPromptRegistry returns the prompt templates selected by
+// template.prompts_source, or nil to keep the built-in prompt.
+func newPromptRegistry(deps *camunda.Dependencies) (*prompts.Registry, error) {
+	cfg := deps.Config.Template
+	reload := config.GetDuration(cfg.PromptsReload)
+	switch cfg.PromptsSource {
+	case "", "file":
+		if cfg.PromptsPath == "" {
+			return nil, nil
+		}
+		return prompts.NewRegistry(prompts.FileSource{Path: cfg.PromptsPath}, reload), nil
+	case "postgres":
+		if deps.Postgres == nil {
+			return nil, stderrors.New("prompts_source postgres: postgres is not configured")
+		}
+		return prompts.NewRegistry(prompts.PostgresSource{DB: deps.Postgres.DB}, reload), nil
+	default:
+		return nil, fmt.Errorf("unknown prompts_source %q", cfg.PromptsSource)
+	}
+}
+
+// blockedCategories are the answer categories guardrails.blocked_categories
+// names, all of them by default.
+func blockedCategories(cfg *config.Config) []string {
+	if len(cfg.Guardrails.BlockedCategories) == 0 {
+		return guardrails.DefaultCategories
+	}
+	return cfg.Guardrails.BlockedCategories
+}
+
+// loggerAdapter lets the shared logger.Logger satisfy this package's Logger interface.
+type loggerAdapter struct {
+	logger.Logger
+}
+
+func (a *loggerAdapter) With(fields map[string]interface{}) Logger {
+	return &loggerAdapter{a.Logger.With(fields)}
+}
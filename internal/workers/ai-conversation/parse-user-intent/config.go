@@ -1,12 +1,32 @@
 // internal/workers/ai-conversation/parse-user-intent/config.go
 package parseuserintent
 
-import "time"
+import (
+	"time"
+
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/intent"
+	"camunda-workers/internal/common/usage"
+)
 
 type Config struct {
 	GenAIBaseURL string
 	Timeout      time.Duration
 	MaxRetries   int
+	// Memory, when set, supplies the entities resolved earlier in the
+	// conversation and records the ones parsed now.
+	Memory conversation.Store
+	// Guardrails redacts PII from the question before it is sent.
+	Guardrails bool
+	// Fallback classifies the question in process when the parse-intent
+	// API fails or has not answered within FallbackAfter; nil fails the
+	// job instead. FallbackAfter 0 gives the API until shortly before the
+	// job deadline.
+	Fallback      *intent.Classifier
+	FallbackAfter time.Duration
+	// Usage records the tokens and cost of each parse-intent call; nil
+	// records nothing.
+	Usage *usage.Meter
 }
 
 func LoadConfig() *Config {
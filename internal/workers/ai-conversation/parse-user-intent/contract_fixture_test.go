@@ -0,0 +1,59 @@
+// internal/workers/ai-conversation/parse-user-intent/contract_fixture_test.go
+package parseuserintent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(createIntentAPIResponse("franchise_search", 0.9,
+					[]Entity{{Type: "location", Value: "Lyon"}}, []string{"postgresql"})))
+			}))
+			t.Cleanup(server.Close)
+
+			config := createTestConfig()
+			config.GenAIBaseURL = server.URL
+			return NewHandler(config, NewTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"INTENT_PARSING_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+				}))
+				t.Cleanup(server.Close)
+
+				config := createTestConfig()
+				config.GenAIBaseURL = server.URL
+				config.MaxRetries = 0
+				_, err := NewHandler(config, NewTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+			"INTENT_API_TIMEOUT": func(t *testing.T, ctx context.Context, input *Input) error {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(200 * time.Millisecond)
+				}))
+				t.Cleanup(server.Close)
+
+				config := createTestConfig()
+				config.GenAIBaseURL = server.URL
+				config.Timeout = 50 * time.Millisecond
+				_, err := NewHandler(config, NewTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "INTENT_PARSING_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -0,0 +1,54 @@
+// internal/workers/ai-conversation/parse-user-intent/fallback.go
+package parseuserintent
+
+import (
+	"context"
+	"time"
+
+	"camunda-workers/internal/common/conversation"
+)
+
+// apiContext bounds the API call when a fallback is configured: by
+// FallbackAfter, and by the job deadline less fallbackMargin, so that a slow
+// API leaves time to classify locally. Without a fallback the API has the
+// whole job.
+func (h *Handler) apiContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if h.config.Fallback == nil {
+		return context.WithCancel(ctx)
+	}
+	budget := h.config.FallbackAfter
+	if deadline, ok := ctx.Deadline(); ok {
+		if left := time.Until(deadline) - fallbackMargin; budget <= 0 || left < budget {
+			budget = left
+		}
+	}
+	if budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, budget)
+}
+
+// classifyLocally answers with the fallback classifier. Like the API, it
+// carries the franchise of earlier questions over to a follow-up that names
+// none. Data sources are left to determineDataSources.
+func (h *Handler) classifyLocally(question string, conv *conversation.Conversation) *intentResponse {
+	result := h.config.Fallback.Classify(question)
+	entities := make([]Entity, 0, len(result.Entities)+1)
+	hasFranchise := false
+	for _, e := range result.Entities {
+		entities = append(entities, Entity{Type: e.Type, Value: e.Value})
+		hasFranchise = hasFranchise || e.Type == "franchise_name"
+	}
+	if conv != nil && !hasFranchise {
+		for _, e := range conv.Entities {
+			if e.Type == "franchise_name" {
+				entities = append(entities, Entity{Type: e.Type, Value: e.Value})
+			}
+		}
+	}
+	return &intentResponse{
+		Intent:     result.Intent,
+		Confidence: result.Confidence,
+		Entities:   entities,
+	}
+}
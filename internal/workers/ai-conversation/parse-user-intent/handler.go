@@ -9,19 +9,44 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/guardrails"
+	"camunda-workers/internal/common/llm"
+	"camunda-workers/internal/common/usage"
 )
 
 const (
 	TaskType = "parse-user-intent"
 )
 
+// Sources of an intent analysis.
+const (
+	SourceAPI      = "api"
+	SourceFallback = "fallback"
+)
+
+// fallbackMargin is left of the job deadline for the fallback classifier.
+const fallbackMargin = 250 * time.Millisecond
+
 var (
 	ErrIntentParsingFailed = errors.New("INTENT_PARSING_FAILED")
 	ErrIntentAPITimeout    = errors.New("INTENT_API_TIMEOUT")
 )
 
+// intentResponse is the body of a parse-intent response.
+type intentResponse struct {
+	Intent      string   `json:"intent"`
+	Confidence  float64  `json:"confidence"`
+	Entities    []Entity `json:"entities"`
+	DataSources []string `json:"dataSources"`
+	// Model and Usage are reported by services that meter their calls.
+	Model string `json:"model,omitempty"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
 // Logger interface definition
 type Logger interface {
 	Info(msg string, fields map[string]interface{})
@@ -48,46 +73,90 @@ func NewHandler(config *Config, log Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":            job.Key,
-		"workflowKey":       job.ProcessInstanceKey,
-	})
+func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
+	question := input.Question
+	var decisions []guardrails.Decision
+	if h.config.Guardrails {
+		question, decisions = guardrails.Redact(question, "question")
+		for _, d := range decisions {
+			h.logger.Warn("guardrail applied", map[string]interface{}{
+				"rule":   d.Rule,
+				"action": d.Action,
+				"count":  d.Count,
+			})
+		}
+	}
+	requestBody := map[string]interface{}{
+		"query": question,
+	}
 
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, fmt.Errorf("parse input: %w", err), 0)
-		return
+	conv := h.loadConversation(ctx, input.ConversationID)
+	requestContext := input.Context
+	if conv != nil && len(conv.Entities) > 0 {
+		// Prior entities let the API resolve follow-ups like "what about in Texas?"
+		requestContext = make(map[string]interface{}, len(input.Context)+1)
+		for k, v := range input.Context {
+			requestContext[k] = v
+		}
+		requestContext["priorEntities"] = conv.Entities
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
+	// Only include context if it's not nil
+	if requestContext != nil {
+		requestBody["context"] = requestContext
+	}
 
-	output, err := h.execute(ctx, &input)
+	source := SourceAPI
+	apiCtx, cancel := h.apiContext(ctx)
+	start := time.Now()
+	apiResponse, err := h.callAPI(apiCtx, requestBody)
+	cancel()
+	h.recordUsage(ctx, question, apiResponse, err, time.Since(start))
 	if err != nil {
-		retries := int32(0)
-		if errors.Is(err, ErrIntentAPITimeout) {
-			retries = 1
-		} else if errors.Is(err, ErrIntentParsingFailed) {
-			retries = 2
+		if h.config.Fallback == nil {
+			return nil, err
 		}
-		h.failJob(client, job, err, retries)
-		return
+		h.logger.Warn("intent API unavailable, classifying locally", map[string]interface{}{
+			"error": err.Error(),
+		})
+		apiResponse = h.classifyLocally(question, conv)
+		source = SourceFallback
 	}
 
-	h.completeJob(client, job, output)
-}
+	dataSources := apiResponse.DataSources
+	if len(dataSources) == 0 {
+		dataSources = h.determineDataSources(apiResponse.Intent, apiResponse.Entities)
+	}
 
-func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
-	requestBody := map[string]interface{}{
-		"query": input.Question,
+	output := &Output{
+		IntentAnalysis: IntentAnalysis{
+			PrimaryIntent: apiResponse.Intent,
+			Confidence:    apiResponse.Confidence,
+		},
+		DataSources: dataSources,
+		Entities:    apiResponse.Entities,
+		Guardrails:  decisions,
+		Source:      source,
 	}
 
-	// Only include context if it's not nil
-	if input.Context != nil {
-		requestBody["context"] = input.Context
+	h.logger.Info("intent parsed successfully", map[string]interface{}{
+		"intent":      apiResponse.Intent,
+		"confidence":  apiResponse.Confidence,
+		"entityCount": len(apiResponse.Entities),
+		"dataSources": dataSources,
+		"source":      source,
+	})
+
+	if conv != nil {
+		h.saveEntities(ctx, conv, apiResponse.Entities)
 	}
 
+	return output, nil
+}
+
+// callAPI asks the parse-intent API for the intent of the question in
+// requestBody, retrying failed requests MaxRetries times.
+func (h *Handler) callAPI(ctx context.Context, requestBody map[string]interface{}) (*intentResponse, error) {
 	body, _ := json.Marshal(requestBody)
 	req, err := http.NewRequestWithContext(ctx, "POST", h.config.GenAIBaseURL+"/api/ai/parse-intent", bytes.NewBuffer(body))
 	if err != nil {
@@ -144,39 +213,72 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	}
 	defer resp.Body.Close()
 
-	var apiResponse struct {
-		Intent      string   `json:"intent"`
-		Confidence  float64  `json:"confidence"`
-		Entities    []Entity `json:"entities"`
-		DataSources []string `json:"dataSources"`
-	}
+	var apiResponse intentResponse
 
 	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
 		return nil, fmt.Errorf("%w: decode error: %v", ErrIntentParsingFailed, err)
 	}
+	return &apiResponse, nil
+}
 
-	dataSources := apiResponse.DataSources
-	if len(dataSources) == 0 {
-		dataSources = h.determineDataSources(apiResponse.Intent, apiResponse.Entities)
+// recordUsage records the parse-intent call with the Usage meter, estimating
+// the tokens the service did not report.
+func (h *Handler) recordUsage(ctx context.Context, question string, resp *intentResponse, err error, latency time.Duration) {
+	if h.config.Usage == nil {
+		return
 	}
-
-	output := &Output{
-		IntentAnalysis: IntentAnalysis{
-			PrimaryIntent: apiResponse.Intent,
-			Confidence:    apiResponse.Confidence,
-		},
-		DataSources: dataSources,
-		Entities:    apiResponse.Entities,
+	r := usage.Record{
+		Operation: usage.OpIntent,
+		Provider:  llm.ProviderGenAI,
+		Latency:   latency,
+		Success:   err == nil,
+	}
+	if resp != nil {
+		r.Model = resp.Model
+		r.PromptTokens = resp.Usage.PromptTokens
+		r.CompletionTokens = resp.Usage.CompletionTokens
 	}
+	if r.PromptTokens == 0 && r.CompletionTokens == 0 {
+		r.Estimated = true
+		r.PromptTokens = usage.EstimateTokens(question)
+		if resp != nil {
+			answer, _ := json.Marshal(resp.Entities)
+			r.CompletionTokens = usage.EstimateTokens(resp.Intent) + usage.EstimateTokens(string(answer))
+		}
+	}
+	h.config.Usage.Record(ctx, r)
+}
 
-	h.logger.Info("intent parsed successfully", map[string]interface{}{
-		"intent":       apiResponse.Intent,
-		"confidence":   apiResponse.Confidence,
-		"entityCount":  len(apiResponse.Entities),
-		"dataSources":  dataSources,
-	})
+// loadConversation returns the remembered conversation, or nil without
+// memory or conversation ID. Memory is best effort: a failed load is logged
+// and the question parsed on its own.
+func (h *Handler) loadConversation(ctx context.Context, id string) *conversation.Conversation {
+	if h.config.Memory == nil || id == "" {
+		return nil
+	}
+	conv, err := h.config.Memory.Load(ctx, id)
+	if err != nil {
+		h.logger.Warn("failed to load conversation", map[string]interface{}{
+			"conversationId": id,
+			"error":          err.Error(),
+		})
+		return nil
+	}
+	return conv
+}
 
-	return output, nil
+func (h *Handler) saveEntities(ctx context.Context, conv *conversation.Conversation, entities []Entity) {
+	resolved := make([]conversation.Entity, len(entities))
+	for i, e := range entities {
+		resolved[i] = conversation.Entity{Type: e.Type, Value: e.Value}
+	}
+	conv.MergeEntities(resolved)
+	if err := h.config.Memory.Save(ctx, conv); err != nil {
+		h.logger.Warn("failed to save conversation", map[string]interface{}{
+			"conversationId": conv.ID,
+			"error":          err.Error(),
+		})
+	}
 }
 
 func (h *Handler) determineDataSources(intent string, entities []Entity) []string {
@@ -206,49 +308,6 @@ func (h *Handler) determineDataSources(intent string, entities []Entity) []strin
 	return sources
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-
-	if err != nil {
-		h.logger.Error("Failed to create complete job command", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  err.Error(),
-		})
-		return
-	}
-
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("Failed to send complete job command", map[string]interface{}{
-			"jobKey": job.Key,
-			"error":  err.Error(),
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, err error, retries int32) {
-	errorCode := "UNKNOWN_ERROR"
-	if errors.Is(err, ErrIntentAPITimeout) {
-		errorCode = "INTENT_API_TIMEOUT"
-	} else if errors.Is(err, ErrIntentParsingFailed) {
-		errorCode = "INTENT_PARSING_FAILED"
-	}
-
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":    job.Key,
-		"error":     err.Error(),
-		"errorCode": errorCode,
-	})
-
-	_, _ = client.NewFailJobCommand().
-		JobKey(job.Key).
-		Retries(retries).
-		ErrorMessage(errorCode + ": " + err.Error()).
-		Send(context.Background())
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
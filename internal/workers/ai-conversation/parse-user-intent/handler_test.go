@@ -8,9 +8,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/guardrails"
+	"camunda-workers/internal/common/intent"
+	"camunda-workers/internal/common/usage"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -677,10 +685,418 @@ func TestHandler_FullWorkflow(t *testing.T) {
 	assert.True(t, entityTypes["investment_amount"])
 }
 
+// ==========================
+// Conversation Memory Tests
+// ==========================
+
+func newRedisMemory(t *testing.T) (*conversation.RedisStore, *miniredis.Miniredis) {
+	mr, err := miniredis.Run()
+	assert.NoError(t, err)
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return conversation.NewRedisStore(rdb, time.Hour), mr
+}
+
+func TestHandler_Memory_SendsPriorEntitiesAndMergesNewOnes(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createIntentAPIResponse("location_inquiry", 0.85, []Entity{
+			{Type: "franchise_name", Value: "Bean There"},
+			{Type: "location", Value: "texas"},
+		}, nil)))
+	}))
+	defer server.Close()
+
+	memory, _ := newRedisMemory(t)
+	ctx := context.Background()
+	assert.NoError(t, memory.Save(ctx, &conversation.Conversation{
+		ID: "conv-1",
+		Entities: []conversation.Entity{
+			{Type: "franchise_name", Value: "Bean There"},
+			{Type: "location", Value: "austin"},
+		},
+	}))
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Memory = memory
+	handler := NewHandler(config, NewTestLogger(t))
+
+	_, err := handler.execute(ctx, &Input{
+		Question:       "What about in Texas?",
+		Context:        map[string]interface{}{"channel": "web"},
+		ConversationID: "conv-1",
+	})
+	assert.NoError(t, err)
+
+	reqContext := received["context"].(map[string]interface{})
+	assert.Equal(t, "web", reqContext["channel"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "franchise_name", "value": "Bean There"},
+		map[string]interface{}{"type": "location", "value": "austin"},
+	}, reqContext["priorEntities"])
+
+	conv, err := memory.Load(ctx, "conv-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []conversation.Entity{
+		{Type: "franchise_name", Value: "Bean There"},
+		{Type: "location", Value: "texas"},
+	}, conv.Entities)
+}
+
+func TestHandler_Memory_NewConversationSendsNoPriorEntities(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createIntentAPIResponse("cost_inquiry", 0.9, []Entity{{Type: "franchise_name", Value: "Bean There"}}, nil)))
+	}))
+	defer server.Close()
+
+	memory, _ := newRedisMemory(t)
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Memory = memory
+	handler := NewHandler(config, NewTestLogger(t))
+
+	_, err := handler.execute(context.Background(), &Input{Question: "What does Bean There cost?", ConversationID: "conv-2"})
+	assert.NoError(t, err)
+	assert.NotContains(t, received, "context")
+
+	conv, err := memory.Load(context.Background(), "conv-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []conversation.Entity{{Type: "franchise_name", Value: "Bean There"}}, conv.Entities)
+}
+
+func TestHandler_Memory_UnavailableStoreDoesNotFailJob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createIntentAPIResponse("cost_inquiry", 0.9, nil, nil)))
+	}))
+	defer server.Close()
+
+	memory, mr := newRedisMemory(t)
+	mr.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Memory = memory
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{Question: "What does it cost?", ConversationID: "conv-3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cost_inquiry", output.IntentAnalysis.PrimaryIntent)
+}
+
 // ==========================
 // Benchmark Tests
 // ==========================
 
+// ==========================
+// Guardrail Tests
+// ==========================
+
+func TestHandler_Guardrails_RedactsQuestion(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createIntentAPIResponse("cost_inquiry", 0.9, nil, nil)))
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Guardrails = true
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{
+		Question: "I'm jane.doe@example.com, SSN 123-45-6789, call (512) 555-0134 about Bean There costs",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "I'm [REDACTED_EMAIL], SSN [REDACTED_SSN], call [REDACTED_PHONE] about Bean There costs", received["query"])
+	assert.Equal(t, []guardrails.Decision{
+		{Stage: "input", Rule: "pii_email", Action: "redacted", Target: "question", Count: 1},
+		{Stage: "input", Rule: "pii_ssn", Action: "redacted", Target: "question", Count: 1},
+		{Stage: "input", Rule: "pii_phone", Action: "redacted", Target: "question", Count: 1},
+	}, output.Guardrails)
+
+	// Without guardrails the question is sent as asked.
+	config.Guardrails = false
+	output, err = handler.execute(context.Background(), &Input{Question: "Email me at jane.doe@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Email me at jane.doe@example.com", received["query"])
+	assert.Nil(t, output.Guardrails)
+}
+
+// ==========================
+// Fallback Tests
+// ==========================
+
+const (
+	trainingFile  = "../../../../configs/intent-training.jsonl"
+	gazetteerFile = "../../../../configs/us-gazetteer.json"
+)
+
+// newFallbackClassifier trains the fallback on the shipped training and
+// gazetteer files, with a small franchise catalog.
+func newFallbackClassifier(t *testing.T) *intent.Classifier {
+	examples, err := intent.ReadExamples(trainingFile)
+	assert.NoError(t, err)
+	gazetteer, err := intent.ReadGazetteer(gazetteerFile)
+	assert.NoError(t, err)
+	return intent.New(intent.Options{
+		Model:      intent.Train(examples),
+		Gazetteer:  gazetteer,
+		Franchises: []string{"Bean There", "Texas Roadhouse", "McDonald's"},
+		Categories: []string{"Food & Beverage"},
+	})
+}
+
+func TestHandler_Fallback_APIErrorClassifiesLocally(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.MaxRetries = 0
+	config.Fallback = newFallbackClassifier(t)
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{
+		Question: "How much does a McDonalds franchise cost in Austin, TX? I have $250k.",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceFallback, output.Source)
+	assert.Equal(t, "cost_inquiry", output.IntentAnalysis.PrimaryIntent)
+	assert.Greater(t, output.IntentAnalysis.Confidence, 0.5)
+	assert.Equal(t, []Entity{
+		{Type: "franchise_name", Value: "McDonald's"},
+		{Type: "investment_amount", Value: "250000"},
+		{Type: "location", Value: "Austin"},
+		{Type: "location", Value: "Texas"},
+	}, output.Entities)
+	assert.Equal(t, []string{"internal_db", "search_index"}, output.DataSources)
+}
+
+func TestHandler_Fallback_SlowAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Fallback = newFallbackClassifier(t)
+	config.FallbackAfter = 50 * time.Millisecond
+	handler := NewHandler(config, NewTestLogger(t))
+
+	start := time.Now()
+	output, err := handler.execute(context.Background(), &Input{Question: "Is the coffee market growing in Florida?"})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, SourceFallback, output.Source)
+	assert.Equal(t, "market_research", output.IntentAnalysis.PrimaryIntent)
+	assert.Equal(t, []Entity{
+		{Type: "location", Value: "Florida"},
+		{Type: "category", Value: "coffee"},
+	}, output.Entities)
+	assert.Equal(t, []string{"internal_db", "search_index", "external_web"}, output.DataSources)
+}
+
+func TestHandler_Fallback_AnswersBeforeJobDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Fallback = newFallbackClassifier(t)
+	handler := NewHandler(config, NewTestLogger(t))
+
+	// Without FallbackAfter the API has until fallbackMargin before the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	output, err := handler.execute(ctx, &Input{Question: "Hello!"})
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, SourceFallback, output.Source)
+	assert.Equal(t, "greeting", output.IntentAnalysis.PrimaryIntent)
+}
+
+func TestHandler_Fallback_KeepsRememberedFranchise(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	memory, _ := newRedisMemory(t)
+	ctx := context.Background()
+	assert.NoError(t, memory.Save(ctx, &conversation.Conversation{
+		ID:       "conv-4",
+		Entities: []conversation.Entity{{Type: "franchise_name", Value: "Bean There"}},
+	}))
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.MaxRetries = 0
+	config.Memory = memory
+	config.Fallback = newFallbackClassifier(t)
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(ctx, &Input{Question: "What about in Texas?", ConversationID: "conv-4"})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceFallback, output.Source)
+	assert.Equal(t, []Entity{
+		{Type: "location", Value: "Texas"},
+		{Type: "franchise_name", Value: "Bean There"},
+	}, output.Entities)
+
+	// A named franchise is a new subject.
+	output, err = handler.execute(ctx, &Input{Question: "What does Texas Roadhouse cost?", ConversationID: "conv-4"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Entity{{Type: "franchise_name", Value: "Texas Roadhouse"}}, output.Entities)
+}
+
+func TestHandler_Fallback_APIAnswerIsKept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(createIntentAPIResponse("franchise_inquiry", 0.92, nil, []string{"internal_db"})))
+	}))
+	defer server.Close()
+
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Fallback = newFallbackClassifier(t)
+	config.FallbackAfter = time.Second
+	handler := NewHandler(config, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{Question: "What does Bean There cost?"})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceAPI, output.Source)
+	assert.Equal(t, "franchise_inquiry", output.IntentAnalysis.PrimaryIntent)
+	assert.Equal(t, 0.92, output.IntentAnalysis.Confidence)
+}
+
+func TestIntentClassifier_InvestmentAmounts(t *testing.T) {
+	classifier := newFallbackClassifier(t)
+	tests := []struct {
+		question string
+		want     []string
+	}{
+		{"I have $250k to invest", []string{"250000"}},
+		{"between $100,000 and $1.5 million", []string{"100000", "1500000"}},
+		{"about 80k or 90 thousand dollars", []string{"80000", "90000"}},
+		{"opened 25 units in 2024", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			var got []string
+			for _, e := range classifier.Entities(tt.question) {
+				if e.Type == "investment_amount" {
+					got = append(got, e.Value)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// ==========================
+// Usage Tests
+// ==========================
+
+// usageStore keeps the records of a usage.Meter.
+type usageStore struct {
+	mu      sync.Mutex
+	records []usage.Record
+}
+
+func (s *usageStore) Insert(ctx context.Context, r usage.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *usageStore) Spent(ctx context.Context, userID string, since time.Time) (usage.Spend, error) {
+	return usage.Spend{}, nil
+}
+
+func TestHandler_Usage_RecordsReportedTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"intent": "cost_inquiry", "confidence": 0.9, "entities": [], "model": "intent-small",
+			"usage": {"prompt_tokens": 120, "completion_tokens": 30}}`))
+	}))
+	defer server.Close()
+
+	store := &usageStore{}
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.Usage = &usage.Meter{Store: store, Prices: usage.Prices{"intent-small": {Prompt: 1, Completion: 2}}}
+	handler := NewHandler(config, NewTestLogger(t))
+
+	ctx := usage.WithScope(context.Background(), usage.Scope{UserID: "user-1", ConversationID: "conv-1", ProcessInstanceKey: 42})
+	_, err := handler.execute(ctx, &Input{Question: "How much does Bean There cost?"})
+	assert.NoError(t, err)
+
+	assert.Len(t, store.records, 1)
+	r := store.records[0]
+	assert.Equal(t, usage.OpIntent, r.Operation)
+	assert.Equal(t, "intent-small", r.Model)
+	assert.Equal(t, 120, r.PromptTokens)
+	assert.Equal(t, 30, r.CompletionTokens)
+	assert.False(t, r.Estimated)
+	assert.True(t, r.Success)
+	assert.InDelta(t, 0.00018, r.CostUSD, 1e-9)
+	assert.Equal(t, "user-1", r.UserID)
+	assert.Equal(t, int64(42), r.ProcessInstanceKey)
+}
+
+func TestHandler_Usage_EstimatesTokensAndRecordsFailures(t *testing.T) {
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(createIntentAPIResponse("cost_inquiry", 0.9, nil, nil)))
+	}))
+	defer server.Close()
+
+	store := &usageStore{}
+	config := createTestConfig()
+	config.GenAIBaseURL = server.URL
+	config.MaxRetries = 0
+	config.Usage = &usage.Meter{Store: store}
+	handler := NewHandler(config, NewTestLogger(t))
+
+	_, err := handler.execute(context.Background(), &Input{Question: "How much does Bean There cost?"})
+	assert.Error(t, err)
+	fail = false
+	_, err = handler.execute(context.Background(), &Input{Question: "How much does Bean There cost?"})
+	assert.NoError(t, err)
+
+	assert.Len(t, store.records, 2)
+	assert.False(t, store.records[0].Success)
+	assert.Zero(t, store.records[0].CompletionTokens)
+	assert.True(t, store.records[1].Success)
+	assert.True(t, store.records[1].Estimated)
+	assert.Equal(t, usage.EstimateTokens("How much does Bean There cost?"), store.records[1].PromptTokens)
+	assert.Greater(t, store.records[1].CompletionTokens, 0)
+}
+
 func BenchmarkHandler_Execute(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := createIntentAPIResponse("test_intent", 0.8, []Entity{}, []string{"internal_db"})
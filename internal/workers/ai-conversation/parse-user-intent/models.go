@@ -1,15 +1,23 @@
 // internal/workers/ai-conversation/parse-user-intent/models.go
 package parseuserintent
 
+import "camunda-workers/internal/common/guardrails"
+
 type Input struct {
-	Question string                 `json:"question"`
-	Context  map[string]interface{} `json:"context"`
+	Question       string                 `json:"question"`
+	Context        map[string]interface{} `json:"context"`
+	ConversationID string                 `json:"conversationId"`
 }
 
 type Output struct {
 	IntentAnalysis IntentAnalysis `json:"intentAnalysis"`
 	DataSources    []string       `json:"dataSources"`
 	Entities       []Entity       `json:"entities"`
+	// Guardrails records the PII redacted from the question, for audit.
+	Guardrails []guardrails.Decision `json:"guardrails,omitempty"`
+	// Source is SourceAPI, or SourceFallback when the fallback classifier
+	// answered because the API failed or was slow.
+	Source string `json:"source"`
 }
 
 type IntentAnalysis struct {
@@ -0,0 +1,111 @@
+// internal/workers/ai-conversation/parse-user-intent/register.go
+package parseuserintent
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/intent"
+	"camunda-workers/internal/common/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrIntentAPITimeout, Code: "INTENT_API_TIMEOUT", Retryable: true},
+	{Err: ErrIntentParsingFailed, Code: "INTENT_PARSING_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		memory, err := newConversationStore(deps)
+		if err != nil {
+			return nil, err
+		}
+		fallback, err := newFallback(deps)
+		if err != nil {
+			return nil, err
+		}
+		handler := NewHandler(
+			&Config{
+				GenAIBaseURL:  deps.Config.APIs.GenAI.BaseURL,
+				Timeout:       30 * time.Second,
+				MaxRetries:    2,
+				Memory:        memory,
+				Guardrails:    deps.Config.Guardrails.Enabled,
+				Fallback:      fallback,
+				FallbackAfter: config.GetDuration(deps.Config.APIs.GenAI.IntentFallback.After),
+				Usage:         deps.Usage(),
+			},
+			&loggerAdapter{deps.Logger},
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("INTENT_PARSING_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
+
+// newConversationStore returns the conversation store selected by
+// conversation.store, or nil when memory is off.
+func newConversationStore(deps *camunda.Dependencies) (conversation.Store, error) {
+	var rdb redis.UniversalClient
+	if deps.Redis != nil {
+		rdb = deps.Redis.Client
+	}
+	var db *sql.DB
+	if deps.Postgres != nil {
+		db = deps.Postgres.DB
+	}
+	cfg := deps.Config.Conversation
+	return conversation.NewStore(cfg.Store, rdb, db, time.Duration(cfg.TTL)*time.Minute)
+}
+
+// newFallback builds the classifier of apis.genai.intent_fallback, or returns
+// nil when it is off. Franchise names are read from Postgres once, at
+// startup; without them the classifier still finds the other entities.
+func newFallback(deps *camunda.Dependencies) (*intent.Classifier, error) {
+	cfg := deps.Config.APIs.GenAI.IntentFallback
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var opts intent.Options
+	if cfg.TrainingPath != "" {
+		examples, err := intent.ReadExamples(cfg.TrainingPath)
+		if err != nil {
+			return nil, err
+		}
+		opts.Model = intent.Train(examples)
+	}
+	if cfg.GazetteerPath != "" {
+		gazetteer, err := intent.ReadGazetteer(cfg.GazetteerPath)
+		if err != nil {
+			return nil, err
+		}
+		opts.Gazetteer = gazetteer
+	}
+	if deps.Postgres != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		franchises, categories, err := intent.LoadCatalog(ctx, deps.Postgres.DB)
+		if err != nil {
+			deps.Logger.Warn("failed to load franchises for the intent fallback", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		opts.Franchises, opts.Categories = franchises, categories
+	}
+	return intent.New(opts), nil
+}
+
+// loggerAdapter lets the shared logger.Logger satisfy this package's Logger interface.
+type loggerAdapter struct {
+	logger.Logger
+}
+
+func (a *loggerAdapter) With(fields map[string]interface{}) Logger {
+	return &loggerAdapter{a.Logger.With(fields)}
+}
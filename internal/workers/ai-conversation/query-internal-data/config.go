@@ -7,6 +7,10 @@ type Config struct {
 	Timeout    time.Duration
 	CacheTTL   time.Duration
 	MaxResults int
+
+	// Retriever searches franchise passages for the question; nil disables
+	// retrieval.
+	Retriever Retriever
 }
 
 func LoadConfig() *Config {
@@ -0,0 +1,49 @@
+// internal/workers/ai-conversation/query-internal-data/contract_fixture_test.go
+package queryinternaldata
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+const franchiseQuery = `SELECT id, name, description, investment_min, investment_max, category FROM franchises`
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["entities"] = []interface{}{
+				map[string]interface{}{"type": "franchise_name", "value": "McDonald's"},
+			}
+			sample["dataSources"] = []interface{}{"internal_db"}
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			db, mock := setupMockDB(t)
+			mock.ExpectQuery(franchiseQuery).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "investment_min", "investment_max", "category"}).
+					AddRow("1", "McDonald's", "Fast food chain", 1000000, 2000000, "fast_food"))
+
+			handler := NewHandler(createTestConfig(), db, &elasticsearch.Client{}, setupRedis(t), NewTestLogger(t))
+			return handler.Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"INTERNAL_DATA_QUERY_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				db, mock := setupMockDB(t)
+				mock.ExpectQuery(franchiseQuery).WillReturnError(fmt.Errorf("postgres: connection refused"))
+
+				handler := NewHandler(createTestConfig(), db, &elasticsearch.Client{}, setupRedis(t), NewTestLogger(t))
+				_, err := handler.Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "INTERNAL_DATA_QUERY_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
 	"github.com/redis/go-redis/v9"
@@ -54,41 +52,12 @@ func NewHandler(config *Config, db *sql.DB, esClient *elasticsearch.Client, redi
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		errorCode := "INTERNAL_DATA_QUERY_FAILED"
-		retries := int32(0)
-		if strings.Contains(err.Error(), "postgres") || strings.Contains(err.Error(), "elasticsearch") {
-			retries = 2
-		}
-		h.failJob(client, job, errorCode, err.Error(), retries)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	cacheKey := h.buildCacheKey(input.Entities)
 	if val, err := h.redisClient.Get(ctx, cacheKey).Result(); err == nil {
 		var data map[string]interface{}
 		if err := json.Unmarshal([]byte(val), &data); err == nil {
-			return &Output{InternalData: data}, nil
+			return &Output{InternalData: data, Passages: h.retrieve(ctx, input.Question, data)}, nil
 		}
 	}
 
@@ -152,7 +121,7 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 		"resultCount": len(results),
 	})
 
-	return &Output{InternalData: results}, nil
+	return &Output{InternalData: results, Passages: h.retrieve(ctx, input.Question, results)}, nil
 }
 
 func (h *Handler) buildCacheKey(entities []Entity) string {
@@ -374,43 +343,6 @@ func (h *Handler) parseInt(s string) (int, error) {
 	return strconv.Atoi(clean)
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err.Error(),
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err.Error(),
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
@@ -5,10 +5,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	cfgpkg "camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/retrieval"
+
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/elastic/go-elasticsearch/v8"
@@ -335,6 +341,129 @@ func TestHandler_Execute_ElasticsearchError(t *testing.T) {
 // Unit Tests
 // ==========================
 
+// stubEmbedder returns the same vector for every text.
+type stubEmbedder struct{ vector []float32 }
+
+func (e stubEmbedder) Name() string { return "stub" }
+
+func (e stubEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = e.vector
+	}
+	return out, nil
+}
+
+type failingRetriever struct{}
+
+func (failingRetriever) Retrieve(ctx context.Context, question string, franchiseIDs []string) ([]retrieval.Passage, error) {
+	return nil, errors.New("index unavailable")
+}
+
+// newPassageIndex serves a passage search, recording the request body.
+func newPassageIndex(t *testing.T, hits string, body *map[string]interface{}) *elasticsearch.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		assert.Equal(t, "/franchise_passages/_search", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(body))
+		w.Write([]byte(`{"hits":{"hits":` + hits + `}}`))
+	}))
+	t.Cleanup(server.Close)
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	assert.NoError(t, err)
+	return es
+}
+
+func TestHandler_Execute_RetrievesPassages(t *testing.T) {
+	rdb := setupRedis(t)
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery(`SELECT id, name, description, investment_min, investment_max, category FROM franchises`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "investment_min", "investment_max", "category"}).
+			AddRow("f-1", "McDonald's", "Fast food chain", 1000000, 2000000, "fast_food"))
+
+	var search map[string]interface{}
+	es := newPassageIndex(t, `[{"_id":"fdd:7#2","_score":3.5,"_source":{
+		"document_id":"fdd:7","franchise_id":"f-1","kind":"fdd","title":"McDonald's FDD 2024",
+		"text":"The initial franchise fee is $45,000.","url":"https://example.com/fdd.pdf"}}]`, &search)
+
+	config := createTestConfig()
+	config.Retriever = &retrieval.Retriever{
+		Embedder: stubEmbedder{vector: []float32{0.5, 0.75}},
+		Searcher: retrieval.NewESIndex(es, cfgpkg.RetrievalConfig{
+			Index: "franchise_passages", NumCandidates: 50, VectorBoost: 0.7, KeywordBoost: 0.3,
+		}),
+		TopK: 3,
+	}
+	handler := NewHandler(config, db, es, rdb, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{
+		Entities:    []Entity{{Type: "franchise_name", Value: "McDonald's"}},
+		DataSources: []string{"internal_db"},
+		Question:    "What is the franchise fee for McDonald's?",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []retrieval.Passage{{
+		ID:          "fdd:7#2",
+		DocumentID:  "fdd:7",
+		FranchiseID: "f-1",
+		Kind:        retrieval.KindFDD,
+		Title:       "McDonald's FDD 2024",
+		Text:        "The initial franchise fee is $45,000.",
+		URL:         "https://example.com/fdd.pdf",
+		Score:       3.5,
+	}}, output.Passages)
+
+	// Hybrid query: kNN on the question embedding plus BM25 on its text,
+	// both restricted to the franchises found.
+	filter := []interface{}{map[string]interface{}{"terms": map[string]interface{}{"franchise_id": []interface{}{"f-1"}}}}
+	assert.Equal(t, float64(3), search["size"])
+	knn := search["knn"].(map[string]interface{})
+	assert.Equal(t, "embedding", knn["field"])
+	assert.Equal(t, []interface{}{0.5, 0.75}, knn["query_vector"])
+	assert.Equal(t, float64(50), knn["num_candidates"])
+	assert.Equal(t, 0.7, knn["boost"])
+	assert.Equal(t, filter, knn["filter"])
+	boolQuery := search["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"text": map[string]interface{}{
+		"query": "What is the franchise fee for McDonald's?", "boost": 0.3,
+	}}, boolQuery["must"].(map[string]interface{})["match"])
+	assert.Equal(t, filter, boolQuery["filter"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// A cache hit still retrieves passages for the new question.
+	search = nil
+	output, err = handler.execute(context.Background(), &Input{
+		Entities:    []Entity{{Type: "franchise_name", Value: "McDonald's"}},
+		DataSources: []string{"internal_db"},
+		Question:    "How much are the royalties?",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, output.Passages, 1)
+	assert.Equal(t, "How much are the royalties?", search["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"].(map[string]interface{})["match"].(map[string]interface{})["text"].(map[string]interface{})["query"])
+}
+
+func TestHandler_Execute_RetrievalFailureKeepsInternalData(t *testing.T) {
+	rdb := setupRedis(t)
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery(`SELECT id, name, description, investment_min, investment_max, category FROM franchises`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "investment_min", "investment_max", "category"}).
+			AddRow("f-1", "McDonald's", "Fast food chain", 1000000, 2000000, "fast_food"))
+
+	config := createTestConfig()
+	config.Retriever = failingRetriever{}
+	handler := NewHandler(config, db, &elasticsearch.Client{}, rdb, NewTestLogger(t))
+
+	output, err := handler.execute(context.Background(), &Input{
+		Entities:    []Entity{{Type: "franchise_name", Value: "McDonald's"}},
+		DataSources: []string{"internal_db"},
+		Question:    "What is the franchise fee?",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, output.InternalData, "franchises")
+	assert.Empty(t, output.Passages)
+}
+
 func TestHandler_BuildCacheKey(t *testing.T) {
 	handler := NewHandler(createTestConfig(), nil, nil, nil, NewTestLogger(t))
 
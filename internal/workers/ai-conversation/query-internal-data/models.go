@@ -1,13 +1,17 @@
 // internal/workers/ai-conversation/query-internal-data/models.go
 package queryinternaldata
 
+import "camunda-workers/internal/common/retrieval"
+
 type Input struct {
 	Entities    []Entity `json:"entities"`
 	DataSources []string `json:"dataSources"`
+	Question    string   `json:"question"` // searched for passages when retrieval is enabled
 }
 
 type Output struct {
 	InternalData map[string]interface{} `json:"internalData"`
+	Passages     []retrieval.Passage    `json:"passages,omitempty"`
 }
 
 type Entity struct {
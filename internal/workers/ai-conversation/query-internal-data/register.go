@@ -0,0 +1,49 @@
+// internal/workers/ai-conversation/query-internal-data/register.go
+package queryinternaldata
+
+import (
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/retrieval"
+	"camunda-workers/internal/common/usage"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrInternalDataQueryFailed, Code: "INTERNAL_DATA_QUERY_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		cfg := &Config{
+			Timeout:    2 * time.Second,
+			CacheTTL:   5 * time.Minute,
+			MaxResults: 10,
+		}
+		retriever, err := retrieval.NewRetriever(deps.Config, deps.Elasticsearch.Client)
+		if err != nil {
+			return nil, err
+		}
+		if retriever != nil {
+			retriever.Embedder = usage.Embedder(retriever.Embedder, deps.Usage())
+			cfg.Retriever = retriever
+		}
+		handler := NewHandler(
+			cfg,
+			deps.Postgres.DB, deps.Elasticsearch.Client, deps.Redis.Client, &loggerAdapter{deps.Logger},
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("INTERNAL_DATA_QUERY_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
+
+// loggerAdapter lets the shared logger.Logger satisfy this package's Logger interface.
+type loggerAdapter struct {
+	logger.Logger
+}
+
+func (a *loggerAdapter) With(fields map[string]interface{}) Logger {
+	return &loggerAdapter{a.Logger.With(fields)}
+}
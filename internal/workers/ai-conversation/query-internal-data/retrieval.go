@@ -0,0 +1,61 @@
+// internal/workers/ai-conversation/query-internal-data/retrieval.go
+package queryinternaldata
+
+import (
+	"context"
+
+	"camunda-workers/internal/common/retrieval"
+)
+
+// Retriever finds the franchise passages relevant to a question.
+type Retriever interface {
+	Retrieve(ctx context.Context, question string, franchiseIDs []string) ([]retrieval.Passage, error)
+}
+
+// retrieve returns the passages for the question, restricted to the
+// franchises the internal data found when it found any. Passages only add
+// context to the answer, so a failed search is logged and the internal data
+// returned without them.
+func (h *Handler) retrieve(ctx context.Context, question string, internalData map[string]interface{}) []retrieval.Passage {
+	if h.config.Retriever == nil || question == "" {
+		return nil
+	}
+	passages, err := h.config.Retriever.Retrieve(ctx, question, franchiseIDs(internalData))
+	if err != nil {
+		h.logger.Warn("failed to retrieve passages", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	return passages
+}
+
+// franchiseIDs collects the IDs of the franchises and outlets in the
+// internal data, fresh or decoded from the cache.
+func franchiseIDs(internalData map[string]interface{}) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(m map[string]interface{}, field string) {
+		if id, ok := m[field].(string); ok && id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	collect := func(key, field string) {
+		switch rows := internalData[key].(type) {
+		case []interface{}:
+			for _, row := range rows {
+				if m, ok := row.(map[string]interface{}); ok {
+					add(m, field)
+				}
+			}
+		case []map[string]interface{}:
+			for _, m := range rows {
+				add(m, field)
+			}
+		}
+	}
+	collect("franchises", "id")
+	collect("outlets", "franchiseId")
+	return ids
+}
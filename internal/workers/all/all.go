@@ -0,0 +1,49 @@
+// internal/workers/all/all.go
+
+// Package all links every worker package into the binary so that each one
+// registers its factory with the camunda worker registry.
+package all
+
+import (
+	// Infrastructure Workers
+	_ "camunda-workers/internal/workers/infrastructure/audit-log"
+	_ "camunda-workers/internal/workers/infrastructure/build-response"
+	_ "camunda-workers/internal/workers/infrastructure/callback-to-bff"
+	_ "camunda-workers/internal/workers/infrastructure/error-handler"
+	_ "camunda-workers/internal/workers/infrastructure/select-template"
+	_ "camunda-workers/internal/workers/infrastructure/validate-subscription"
+
+	// Data Access Workers
+	_ "camunda-workers/internal/workers/data-access/query-elasticsearch"
+	_ "camunda-workers/internal/workers/data-access/query-postgresql"
+
+	// Business Logic Workers
+	_ "camunda-workers/internal/workers/franchise/apply-relevance-ranking"
+	_ "camunda-workers/internal/workers/franchise/calculate-match-score"
+	_ "camunda-workers/internal/workers/franchise/parse-search-filters"
+
+	_ "camunda-workers/internal/workers/application/check-priority-routing"
+	_ "camunda-workers/internal/workers/application/check-readiness-score"
+	_ "camunda-workers/internal/workers/application/create-application-record"
+	_ "camunda-workers/internal/workers/application/send-notification"
+	_ "camunda-workers/internal/workers/application/validate-application-data"
+
+	// AI/ML Workers
+	_ "camunda-workers/internal/workers/ai-conversation/enrich-web-search"
+	_ "camunda-workers/internal/workers/ai-conversation/llm-synthesis"
+	_ "camunda-workers/internal/workers/ai-conversation/parse-user-intent"
+	_ "camunda-workers/internal/workers/ai-conversation/query-internal-data"
+
+	// Authentication & Utility Workers
+	_ "camunda-workers/internal/workers/auth/auth-logout"
+	_ "camunda-workers/internal/workers/auth/auth-signin-google"
+	_ "camunda-workers/internal/workers/auth/auth-signin-linkedin"
+	_ "camunda-workers/internal/workers/auth/auth-signup-google"
+	_ "camunda-workers/internal/workers/auth/auth-signup-linkedin"
+	_ "camunda-workers/internal/workers/auth/captcha-verify"
+	_ "camunda-workers/internal/workers/communication/email-send"
+	_ "camunda-workers/internal/workers/crm/crm-user-create"
+
+	// User Data Workers
+	_ "camunda-workers/internal/workers/user/user-data-anonymize"
+)
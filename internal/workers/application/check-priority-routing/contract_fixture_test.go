@@ -0,0 +1,34 @@
+// internal/workers/application/check-priority-routing/contract_fixture_test.go
+package checkpriorityrouting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Lookup failures default to low priority; only a
+// job cancelled during the lookup fails.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			rdb := setupRedis(t)
+			db, _ := setupMockDB(t)
+			rdb.Set(ctx, "franchisor:account:"+input.FranchiseID, AccountTypePremium, time.Minute)
+			return NewHandler(createTestConfig(), db, rdb, newTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"PRIORITY_ROUTING_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				db, _ := setupMockDB(t)
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err := NewHandler(createTestConfig(), db, setupRedis(t), newTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "PRIORITY_ROUTING_FAILED",
+	}
+}
@@ -4,14 +4,10 @@ package checkpriorityrouting
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"time"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
-	"github.com/redis/go-redis/v9"
 	"camunda-workers/internal/common/logger"
+	"github.com/redis/go-redis/v9"
 )
 
 const (
@@ -34,36 +30,16 @@ func NewHandler(config *Config, db *sql.DB, redis *redis.Client, log logger.Logg
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":       job.Key,
-		"workflowKey":  job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PRIORITY_ROUTING_FAILED", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "PRIORITY_ROUTING_FAILED", err.Error(), 0)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
 
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	accountType, err := h.getFranchisorAccountType(ctx, input.FranchiseID)
+	if err != nil && ctx.Err() != nil {
+		// A cancelled job must not complete with the default priority.
+		return nil, err
+	}
 	if err != nil {
 		h.logger.Warn("failed to fetch franchisor account type, defaulting to standard", map[string]interface{}{
 			"franchiseId": input.FranchiseID,
@@ -130,43 +106,6 @@ func (h *Handler) determinePriority(accountType string) string {
 	}
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 // // internal/workers/application/check-priority-routing/handler.go
 // package checkpriorityrouting
 
@@ -260,8 +199,8 @@ func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode,
 // 	}
 
 // 	row := h.db.QueryRowContext(ctx, `
-// 		SELECT account_type 
-// 		FROM franchisors 
+// 		SELECT account_type
+// 		FROM franchisors
 // 		WHERE franchise_id = $1`, franchiseID)
 
 // 	var accountType string
@@ -328,4 +267,4 @@ func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode,
 
 // func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 //     return h.execute(ctx, input)
-// }
\ No newline at end of file
+// }
@@ -0,0 +1,21 @@
+// internal/workers/application/check-priority-routing/register.go
+package checkpriorityrouting
+
+import (
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				CacheTTL: 30 * time.Minute,
+			},
+			deps.Postgres.DB, deps.Redis.Client, deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("PRIORITY_ROUTING_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
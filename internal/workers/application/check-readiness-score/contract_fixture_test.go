@@ -0,0 +1,28 @@
+// internal/workers/application/check-readiness-score/contract_fixture_test.go
+package checkreadinessscore
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+	"camunda-workers/internal/common/logger"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. The handler scores any decoded input; only a
+// missing one fails.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return NewHandler(&Config{}, logger.NewTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"READINESS_SCORE_FAILED": func(t *testing.T, ctx context.Context, _ *Input) error {
+				_, err := NewHandler(&Config{}, logger.NewTestLogger(t)).Execute(ctx, nil)
+				return err
+			},
+		},
+		Fallback: "READINESS_SCORE_FAILED",
+	}
+}
@@ -3,22 +3,22 @@ package checkreadinessscore
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"camunda-workers/internal/common/logger"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const (
 	TaskType = "check-readiness-score"
 )
 
+var (
+	ErrNilInput = errors.New("input cannot be nil")
+)
+
 type Handler struct {
 	logger logger.Logger
 }
@@ -29,31 +29,11 @@ func NewHandler(config *Config, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err))
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "READINESS_SCORE_FAILED", err.Error())
-		return
+func (h *Handler) execute(_ context.Context, input *Input) (*Output, error) {
+	if input == nil {
+		return nil, ErrNilInput
 	}
 
-	h.completeJob(client, job, output)
-}
-
-func (h *Handler) execute(_ context.Context, input *Input) (*Output, error) {
 	data := input.ApplicationData
 	if data == nil {
 		data = make(map[string]interface{})
@@ -323,43 +303,6 @@ func (h *Handler) clamp(value, min, max int) int {
 	return value
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
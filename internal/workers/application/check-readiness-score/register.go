@@ -0,0 +1,14 @@
+// internal/workers/application/check-readiness-score/register.go
+package checkreadinessscore
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(&Config{}, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("READINESS_SCORE_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -0,0 +1,53 @@
+// internal/workers/application/create-application-record/contract_fixture_test.go
+package createapplicationrecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	run := func(t *testing.T, ctx context.Context, input *Input, expect func(mock sqlmock.Sqlmock)) (*Output, error) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		expect(mock)
+		return NewHandler(createTestConfig(), db, newTestLogger(t)).Execute(ctx, input)
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return run(t, ctx, input, func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec(`INSERT INTO applications`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(`INSERT INTO audit_log`).WillReturnResult(sqlmock.NewResult(1, 1))
+			})
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"DATABASE_INSERT_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(mock sqlmock.Sqlmock) {
+					mock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+					mock.ExpectExec(`INSERT INTO applications`).WillReturnError(errors.New("connection reset"))
+				})
+				return err
+			},
+			"DUPLICATE_APPLICATION": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(mock sqlmock.Sqlmock) {
+					mock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				})
+				return err
+			},
+		},
+		Fallback: "UNKNOWN_ERROR",
+		Mappings: errorMappings,
+	}
+}
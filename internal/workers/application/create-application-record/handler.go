@@ -11,8 +11,6 @@ import (
 
 	"camunda-workers/internal/common/logger"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/google/uuid"
 )
 
@@ -37,39 +35,6 @@ func NewHandler(config *Config, db *sql.DB, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		errorCode := "UNKNOWN_ERROR"
-		retries := int32(0)
-		if errors.Is(err, ErrDatabaseInsertFailed) {
-			errorCode = "DATABASE_INSERT_FAILED"
-			retries = 3
-		} else if errors.Is(err, ErrDuplicateApplication) {
-			errorCode = "DUPLICATE_APPLICATION"
-			retries = 0
-		}
-		h.failJob(client, job, errorCode, err.Error(), retries)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	// Check for duplicate application
 	var exists bool
@@ -160,48 +125,6 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	}, nil
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	} else {
-		h.logger.Info("job completed successfully", map[string]interface{}{
-			"jobKey": job.Key,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, retries int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-		"retries":      retries,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
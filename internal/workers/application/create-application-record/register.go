@@ -0,0 +1,20 @@
+// internal/workers/application/create-application-record/register.go
+package createapplicationrecord
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrDatabaseInsertFailed, Code: "DATABASE_INSERT_FAILED", Retryable: true},
+	{Err: ErrDuplicateApplication, Code: "DUPLICATE_APPLICATION"},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(&Config{}, deps.Postgres.DB, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("UNKNOWN_ERROR", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
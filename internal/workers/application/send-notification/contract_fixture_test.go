@@ -0,0 +1,59 @@
+// internal/workers/application/send-notification/contract_fixture_test.go
+package sendnotification
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aws/aws-sdk-go-v2/service/ses"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	newHandler := func(t *testing.T, templates map[string]map[string]interface{}) *Handler {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		mock.ExpectQuery(`SELECT email, phone FROM`).
+			WillReturnRows(sqlmock.NewRows([]string{"email", "phone"}).AddRow("recipient@example.com", "+1234567890"))
+
+		return &Handler{
+			config: createTestConfig(),
+			db:     db,
+			logger: newTestLogger(t),
+			sesClient: &MockSESService{
+				SendEmailFunc: func(ctx context.Context, params *ses.SendEmailInput, optFns ...func(*ses.Options)) (*ses.SendEmailOutput, error) {
+					return &ses.SendEmailOutput{}, nil
+				},
+			},
+			snsClient: &MockSNSService{
+				PublishFunc: func(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+					return &sns.PublishOutput{}, nil
+				},
+			},
+			templateMap: templates,
+		}
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return newHandler(t, loadTestTemplates()).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"NOTIFICATION_SEND_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				// A template registry without the requested notification type.
+				_, err := newHandler(t, map[string]map[string]interface{}{}).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "NOTIFICATION_SEND_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -4,7 +4,6 @@ package sendnotification
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -17,8 +16,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ses"
 	"github.com/aws/aws-sdk-go-v2/service/ses/types"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/google/uuid"
 )
 
@@ -69,35 +66,6 @@ func NewHandler(config *Config, db *sql.DB, log logger.Logger) (*Handler, error)
 	}, nil
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		errorCode := "NOTIFICATION_SEND_FAILED"
-		retries := int32(0)
-		if errors.Is(err, ErrNotificationSendFailed) {
-			retries = 3
-		}
-		h.failJob(client, job, errorCode, err.Error(), retries)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	email, phone, err := h.getRecipientContact(input.RecipientID, input.RecipientType)
 	if err != nil {
@@ -221,43 +189,6 @@ func (h *Handler) sendSMS(ctx context.Context, to, message string) error {
 	return err
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 // Simplified template rendering with placeholder removal for missing values
 func renderTemplate(tmpl string, data map[string]interface{}) string {
 	result := tmpl
@@ -0,0 +1,28 @@
+// internal/workers/application/send-notification/register.go
+package sendnotification
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrNotificationSendFailed, Code: "NOTIFICATION_SEND_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler, err := NewHandler(
+			&Config{
+				Timeout: config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+			},
+			deps.Postgres.DB, deps.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("NOTIFICATION_SEND_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
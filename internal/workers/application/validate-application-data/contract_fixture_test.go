@@ -0,0 +1,31 @@
+// internal/workers/application/validate-application-data/contract_fixture_test.go
+package validateapplicationdata
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["franchiseId"] = "mcdonalds"
+			sample["applicationData"] = createValidApplicationData()
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return NewHandler(createTestConfig(), newTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"APPLICATION_VALIDATION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.ApplicationData = map[string]interface{}{}
+				_, err := NewHandler(createTestConfig(), newTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "APPLICATION_VALIDATION_FAILED",
+	}
+}
@@ -3,18 +3,13 @@ package validateapplicationdata
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"camunda-workers/internal/common/logger"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const (
@@ -35,44 +30,6 @@ func NewHandler(config *Config, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", err.Error())
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "APPLICATION_VALIDATION_FAILED", err.Error())
-		return
-	}
-
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to complete job", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) execute(_ context.Context, input *Input) (*Output, error) {
 	validated := make(map[string]interface{})
 	var validationErrors []ValidationError
@@ -384,20 +341,6 @@ func (h *Handler) parseInt(raw interface{}) (int, error) {
 	}
 }
 
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, _ = client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
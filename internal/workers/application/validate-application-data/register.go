@@ -0,0 +1,14 @@
+// internal/workers/application/validate-application-data/register.go
+package validateapplicationdata
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(&Config{}, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("APPLICATION_VALIDATION_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -9,11 +9,9 @@ import (
 	"camunda-workers/internal/common/config"
 	"camunda-workers/internal/common/errors"
 	"camunda-workers/internal/common/logger"
-	"camunda-workers/internal/common/metrics"
 	"camunda-workers/internal/common/validation"
 
 	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
 	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
@@ -25,6 +23,7 @@ type Handler struct {
 	camunda   *camunda.Client
 	service   *Service
 	jobWorker worker.JobWorker
+	handle    camunda.HandlerFunc
 }
 
 type HandlerOptions struct {
@@ -58,53 +57,44 @@ func NewHandler(opts HandlerOptions) (*Handler, error) {
 		Logger: loggerInstance,
 	}, handler.config)
 
+	handler.handle = camunda.NewPipeline(
+		ActivityID, config.GetWorkerConfig(opts.AppConfig, ActivityID), loggerInstance,
+	).Handler(handler.process)
+
 	return handler, nil
 }
 
+// Handle runs a job through the standard worker pipeline.
 func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	startTime := time.Now()
-	metrics.WorkerJobsActive.WithLabelValues(TaskType).Inc()
-	defer metrics.WorkerJobsActive.WithLabelValues(TaskType).Dec()
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	h.logger.Info("Processing auth logout request", map[string]interface{}{
-		"jobKey":             job.GetKey(),
-		"processInstanceKey": job.GetProcessInstanceKey(),
-		"worker":             TaskType,
-	})
+	h.handle(client, job)
+}
 
+// process parses the job variables, runs the service and sets the variables
+// the job is completed with.
+func (h *Handler) process(ctx context.Context, job *camunda.Job) error {
 	if !h.config.Enabled {
 		h.logger.Info("Worker disabled by configuration", map[string]interface{}{
 			"worker": TaskType,
 		})
-		h.completeJob(ctx, client, job, &Output{
+		job.Output = h.outputVariables(&Output{
 			Success: false,
 			Message: "Auth logout disabled",
 		})
-		return
+		return nil
 	}
 
-	input, err := h.parseInput(job)
+	input, err := h.parseInput(job.Job)
 	if err != nil {
-		errorCode := extractErrorCode(err)
-		metrics.WorkerJobsFailed.WithLabelValues(TaskType, errorCode).Inc()
-		h.failJob(ctx, client, job, err)
-		return
+		return err
 	}
 
 	output, err := h.Execute(ctx, input)
 	if err != nil {
-		errorCode := extractErrorCode(err)
-		metrics.WorkerJobsFailed.WithLabelValues(TaskType, errorCode).Inc()
-		h.failJob(ctx, client, job, err)
-		return
+		return convertToStandardError(err)
 	}
 
-	h.completeJob(ctx, client, job, output)
-	metrics.WorkerJobsCompleted.WithLabelValues(TaskType).Inc()
-	metrics.WorkerJobDuration.WithLabelValues(TaskType).Observe(time.Since(startTime).Seconds())
+	job.Output = h.outputVariables(output)
+	return nil
 }
 
 func (h *Handler) parseInput(job entities.Job) (*Input, error) {
@@ -159,7 +149,8 @@ func (h *Handler) parseInput(job entities.Job) (*Input, error) {
 	return input, nil
 }
 
-func (h *Handler) completeJob(ctx context.Context, client worker.JobClient, job entities.Job, output *Output) {
+// outputVariables maps the service output to the process variables.
+func (h *Handler) outputVariables(output *Output) map[string]interface{} {
 	variables := map[string]interface{}{
 		"logoutSuccess": output.Success,
 		"logoutMessage": output.Message,
@@ -173,78 +164,7 @@ func (h *Handler) completeJob(ctx context.Context, client worker.JobClient, job
 		variables["tokenRevoked"] = output.TokenRevoked
 	}
 
-	request, err := client.NewCompleteJobCommand().JobKey(job.GetKey()).VariablesFromMap(variables)
-	if err != nil {
-		h.logger.Error("Failed to create complete job command", map[string]interface{}{
-			"jobKey": job.GetKey(),
-			"error":  err.Error(),
-			"worker": TaskType,
-		})
-		return
-	}
-
-	_, err = request.Send(ctx)
-	if err != nil {
-		h.logger.Error("Failed to complete job", map[string]interface{}{
-			"jobKey": job.GetKey(),
-			"error":  err.Error(),
-			"worker": TaskType,
-		})
-	} else {
-		h.logger.Info("Successfully completed auth logout", map[string]interface{}{
-			"jobKey":              job.GetKey(),
-			"success":             output.Success,
-			"sessionsInvalidated": output.SessionsInvalidated,
-			"worker":              TaskType,
-		})
-	}
-}
-
-func (h *Handler) failJob(ctx context.Context, client worker.JobClient, job entities.Job, err error) {
-	stdErr := convertToStandardError(err)
-	bpmnErr := errors.ConvertToBPMNError(stdErr)
-
-	h.logger.Error("Auth logout job failed", map[string]interface{}{
-		"jobKey":       job.GetKey(),
-		"errorCode":    bpmnErr.Code,
-		"errorMessage": bpmnErr.Message,
-		"retryable":    bpmnErr.Retryable,
-		"retries":      bpmnErr.Retries,
-		"worker":       TaskType,
-	})
-
-	failCmd := client.NewFailJobCommand().
-		JobKey(job.GetKey()).
-		Retries(int32(bpmnErr.Retries)).
-		ErrorMessage(fmt.Sprintf("[%s] %s", bpmnErr.Code, bpmnErr.Message))
-
-	var finalCmd interface {
-		Send(context.Context) (*pb.FailJobResponse, error)
-	}
-	if len(bpmnErr.ErrorVariables) > 0 {
-		varCmd, varErr := failCmd.VariablesFromMap(bpmnErr.ToErrorVariables())
-		if varErr != nil {
-			h.logger.Error("Failed to set error variables, sending without them", map[string]interface{}{
-				"jobKey": job.GetKey(),
-				"error":  varErr.Error(),
-				"worker": TaskType,
-			})
-			finalCmd = failCmd
-		} else {
-			finalCmd = varCmd
-		}
-	} else {
-		finalCmd = failCmd
-	}
-
-	_, failErr := finalCmd.Send(ctx)
-	if failErr != nil {
-		h.logger.Error("Failed to send BPMN error to Camunda", map[string]interface{}{
-			"jobKey": job.GetKey(),
-			"error":  failErr.Error(),
-			"worker": TaskType,
-		})
-	}
+	return variables
 }
 
 func (h *Handler) Register() error {
@@ -0,0 +1,29 @@
+// internal/workers/auth/auth-signup-linkedin/register.go
+package authsignuplinkedin
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+// ActivityID is the activity-registry id this worker is registered and configured under.
+const ActivityID = "auth-signup-linkedin"
+
+func init() {
+	camunda.RegisterWorker(ActivityID, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler, err := NewHandler(HandlerOptions{
+			AppConfig: deps.Config,
+			Camunda:   deps.Camunda,
+			Logger:    deps.Logger,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return camunda.NewHandlerWorker(ActivityID, handler.Handle,
+			camunda.WithHealthCheck(handler.HealthCheck),
+			camunda.WithCloser(func() error {
+				handler.Close()
+				return nil
+			}),
+		), nil
+	})
+}
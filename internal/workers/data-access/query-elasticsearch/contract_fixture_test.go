@@ -0,0 +1,95 @@
+// internal/workers/data-access/query-elasticsearch/contract_fixture_test.go
+package queryelasticsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+
+	"camunda-workers/internal/common/contract"
+)
+
+const contractSearchResponse = `{"took": 3, "hits": {"total": {"value": 1}, "max_score": 1.5,
+	"hits": [{"_source": {"franchiseId": "franchise-001", "name": "Contract Franchise"}}]}}`
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			client := fakeElasticsearch(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(contractSearchResponse))
+			})
+			return NewHandler(createTestConfig(), client, createTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"ELASTICSEARCH_CONNECTION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				server := httptest.NewServer(http.NotFoundHandler())
+				server.Close()
+				client, err := elasticsearch.NewClient(elasticsearch.Config{
+					Addresses:    []string{server.URL},
+					DisableRetry: true,
+				})
+				if err != nil {
+					t.Fatalf("elasticsearch client: %v", err)
+				}
+				_, err = NewHandler(createTestConfig(), client, createTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+			"SEARCH_QUERY_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				client := fakeElasticsearch(t, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"error": {"type": "parsing_exception"}, "status": 400}`))
+				})
+				_, err := NewHandler(createTestConfig(), client, createTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+			"SEARCH_TIMEOUT": func(t *testing.T, ctx context.Context, input *Input) error {
+				release := make(chan struct{})
+				client := fakeElasticsearch(t, func(w http.ResponseWriter, r *http.Request) {
+					select {
+					case <-r.Context().Done():
+					case <-release:
+					}
+				})
+				t.Cleanup(func() { close(release) })
+
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				defer cancel()
+				_, err := NewHandler(createTestConfig(), client, createTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+			"INDEX_NOT_FOUND": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.IndexName = ""
+				_, err := NewHandler(createTestConfig(), nil, createTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "UNKNOWN_ERROR",
+		Mappings: errorMappings,
+	}
+}
+
+// fakeElasticsearch returns a client for a server that answers searches with
+// handler.
+func fakeElasticsearch(t *testing.T, handler http.HandlerFunc) *elasticsearch.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:    []string{server.URL},
+		DisableRetry: true,
+	})
+	if err != nil {
+		t.Fatalf("elasticsearch client: %v", err)
+	}
+	return client
+}
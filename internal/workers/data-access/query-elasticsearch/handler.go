@@ -2,12 +2,9 @@ package queryelasticsearch
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/elastic/go-elasticsearch/v8"
 
 	"camunda-workers/internal/common/logger"
@@ -39,32 +36,6 @@ func NewHandler(config *Config, client *elasticsearch.Client, log logger.Logger)
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		errorCode := h.mapErrorToCode(err)
-		retries := h.getRetryCount(err)
-		h.failJob(client, job, errorCode, err.Error(), retries)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	if input == nil {
 		return nil, errors.New("input cannot be nil")
@@ -94,6 +65,9 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 		if errors.Is(err, queries.ErrMissingIndex) {
 			return nil, ErrIndexNotFound
 		}
+		if errors.Is(err, queries.ErrUnreachable) {
+			return nil, fmt.Errorf("%w: %v", ErrElasticsearchConnectionFailed, err)
+		}
 		return nil, fmt.Errorf("%w: %v", ErrSearchQueryFailed, err)
 	}
 
@@ -105,66 +79,15 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	}, nil
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, retries int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-		"retries":      retries,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) mapErrorToCode(err error) string {
-	if errors.Is(err, ErrIndexNotFound) {
-		return "INDEX_NOT_FOUND"
-	} else if errors.Is(err, ErrSearchTimeout) {
-		return "SEARCH_TIMEOUT"
-	} else if errors.Is(err, ErrSearchQueryFailed) {
-		return "SEARCH_QUERY_FAILED"
-	} else if errors.Is(err, ErrElasticsearchConnectionFailed) {
-		return "ELASTICSEARCH_CONNECTION_FAILED"
+	for _, m := range errorMappings {
+		if errors.Is(err, m.Err) {
+			return string(m.Code)
+		}
 	}
 	return "UNKNOWN_ERROR"
 }
 
-func (h *Handler) getRetryCount(err error) int32 {
-	if errors.Is(err, ErrElasticsearchConnectionFailed) || errors.Is(err, ErrSearchQueryFailed) {
-		return 3
-	} else if errors.Is(err, ErrSearchTimeout) {
-		return 2
-	}
-	return 0
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
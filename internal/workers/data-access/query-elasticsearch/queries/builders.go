@@ -13,6 +13,7 @@ import (
 var (
 	ErrUnknownQueryType = errors.New("unknown query type")
 	ErrMissingIndex     = errors.New("index name is required")
+	ErrUnreachable      = errors.New("elasticsearch is unreachable")
 )
 
 // ElasticsearchQuery defines the structure of a query request
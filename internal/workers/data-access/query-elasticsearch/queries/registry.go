@@ -54,7 +54,7 @@ func Execute(ctx context.Context, esClient *elasticsearch.Client, input map[stri
 	start := time.Now()
 	res, err := req.Do(ctx, esClient)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrUnreachable, err)
 	}
 	defer res.Body.Close()
 
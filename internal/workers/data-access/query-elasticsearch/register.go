@@ -0,0 +1,28 @@
+// internal/workers/data-access/query-elasticsearch/register.go
+package queryelasticsearch
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrIndexNotFound, Code: "INDEX_NOT_FOUND"},
+	{Err: ErrSearchTimeout, Code: "SEARCH_TIMEOUT", Retryable: true},
+	{Err: ErrSearchQueryFailed, Code: "SEARCH_QUERY_FAILED", Retryable: true},
+	{Err: ErrElasticsearchConnectionFailed, Code: "ELASTICSEARCH_CONNECTION_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				Timeout: config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+			},
+			deps.Elasticsearch.Client, deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("UNKNOWN_ERROR", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
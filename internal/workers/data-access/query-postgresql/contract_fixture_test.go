@@ -0,0 +1,77 @@
+// internal/workers/data-access/query-postgresql/contract_fixture_test.go
+package querypostgresql
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"camunda-workers/internal/common/contract"
+)
+
+const fullDetailsQuery = `SELECT id, name, description, investment_min, investment_max, category, locations, is_verified, created_at, updated_at FROM franchises WHERE id = \$1`
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	run := func(t *testing.T, ctx context.Context, input *Input, expect func(q *sqlmock.ExpectedQuery)) (*Output, error) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		expect(mock.ExpectQuery(fullDetailsQuery))
+		return NewHandler(createTestConfig(), db, createTestLogger(t)).Execute(ctx, input)
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["franchiseId"] = "franchise-123"
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+				q.WillReturnRows(sqlmock.NewRows([]string{
+					"id", "name", "description", "investment_min", "investment_max",
+					"category", "locations", "is_verified", "created_at", "updated_at",
+				}).AddRow(
+					"franchise-123", "Starbucks", "Coffee shop franchise",
+					300000, 600000, "food", "US,CA", true,
+					"2023-01-01", "2023-12-01",
+				))
+			})
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"DATABASE_CONNECTION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillReturnError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+				})
+				return err
+			},
+			"QUERY_EXECUTION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillReturnError(errors.New(`pq: relation "franchises" does not exist`))
+				})
+				return err
+			},
+			"QUERY_TIMEOUT": func(t *testing.T, ctx context.Context, input *Input) error {
+				ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+				defer cancel()
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillDelayFor(200 * time.Millisecond).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				})
+				return err
+			},
+			"INVALID_QUERY_TYPE": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.QueryType = "franchise_everything"
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {})
+				return err
+			},
+		},
+		Fallback: "QUERY_EXECUTION_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -3,12 +3,10 @@ package querypostgresql
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"database/sql/driver"
 	"errors"
 	"fmt"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"net"
 
 	"camunda-workers/internal/common/logger"
 	"camunda-workers/internal/models"
@@ -40,39 +38,6 @@ func NewHandler(config *Config, db *sql.DB, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		errorCode := "QUERY_EXECUTION_FAILED"
-		retries := int32(0)
-		if errors.Is(err, ErrQueryTimeout) {
-			errorCode = "QUERY_TIMEOUT"
-			retries = 2
-		} else if errors.Is(err, ErrInvalidQueryType) {
-			errorCode = "INVALID_QUERY_TYPE"
-			retries = 0
-		}
-		h.failJob(client, job, errorCode, err.Error(), retries)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	if input == nil {
 		return nil, fmt.Errorf("input cannot be nil")
@@ -102,6 +67,9 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, ErrQueryTimeout
 		}
+		if connectionLost(err) {
+			return nil, fmt.Errorf("%w: %v", ErrDatabaseConnectionFailed, err)
+		}
 		return nil, fmt.Errorf("%w: %v", ErrQueryExecutionFailed, err)
 	}
 
@@ -112,48 +80,17 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	}, nil
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, retries int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-		"retries":      retries,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
 
+// connectionLost reports whether err means the database could not be reached
+// rather than that the query itself failed.
+func connectionLost(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.As(err, &netErr)
+}
+
 // package querypostgresql
 
 // import (
@@ -0,0 +1,27 @@
+// internal/workers/data-access/query-postgresql/register.go
+package querypostgresql
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrQueryTimeout, Code: "QUERY_TIMEOUT", Retryable: true},
+	{Err: ErrInvalidQueryType, Code: "INVALID_QUERY_TYPE"},
+	{Err: ErrDatabaseConnectionFailed, Code: "DATABASE_CONNECTION_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				Timeout: config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+			},
+			deps.Postgres.DB, deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("QUERY_EXECUTION_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
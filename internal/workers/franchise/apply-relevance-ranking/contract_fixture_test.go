@@ -0,0 +1,36 @@
+// internal/workers/franchise/apply-relevance-ranking/contract_fixture_test.go
+package applyrelevanceranking
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Ranking works on any decoded input; only a
+// missing one fails.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			// One hit with details, so that a franchise is ranked.
+			sample["searchResults"] = []interface{}{
+				map[string]interface{}{"id": "franchise-1", "score": 8.5},
+			}
+			sample["detailsData"] = []interface{}{
+				map[string]interface{}{"id": "franchise-1", "name": "McDonald's", "category": "Fast Food"},
+			}
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return NewHandler(createTestConfig(), newTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"RANKING_FAILED": func(t *testing.T, ctx context.Context, _ *Input) error {
+				_, err := NewHandler(createTestConfig(), newTestLogger(t)).Execute(ctx, nil)
+				return err
+			},
+		},
+		Fallback: "RANKING_FAILED",
+	}
+}
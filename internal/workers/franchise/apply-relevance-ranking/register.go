@@ -0,0 +1,21 @@
+// internal/workers/franchise/apply-relevance-ranking/register.go
+package applyrelevanceranking
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				MaxItems: 100,
+				Timeout:  config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+			},
+			deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("RANKING_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -0,0 +1,38 @@
+// internal/workers/franchise/calculate-match-score/contract_fixture_test.go
+package calculatematchscore
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Without a profile the score falls back to a
+// neutral 50; only a job cancelled during the profile lookup fails.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			// The profile lookup of the error case is keyed by userId.
+			sample["userId"] = "user-123"
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			db, _ := setupMockDB(t)
+			t.Cleanup(func() { db.Close() })
+			return NewHandler(createTestConfig(), db, setupMockRedis(), newTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"MATCH_SCORE_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				db, _ := setupMockDB(t)
+				t.Cleanup(func() { db.Close() })
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				input.UserProfile = nil
+				_, err := NewHandler(createTestConfig(), db, setupMockRedis(), newTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "MATCH_SCORE_FAILED",
+	}
+}
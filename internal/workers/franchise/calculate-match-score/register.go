@@ -0,0 +1,21 @@
+// internal/workers/franchise/calculate-match-score/register.go
+package calculatematchscore
+
+import (
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				CacheTTL: 10 * time.Minute,
+			},
+			deps.Postgres.DB, deps.Redis.Client, deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("MATCH_SCORE_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
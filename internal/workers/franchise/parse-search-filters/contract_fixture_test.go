@@ -0,0 +1,33 @@
+// internal/workers/franchise/parse-search-filters/contract_fixture_test.go
+package parsesearchfilters
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["rawFilters"] = map[string]interface{}{
+				"keywords": "coffee",
+				"sortBy":   "name",
+			}
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return createTestHandler(t).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"INVALID_FILTER_FORMAT": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.RawFilters = map[string]interface{}{"sortBy": "popularity"}
+				_, err := createTestHandler(t).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "INVALID_FILTER_FORMAT",
+	}
+}
@@ -3,18 +3,13 @@ package parsesearchfilters
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"camunda-workers/internal/common/logger"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const TaskType = "parse-search-filters"
@@ -44,30 +39,6 @@ func NewHandler(config *Config, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "INVALID_FILTER_FORMAT", err.Error(), 0)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) execute(_ context.Context, input *Input) (*Output, error) {
 	if input.RawFilters == nil {
 		input.RawFilters = make(map[string]interface{})
@@ -180,12 +151,12 @@ func (h *Handler) execute(_ context.Context, input *Input) (*Output, error) {
 	}
 
 	h.logger.Info("filters parsed successfully", map[string]interface{}{
-		"categories":     parsed.Categories,
+		"categories":      parsed.Categories,
 		"investmentRange": parsed.InvestmentRange,
-		"locations":      parsed.Locations,
-		"keywords":       parsed.Keywords,
-		"sortBy":         parsed.SortBy,
-		"pagination":     parsed.Pagination,
+		"locations":       parsed.Locations,
+		"keywords":        parsed.Keywords,
+		"sortBy":          parsed.SortBy,
+		"pagination":      parsed.Pagination,
 	})
 
 	return &Output{ParsedFilters: parsed}, nil
@@ -299,43 +270,6 @@ func (h *Handler) parseInt(raw interface{}) (int, error) {
 	}
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err,
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err,
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
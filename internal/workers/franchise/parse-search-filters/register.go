@@ -0,0 +1,14 @@
+// internal/workers/franchise/parse-search-filters/register.go
+package parsesearchfilters
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(&Config{}, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("INVALID_FILTER_FORMAT"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -0,0 +1,16 @@
+// internal/workers/infrastructure/audit-log/config.go
+package auditlog
+
+import "time"
+
+type Config struct {
+	DefaultAction string
+	Timeout       time.Duration
+}
+
+func LoadConfig() *Config {
+	return &Config{
+		DefaultAction: "WORKFLOW_EVENT",
+		Timeout:       5 * time.Second,
+	}
+}
@@ -0,0 +1,35 @@
+// internal/workers/infrastructure/audit-log/contract_fixture_test.go
+package auditlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+	"camunda-workers/internal/common/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newContractFixture wires the handler to a mocked database for the
+// generated contract tests.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			db, mock := setupMockDB(t)
+			mock.ExpectQuery(insertQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			return NewHandler(LoadConfig(), db, logger.NewTestLogger(t)).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"AUDIT_LOG_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				db, mock := setupMockDB(t)
+				mock.ExpectQuery(insertQuery).WillReturnError(errors.New("connection refused"))
+				_, err := NewHandler(LoadConfig(), db, logger.NewTestLogger(t)).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "AUDIT_LOG_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -0,0 +1,99 @@
+// internal/workers/infrastructure/audit-log/handler.go
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/logger"
+)
+
+const (
+	TaskType = "audit-log"
+)
+
+var (
+	ErrAuditWriteFailed = stderrors.New("AUDIT_LOG_FAILED")
+)
+
+type Handler struct {
+	config *Config
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewHandler(config *Config, db *sql.DB, log logger.Logger) *Handler {
+	return &Handler{
+		config: config,
+		db:     db,
+		logger: log.WithFields(map[string]interface{}{"taskType": TaskType}),
+	}
+}
+
+// process decodes the job variables and records them together with the
+// process instance the entry came from.
+func (h *Handler) process(ctx context.Context, job *camunda.Job) error {
+	input := new(Input)
+	if err := json.Unmarshal([]byte(job.Variables), input); err != nil {
+		return errors.NewInputParsingFailedError(err)
+	}
+	input.ProcessInstanceKey = job.ProcessInstanceKey
+	input.BpmnProcessId = job.BpmnProcessId
+	input.ElementId = job.ElementId
+
+	output, err := h.Execute(ctx, input)
+	if err != nil {
+		return err
+	}
+	job.Output = output
+	return nil
+}
+
+func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
+	action := input.Action
+	if action == "" {
+		action = h.config.DefaultAction
+	}
+
+	resourceType, resourceId := ResourceTypeUser, input.UserId
+	if resourceId == "" {
+		resourceType, resourceId = ResourceTypeProcessInstance, strconv.FormatInt(input.ProcessInstanceKey, 10)
+	}
+
+	details := make(map[string]interface{}, len(input.Details)+4)
+	for k, v := range input.Details {
+		details[k] = v
+	}
+	if input.Timestamp != "" {
+		details["timestamp"] = input.Timestamp
+	}
+	details["processInstanceKey"] = input.ProcessInstanceKey
+	details["bpmnProcessId"] = input.BpmnProcessId
+	details["elementId"] = input.ElementId
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("%w: marshal details: %v", ErrAuditWriteFailed, err)
+	}
+
+	var id int64
+	query := `INSERT INTO audit_log (event_type, resource_type, resource_id, details) VALUES ($1, $2, $3, $4) RETURNING id`
+	if err := h.db.QueryRowContext(ctx, query, action, resourceType, resourceId, detailsJSON).Scan(&id); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrAuditWriteFailed, err)
+	}
+
+	h.logger.Info("audit entry recorded", map[string]interface{}{
+		"auditLogId":   id,
+		"action":       action,
+		"resourceType": resourceType,
+		"resourceId":   resourceId,
+	})
+
+	return &Output{AuditLogged: true, AuditLogId: id}, nil
+}
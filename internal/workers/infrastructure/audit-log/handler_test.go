@@ -0,0 +1,128 @@
+// internal/workers/infrastructure/audit-log/handler_test.go
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const insertQuery = `INSERT INTO audit_log \(event_type, resource_type, resource_id, details\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id`
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+// detailsMatcher checks that the details argument is JSON containing the expected keys.
+type detailsMatcher map[string]interface{}
+
+func (m detailsMatcher) Match(v driver.Value) bool {
+	raw, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return false
+	}
+	for k, want := range m {
+		if details[k] != want {
+			return false
+		}
+	}
+	return true
+}
+
+// ==========================
+// Core Functionality Tests
+// ==========================
+
+func TestInput_UnmarshalJSON(t *testing.T) {
+	var input Input
+	err := json.Unmarshal([]byte(`{"action":"USER_LOGOUT","userId":"user-1","timestamp":"2024-01-01T00:00:00Z","sessionId":"s-1","emailSent":true}`), &input)
+
+	require.NoError(t, err)
+	assert.Equal(t, "USER_LOGOUT", input.Action)
+	assert.Equal(t, "user-1", input.UserId)
+	assert.Equal(t, "2024-01-01T00:00:00Z", input.Timestamp)
+	assert.Equal(t, map[string]interface{}{"sessionId": "s-1", "emailSent": true}, input.Details)
+}
+
+func TestHandler_Execute_UserEntry(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery(insertQuery).
+		WithArgs("USER_LOGOUT", ResourceTypeUser, "user-1", detailsMatcher{"sessionId": "s-1", "timestamp": "2024-01-01T00:00:00Z"}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(42)))
+
+	handler := NewHandler(LoadConfig(), db, logger.NewTestLogger(t))
+	output, err := handler.Execute(context.Background(), &Input{
+		Action:    "USER_LOGOUT",
+		UserId:    "user-1",
+		Timestamp: "2024-01-01T00:00:00Z",
+		Details:   map[string]interface{}{"sessionId": "s-1"},
+	})
+
+	require.NoError(t, err)
+	assert.True(t, output.AuditLogged)
+	assert.Equal(t, int64(42), output.AuditLogId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandler_Process_ProcessInstanceEntry(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery(insertQuery).
+		WithArgs("WORKFLOW_EVENT", ResourceTypeProcessInstance, "2251799813685249", detailsMatcher{"bpmnProcessId": "Process_ErrorHandling"}).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	job := &camunda.Job{
+		Job: entities.Job{ActivatedJob: &pb.ActivatedJob{
+			Type:               "audit.log.create",
+			ProcessInstanceKey: 2251799813685249,
+			BpmnProcessId:      "Process_ErrorHandling",
+			ElementId:          "Task_AuditLog",
+			Variables:          `{"errorCode":"NETWORK_ERROR"}`,
+		}},
+		TaskType: TaskType,
+	}
+
+	handler := NewHandler(LoadConfig(), db, logger.NewTestLogger(t))
+	err := handler.process(context.Background(), job)
+
+	require.NoError(t, err)
+	assert.Equal(t, &Output{AuditLogged: true, AuditLogId: 7}, job.Output)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// ==========================
+// Error Handling Tests
+// ==========================
+
+func TestHandler_Execute_DatabaseError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	mock.ExpectQuery(insertQuery).WillReturnError(errors.New("connection refused"))
+
+	handler := NewHandler(LoadConfig(), db, logger.NewTestLogger(t))
+	output, err := handler.Execute(context.Background(), &Input{Action: "PROFILE_UPDATE", UserId: "user-1"})
+
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, ErrAuditWriteFailed)
+}
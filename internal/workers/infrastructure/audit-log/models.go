@@ -0,0 +1,46 @@
+// internal/workers/infrastructure/audit-log/models.go
+package auditlog
+
+import "encoding/json"
+
+// Input is the audit entry requested by the process. action, userId and
+// timestamp are well-known; every other variable is kept as details.
+type Input struct {
+	Action    string
+	UserId    string
+	Timestamp string
+	Details   map[string]interface{}
+
+	// Set from the activated job, not from variables.
+	ProcessInstanceKey int64
+	BpmnProcessId      string
+	ElementId          string
+}
+
+func (in *Input) UnmarshalJSON(data []byte) error {
+	var vars map[string]interface{}
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return err
+	}
+
+	in.Action, _ = vars["action"].(string)
+	in.UserId, _ = vars["userId"].(string)
+	in.Timestamp, _ = vars["timestamp"].(string)
+	delete(vars, "action")
+	delete(vars, "userId")
+	delete(vars, "timestamp")
+	in.Details = vars
+	return nil
+}
+
+type Output struct {
+	AuditLogged bool  `json:"auditLogged"`
+	AuditLogId  int64 `json:"auditLogId"`
+}
+
+// ResourceTypeUser is recorded for entries about a user; other entries are
+// recorded against the process instance.
+const (
+	ResourceTypeUser            = "user"
+	ResourceTypeProcessInstance = "process_instance"
+)
@@ -0,0 +1,19 @@
+// internal/workers/infrastructure/audit-log/register.go
+package auditlog
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrAuditWriteFailed, Code: "AUDIT_LOG_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(LoadConfig(), deps.Postgres.DB, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("AUDIT_LOG_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(handler.process)), nil
+	})
+}
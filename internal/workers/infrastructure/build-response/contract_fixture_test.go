@@ -0,0 +1,61 @@
+// internal/workers/infrastructure/build-response/contract_fixture_test.go
+package buildresponse
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	registry := filepath.Join(t.TempDir(), "templates.json")
+	err := os.WriteFile(registry, []byte(createTemplateRegistry([]TemplateDefinition{{
+		ID:   "contract-template",
+		Type: "franchise-detail",
+		Schema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+			"required":   []string{"name"},
+		},
+		Template: map[string]interface{}{"franchise": map[string]interface{}{"name": "{{name}}"}},
+		Version:  "1.0",
+	}})), 0o644)
+	if err != nil {
+		t.Fatalf("write template registry: %v", err)
+	}
+
+	newHandler := func(t *testing.T) *Handler {
+		config := createTestConfig()
+		config.TemplateRegistry = registry
+		return createTestHandler(t, config)
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["templateId"] = "contract-template"
+			sample["data"] = map[string]interface{}{"name": "McDonald's"}
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return newHandler(t).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"TEMPLATE_NOT_FOUND": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.TemplateId = "unknown-template"
+				_, err := newHandler(t).Execute(ctx, input)
+				return err
+			},
+			"TEMPLATE_VALIDATION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.Data = map[string]interface{}{}
+				_, err := newHandler(t).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "RESPONSE_BUILD_ERROR",
+		Mappings: errorMappings,
+	}
+}
@@ -12,8 +12,6 @@ import (
 
 	"camunda-workers/internal/common/logger"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/xeipuuv/gojsonschema"
 )
 
@@ -44,37 +42,6 @@ func NewHandler(config *Config, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job",
-		map[string]interface{}{
-			"jobKey":      job.Key,
-			"workflowKey": job.ProcessInstanceKey,
-		})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.Execute(ctx, &input) // Changed to Execute
-	if err != nil {
-		errorCode := "RESPONSE_BUILD_ERROR"
-		if errors.Is(err, ErrTemplateNotFound) {
-			errorCode = "TEMPLATE_NOT_FOUND"
-		} else if errors.Is(err, ErrTemplateValidationFailed) {
-			errorCode = "TEMPLATE_VALIDATION_FAILED"
-		}
-		h.failJob(client, job, errorCode, err.Error(), 0)
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	template, err := h.loadTemplate(input.TemplateId)
 	if err != nil {
@@ -264,38 +231,6 @@ func (h *Handler) deepMerge(dst, src map[string]interface{}) map[string]interfac
 	return result
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{"error": err})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{"error": err})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed",
-		map[string]interface{}{
-			"jobKey":       job.Key,
-			"errorCode":    errorCode,
-			"errorMessage": errorMessage,
-		})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{"error": err})
-	}
-}
-
 // // internal/workers/infrastructure/build-response/handler.go
 // package buildresponse
 
@@ -0,0 +1,26 @@
+// internal/workers/infrastructure/build-response/register.go
+package buildresponse
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrTemplateNotFound, Code: "TEMPLATE_NOT_FOUND"},
+	{Err: ErrTemplateValidationFailed, Code: "TEMPLATE_VALIDATION_FAILED"},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				TemplateRegistry: deps.Config.Template.RegistryPath,
+				AppVersion:       deps.Config.App.Version,
+			},
+			deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("RESPONSE_BUILD_ERROR", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -0,0 +1,35 @@
+// internal/workers/infrastructure/callback-to-bff/config.go
+package callbacktobff
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type Config struct {
+	CallbackURL string
+	APIKey      string
+	Timeout     time.Duration
+}
+
+func LoadConfig() *Config {
+	return &Config{
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Validate rejects a config the handler could not post callbacks with.
+func (c *Config) Validate() error {
+	if c.CallbackURL == "" {
+		return fmt.Errorf("apis.bff.callback_url is required")
+	}
+	u, err := url.Parse(c.CallbackURL)
+	if err != nil {
+		return fmt.Errorf("apis.bff.callback_url: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("apis.bff.callback_url %q must be an absolute http(s) URL", c.CallbackURL)
+	}
+	return nil
+}
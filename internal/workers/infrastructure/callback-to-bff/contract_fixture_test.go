@@ -0,0 +1,49 @@
+// internal/workers/infrastructure/callback-to-bff/contract_fixture_test.go
+package callbacktobff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+	"camunda-workers/internal/common/logger"
+)
+
+// newContractFixture wires the handler to a fake BFF for the generated
+// contract tests.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	run := func(t *testing.T, ctx context.Context, input *Input, status int) (*Output, error) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+
+		handler := NewHandler(&Config{CallbackURL: server.URL, APIKey: "test-key"}, logger.NewTestLogger(t))
+		return handler.Execute(ctx, input)
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return run(t, ctx, input, http.StatusOK)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"BFF_CALLBACK_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, http.StatusServiceUnavailable)
+				return err
+			},
+			"BFF_CALLBACK_REJECTED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, http.StatusBadRequest)
+				return err
+			},
+			"VALIDATION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				input.Response = nil
+				_, err := run(t, ctx, input, http.StatusOK)
+				return err
+			},
+		},
+		Fallback: "BFF_CALLBACK_FAILED",
+		Mappings: errorMappings,
+	}
+}
@@ -0,0 +1,95 @@
+// internal/workers/infrastructure/callback-to-bff/handler.go
+package callbacktobff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"camunda-workers/internal/common/logger"
+)
+
+const (
+	TaskType = "callback-to-bff"
+)
+
+var (
+	ErrCallbackFailed   = errors.New("BFF_CALLBACK_FAILED")
+	ErrCallbackRejected = errors.New("BFF_CALLBACK_REJECTED")
+	ErrInvalidInput     = errors.New("INVALID_INPUT")
+)
+
+type Handler struct {
+	config     *Config
+	httpClient *http.Client
+	logger     logger.Logger
+}
+
+func NewHandler(config *Config, log logger.Logger) *Handler {
+	return &Handler{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		logger:     log.WithFields(map[string]interface{}{"taskType": TaskType}),
+	}
+}
+
+// Execute POSTs the built response to the BFF so it can answer the waiting
+// client. 5xx responses and transport errors are retryable; other non-2xx
+// responses mean the BFF rejected the payload and retrying won't help.
+func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
+	requestId := input.RequestId
+	if requestId == "" {
+		requestId, _ = input.Response["requestId"].(string)
+	}
+	if requestId == "" || input.Response == nil {
+		return nil, fmt.Errorf("%w: requestId and response are required", ErrInvalidInput)
+	}
+
+	body, err := json.Marshal(CallbackPayload{
+		RequestId: requestId,
+		Response:  input.Response,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: marshal payload: %v", ErrInvalidInput, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.CallbackURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: build request: %v", ErrCallbackRejected, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-Id", requestId)
+	if h.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+h.config.APIKey)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCallbackFailed, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: BFF returned %d", ErrCallbackFailed, resp.StatusCode)
+	case resp.StatusCode >= 300:
+		return nil, fmt.Errorf("%w: BFF returned %d", ErrCallbackRejected, resp.StatusCode)
+	}
+
+	h.logger.Info("response delivered to BFF", map[string]interface{}{
+		"requestId":  requestId,
+		"statusCode": resp.StatusCode,
+	})
+
+	return &Output{
+		CallbackDelivered:   true,
+		CallbackStatusCode:  resp.StatusCode,
+		CallbackDeliveredAt: time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,179 @@
+// internal/workers/infrastructure/callback-to-bff/handler_test.go
+package callbacktobff
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+func createTestHandler(t *testing.T, url string) *Handler {
+	return NewHandler(&Config{
+		CallbackURL: url,
+		APIKey:      "test-key",
+		Timeout:     2 * time.Second,
+	}, logger.NewTestLogger(t))
+}
+
+func createTestInput(requestId string) *Input {
+	return &Input{
+		RequestId: requestId,
+		Response: map[string]interface{}{
+			"requestId": requestId,
+			"status":    "success",
+			"data":      map[string]interface{}{"answer": "42"},
+		},
+	}
+}
+
+// ==========================
+// Core Functionality Tests
+// ==========================
+
+func TestHandler_Execute_Success(t *testing.T) {
+	var received CallbackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "req-123", r.Header.Get("X-Request-Id"))
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	handler := createTestHandler(t, server.URL)
+	output, err := handler.Execute(context.Background(), createTestInput("req-123"))
+
+	require.NoError(t, err)
+	assert.True(t, output.CallbackDelivered)
+	assert.Equal(t, http.StatusAccepted, output.CallbackStatusCode)
+	assert.NotEmpty(t, output.CallbackDeliveredAt)
+	assert.Equal(t, "req-123", received.RequestId)
+	assert.Equal(t, "success", received.Response["status"])
+}
+
+func TestHandler_Execute_RequestIdFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-from-response", r.Header.Get("X-Request-Id"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	input := createTestInput("req-from-response")
+	input.RequestId = ""
+
+	output, err := createTestHandler(t, server.URL).Execute(context.Background(), input)
+
+	require.NoError(t, err)
+	assert.True(t, output.CallbackDelivered)
+}
+
+// ==========================
+// Error Handling Tests
+// ==========================
+
+func TestHandler_Execute_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		expectedErr error
+	}{
+		{name: "server error is retryable", statusCode: http.StatusBadGateway, expectedErr: ErrCallbackFailed},
+		{name: "rate limited is retryable", statusCode: http.StatusTooManyRequests, expectedErr: ErrCallbackFailed},
+		{name: "bad request is rejected", statusCode: http.StatusBadRequest, expectedErr: ErrCallbackRejected},
+		{name: "not found is rejected", statusCode: http.StatusNotFound, expectedErr: ErrCallbackRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			output, err := createTestHandler(t, server.URL).Execute(context.Background(), createTestInput("req-123"))
+
+			assert.Nil(t, output)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestHandler_Execute_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	_, err := createTestHandler(t, url).Execute(context.Background(), createTestInput("req-123"))
+
+	assert.ErrorIs(t, err, ErrCallbackFailed)
+}
+
+func TestHandler_Execute_InvalidInput(t *testing.T) {
+	handler := createTestHandler(t, "http://localhost:0")
+
+	_, err := handler.Execute(context.Background(), &Input{})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	_, err = handler.Execute(context.Background(), &Input{RequestId: "req-123"})
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
+
+// ==========================
+// Configuration Tests
+// ==========================
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		callbackURL string
+		expectErr   bool
+	}{
+		{name: "http URL", callbackURL: "http://bff:3000/internal/workflow-callback"},
+		{name: "https URL", callbackURL: "https://bff.example.com/callback"},
+		{name: "empty", callbackURL: "", expectErr: true},
+		{name: "relative path", callbackURL: "/internal/workflow-callback", expectErr: true},
+		{name: "host without scheme", callbackURL: "bff:3000/internal/workflow-callback", expectErr: true},
+		{name: "scheme without host", callbackURL: "http:///callback", expectErr: true},
+		{name: "unsupported scheme", callbackURL: "ftp://bff/callback", expectErr: true},
+		{name: "unparseable", callbackURL: "http://bff:port/callback", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&Config{CallbackURL: tt.callbackURL}).Validate()
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRegister_RequiresCallbackURL(t *testing.T) {
+	deps := &camunda.Dependencies{Config: &config.Config{}, Logger: logger.NewTestLogger(t)}
+
+	_, err := camunda.BuildWorker(TaskType, deps)
+	assert.ErrorContains(t, err, "callback_url")
+
+	deps.Config.APIs.BFF.CallbackURL = "http://bff:3000/internal/workflow-callback"
+	w, err := camunda.BuildWorker(TaskType, deps)
+	require.NoError(t, err)
+	assert.Equal(t, TaskType, w.TaskType())
+}
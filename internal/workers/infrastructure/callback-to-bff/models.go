@@ -0,0 +1,20 @@
+// internal/workers/infrastructure/callback-to-bff/models.go
+package callbacktobff
+
+// Input is the payload produced by build-response.
+type Input struct {
+	RequestId string                 `json:"requestId"`
+	Response  map[string]interface{} `json:"response"`
+}
+
+type Output struct {
+	CallbackDelivered   bool   `json:"callbackDelivered"`
+	CallbackStatusCode  int    `json:"callbackStatusCode"`
+	CallbackDeliveredAt string `json:"callbackDeliveredAt"`
+}
+
+// CallbackPayload is the body POSTed to the BFF.
+type CallbackPayload struct {
+	RequestId string                 `json:"requestId"`
+	Response  map[string]interface{} `json:"response"`
+}
@@ -0,0 +1,31 @@
+// internal/workers/infrastructure/callback-to-bff/register.go
+package callbacktobff
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrCallbackFailed, Code: "BFF_CALLBACK_FAILED", Retryable: true},
+	{Err: ErrCallbackRejected, Code: "BFF_CALLBACK_REJECTED"},
+	{Err: ErrInvalidInput, Code: "VALIDATION_FAILED"},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		cfg := &Config{
+			CallbackURL: deps.Config.APIs.BFF.CallbackURL,
+			APIKey:      deps.Config.APIs.BFF.APIKey,
+			Timeout:     config.GetDuration(deps.Config.APIs.BFF.Timeout),
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+
+		handler := NewHandler(cfg, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("BFF_CALLBACK_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -0,0 +1,16 @@
+// internal/workers/infrastructure/error-handler/config.go
+package errorhandler
+
+import "time"
+
+type Config struct {
+	DefaultUserMessage string
+	Timeout            time.Duration
+}
+
+func LoadConfig() *Config {
+	return &Config{
+		DefaultUserMessage: "Something went wrong. Please try again later.",
+		Timeout:            5 * time.Second,
+	}
+}
@@ -0,0 +1,21 @@
+// internal/workers/infrastructure/error-handler/contract_fixture_test.go
+package errorhandler
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+	"camunda-workers/internal/common/logger"
+)
+
+// newContractFixture runs the handler for the generated contract tests. The
+// error handler has no dependencies and never fails.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return NewHandler(LoadConfig(), logger.NewTestLogger(t)).Execute(ctx, TaskType+".validation", input)
+		},
+		Fallback: "ERROR_HANDLING_FAILED",
+	}
+}
@@ -0,0 +1,106 @@
+// internal/workers/infrastructure/error-handler/handler.go
+package errorhandler
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/errors"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/metrics"
+)
+
+const (
+	TaskType = "error-handler"
+)
+
+type Handler struct {
+	config *Config
+	logger logger.Logger
+}
+
+func NewHandler(config *Config, log logger.Logger) *Handler {
+	return &Handler{
+		config: config,
+		logger: log.WithFields(map[string]interface{}{"taskType": TaskType}),
+	}
+}
+
+// process decodes the job variables and handles the error for the job's
+// actual type. The worker is subscribed to the error-handler.* and
+// error.handler.* variants through registry aliases, and the job type tells
+// which one was activated.
+func (h *Handler) process(ctx context.Context, job *camunda.Job) error {
+	input := new(Input)
+	if err := json.Unmarshal([]byte(job.Variables), input); err != nil {
+		return errors.NewInputParsingFailedError(err)
+	}
+
+	output, err := h.Execute(ctx, job.Type, input)
+	if err != nil {
+		return err
+	}
+	job.Output = output
+	return nil
+}
+
+// Execute records an error caught by a boundary event and returns the
+// variables the process uses to end or report the failed path. It never
+// fails: an error handler that errors itself would leave the instance stuck.
+func (h *Handler) Execute(ctx context.Context, jobType string, input *Input) (*Output, error) {
+	source := errorSource(jobType)
+
+	code := input.ErrorCode
+	if code == "" && source == SourceTimeout {
+		code = "TIMEOUT_ERROR"
+	}
+	if code == "" {
+		code = "UNKNOWN_ERROR"
+	}
+
+	category := errors.GetErrorCategory(errors.ErrorCode(code))
+	if input.OriginalErrorCode != "" {
+		category = errors.GetErrorCategory(errors.ErrorCode(input.OriginalErrorCode))
+	}
+
+	userMessage, ok := userMessages[source]
+	if !ok {
+		userMessage = h.config.DefaultUserMessage
+	}
+
+	metrics.WorkflowErrorsHandled.WithLabelValues(source, code).Inc()
+	h.logger.Warn("workflow error handled", map[string]interface{}{
+		"jobType":           jobType,
+		"errorSource":       source,
+		"errorCode":         code,
+		"originalErrorCode": input.OriginalErrorCode,
+		"errorCategory":     category,
+		"errorMessage":      input.ErrorMessage,
+		"errorDetails":      input.ErrorDetails,
+		"userId":            input.UserId,
+		"requestId":         input.RequestId,
+	})
+
+	return &Output{
+		ErrorHandled:  true,
+		ErrorSource:   source,
+		ErrorCategory: category,
+		ErrorCode:     code,
+		UserMessage:   userMessage,
+		HandledAt:     time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// errorSource returns the variant suffix of an error-handler job type,
+// e.g. "validation" for both error-handler.validation and error.handler.validation.
+func errorSource(jobType string) string {
+	for _, prefix := range []string{TaskType + ".", "error.handler."} {
+		if strings.HasPrefix(jobType, prefix) {
+			return strings.TrimPrefix(jobType, prefix)
+		}
+	}
+	return SourceGeneral
+}
@@ -0,0 +1,144 @@
+// internal/workers/infrastructure/error-handler/handler_test.go
+package errorhandler
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/logger"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+func createTestHandler(t *testing.T) *Handler {
+	return NewHandler(LoadConfig(), logger.NewTestLogger(t))
+}
+
+// ==========================
+// Core Functionality Tests
+// ==========================
+
+func TestHandler_Execute(t *testing.T) {
+	tests := []struct {
+		name             string
+		jobType          string
+		input            *Input
+		expectedSource   string
+		expectedCode     string
+		expectedCategory string
+		expectedMessage  string
+	}{
+		{
+			name:             "dashed validation variant",
+			jobType:          "error-handler.validation",
+			input:            &Input{ErrorCode: "APPLICATION_VALIDATION_FAILED", ErrorMessage: "missing field"},
+			expectedSource:   "validation",
+			expectedCode:     "APPLICATION_VALIDATION_FAILED",
+			expectedCategory: "VALIDATION",
+			expectedMessage:  userMessages["validation"],
+		},
+		{
+			name:             "dotted variant uses the same source",
+			jobType:          "error.handler.validation",
+			input:            &Input{ErrorCode: "INVALID_FILTER_FORMAT"},
+			expectedSource:   "validation",
+			expectedCode:     "INVALID_FILTER_FORMAT",
+			expectedCategory: "VALIDATION",
+			expectedMessage:  userMessages["validation"],
+		},
+		{
+			name:             "timer boundary without error variables",
+			jobType:          "error-handler.timeout",
+			input:            &Input{},
+			expectedSource:   SourceTimeout,
+			expectedCode:     "TIMEOUT_ERROR",
+			expectedCategory: "OTHER",
+			expectedMessage:  userMessages["timeout"],
+		},
+		{
+			name:             "generic handler prefers the original error code category",
+			jobType:          TaskType,
+			input:            &Input{ErrorCode: "ANONYMIZATION_FAILED", OriginalErrorCode: "QUERY_TIMEOUT"},
+			expectedSource:   SourceGeneral,
+			expectedCode:     "ANONYMIZATION_FAILED",
+			expectedCategory: "DATABASE",
+			expectedMessage:  LoadConfig().DefaultUserMessage,
+		},
+		{
+			name:             "missing error code",
+			jobType:          "error.handler.system",
+			input:            &Input{},
+			expectedSource:   "system",
+			expectedCode:     "UNKNOWN_ERROR",
+			expectedCategory: "OTHER",
+			expectedMessage:  LoadConfig().DefaultUserMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := createTestHandler(t).Execute(context.Background(), tt.jobType, tt.input)
+
+			require.NoError(t, err)
+			assert.True(t, output.ErrorHandled)
+			assert.Equal(t, tt.expectedSource, output.ErrorSource)
+			assert.Equal(t, tt.expectedCode, output.ErrorCode)
+			assert.Equal(t, tt.expectedCategory, output.ErrorCategory)
+			assert.Equal(t, tt.expectedMessage, output.UserMessage)
+			assert.NotEmpty(t, output.HandledAt)
+		})
+	}
+}
+
+func TestHandler_Process_UsesJobType(t *testing.T) {
+	job := &camunda.Job{
+		Job: entities.Job{ActivatedJob: &pb.ActivatedJob{
+			Type:      "error-handler.email",
+			Variables: `{"errorCode":"NOTIFICATION_SEND_FAILED","userId":"user-1"}`,
+		}},
+		TaskType: TaskType,
+	}
+
+	err := createTestHandler(t).process(context.Background(), job)
+
+	require.NoError(t, err)
+	output, ok := job.Output.(*Output)
+	require.True(t, ok)
+	assert.Equal(t, "email", output.ErrorSource)
+	assert.Equal(t, "NOTIFICATION_SEND_FAILED", output.ErrorCode)
+}
+
+func TestHandler_Process_InvalidVariables(t *testing.T) {
+	job := &camunda.Job{
+		Job: entities.Job{ActivatedJob: &pb.ActivatedJob{
+			Type:      TaskType,
+			Variables: `not json`,
+		}},
+	}
+
+	err := createTestHandler(t).process(context.Background(), job)
+
+	assert.Error(t, err)
+	assert.Nil(t, job.Output)
+}
+
+func TestErrorSource(t *testing.T) {
+	tests := map[string]string{
+		"error-handler":                   SourceGeneral,
+		"error-handler.session-not-found": "session-not-found",
+		"error.handler.invalid-method":    "invalid-method",
+		"error.validation":                SourceGeneral,
+	}
+
+	for jobType, expected := range tests {
+		assert.Equal(t, expected, errorSource(jobType), jobType)
+	}
+}
@@ -0,0 +1,45 @@
+// internal/workers/infrastructure/error-handler/models.go
+package errorhandler
+
+// Input carries the variables attached to a thrown BPMN error
+// (see errors.BPMNError.ToErrorVariables) plus the correlation ids
+// commonly present in process scope.
+type Input struct {
+	ErrorCode         string `json:"errorCode"`
+	ErrorMessage      string `json:"errorMessage"`
+	ErrorDetails      string `json:"errorDetails"`
+	OriginalErrorCode string `json:"originalErrorCode"`
+	Retryable         bool   `json:"retryable"`
+	UserId            string `json:"userId"`
+	RequestId         string `json:"requestId"`
+}
+
+type Output struct {
+	ErrorHandled  bool   `json:"errorHandled"`
+	ErrorSource   string `json:"errorSource"`
+	ErrorCategory string `json:"errorCategory"`
+	ErrorCode     string `json:"errorCode"`
+	UserMessage   string `json:"userMessage"`
+	HandledAt     string `json:"handledAt"`
+}
+
+// Error sources, taken from the job type suffix (error-handler.<source>).
+const (
+	SourceGeneral = "general"
+	SourceTimeout = "timeout"
+)
+
+// userMessages are shown to the end user for errors from a given source.
+var userMessages = map[string]string{
+	"validation":        "Some of the information provided is invalid. Please check it and try again.",
+	"timeout":           "The request took too long to complete. Please try again.",
+	"email":             "We couldn't send the email. Please try again later.",
+	"auth":              "We couldn't sign you in. Please try again.",
+	"oauth":             "We couldn't sign you in with the selected provider. Please try again.",
+	"captcha":           "Captcha verification failed. Please try again.",
+	"session-not-found": "Your session has expired. Please sign in again.",
+	"duplicate-email":   "An account with this email address already exists.",
+	"token":             "This link is invalid or has expired. Please request a new one.",
+	"password":          "The password doesn't meet the requirements.",
+	"network":           "A network error occurred. Please try again.",
+}
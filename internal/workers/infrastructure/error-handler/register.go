@@ -0,0 +1,14 @@
+// internal/workers/infrastructure/error-handler/register.go
+package errorhandler
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(LoadConfig(), deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("ERROR_HANDLING_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(handler.process)), nil
+	})
+}
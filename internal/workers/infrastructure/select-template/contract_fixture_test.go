@@ -0,0 +1,30 @@
+// internal/workers/infrastructure/select-template/contract_fixture_test.go
+package selecttemplate
+
+import (
+	"context"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+)
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Prepare: func(sample map[string]interface{}) {
+			sample["routePath"] = "/franchise/search"
+		},
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return createTestHandler(t, nil).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"TEMPLATE_SELECTION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				// A deployment without template.templateRules.route.
+				_, err := createTestHandler(t, &Config{TemplateRules: map[string]map[string]string{}}).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "TEMPLATE_SELECTION_FAILED",
+	}
+}
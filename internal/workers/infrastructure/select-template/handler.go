@@ -2,14 +2,9 @@ package selecttemplate
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"time"
 
 	"camunda-workers/internal/common/logger"
-
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 )
 
 const (
@@ -28,31 +23,6 @@ func NewHandler(config *Config, log logger.Logger) *Handler {
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job",
-		map[string]interface{}{
-			"jobKey":      job.Key,
-			"workflowKey": job.ProcessInstanceKey,
-		})
-
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err))
-		return
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	output, err := h.Execute(ctx, &input)
-	if err != nil {
-		h.failJob(client, job, "TEMPLATE_SELECTION_FAILED", err.Error())
-		return
-	}
-
-	h.completeJob(client, job, output)
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	var selected string
 
@@ -130,38 +100,6 @@ func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return &Output{SelectedTemplateId: selected}, nil
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{"error": err})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{"error": err})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string) {
-	h.logger.Error("job failed",
-		map[string]interface{}{
-			"jobKey":       job.Key,
-			"errorCode":    errorCode,
-			"errorMessage": errorMessage,
-		})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{"error": err})
-	}
-}
-
 // // internal/workers/infrastructure/select-template/handler.go
 // package selecttemplate
 
@@ -0,0 +1,22 @@
+// internal/workers/infrastructure/select-template/register.go
+package selecttemplate
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(
+			&Config{
+				TemplateRules: map[string]map[string]string{
+					"route": deps.Config.Template.TemplateRules.Route,
+					"flow":  deps.Config.Template.TemplateRules.Flow,
+				},
+			},
+			deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("TEMPLATE_SELECTION_FAILED"))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
@@ -1,11 +1,20 @@
 // internal/workers/infrastructure/validate-subscription/config.go
 package validatesubscription
 
-import "time"
+import (
+	"time"
+
+	"camunda-workers/internal/common/usage"
+)
 
 type Config struct {
 	Timeout  time.Duration
 	CacheTTL time.Duration
+	// Budgets are the daily AI budgets of users, checked against what
+	// Spend recorded since midnight UTC; nil checks none and every valid
+	// subscription is within budget.
+	Budgets *usage.Budgets
+	Spend   usage.Store
 }
 
 func LoadConfig() *Config {
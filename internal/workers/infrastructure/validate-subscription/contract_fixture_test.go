@@ -0,0 +1,67 @@
+// internal/workers/infrastructure/validate-subscription/contract_fixture_test.go
+package validatesubscription
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"camunda-workers/internal/common/contract"
+)
+
+const subscriptionQuery = `SELECT user_id, tier, expires_at, is_valid FROM user_subscriptions WHERE user_id = \$1`
+
+// newContractFixture wires the handler to fake dependencies for the
+// generated contract tests. Add an ErrorCases entry for every errorCode.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	run := func(t *testing.T, ctx context.Context, input *Input, expect func(q *sqlmock.ExpectedQuery)) (*Output, error) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		expect(mock.ExpectQuery(subscriptionQuery))
+
+		mr := miniredis.RunT(t)
+		return createTestHandler(t, db, redis.NewClient(&redis.Options{Addr: mr.Addr()}), nil).Execute(ctx, input)
+	}
+	subscription := func(valid bool, expiresAt time.Time) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id", "tier", "expires_at", "is_valid"}).
+			AddRow("user-123", "premium", expiresAt.Format(time.RFC3339), valid)
+	}
+
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			return run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+				q.WillReturnRows(subscription(true, time.Now().Add(24*time.Hour)))
+			})
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"SUBSCRIPTION_INVALID": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillReturnRows(subscription(false, time.Now().Add(24*time.Hour)))
+				})
+				return err
+			},
+			"SUBSCRIPTION_EXPIRED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillReturnRows(subscription(true, time.Now().Add(-24*time.Hour)))
+				})
+				return err
+			},
+			"SUBSCRIPTION_CHECK_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				_, err := run(t, ctx, input, func(q *sqlmock.ExpectedQuery) {
+					q.WillReturnError(errors.New("connection reset by peer"))
+				})
+				return err
+			},
+		},
+		Fallback: "UNKNOWN_ERROR",
+		Mappings: errorMappings,
+	}
+}
@@ -10,9 +10,8 @@ import (
 	"time"
 
 	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/usage"
 
-	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
-	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -42,40 +41,53 @@ func NewHandler(config *Config, db *sql.DB, redis *redis.Client, log logger.Logg
 	}
 }
 
-func (h *Handler) Handle(client worker.JobClient, job entities.Job) {
-	h.logger.Info("processing job", map[string]interface{}{
-		"jobKey":      job.Key,
-		"workflowKey": job.ProcessInstanceKey,
-	})
+func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
+	output, err := h.validate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if output.IsValid {
+		h.checkBudget(ctx, input.UserID, output)
+	}
+	return output, nil
+}
 
-	var input Input
-	if err := json.Unmarshal([]byte(job.Variables), &input); err != nil {
-		h.failJob(client, job, "PARSE_ERROR", fmt.Sprintf("parse input: %v", err), 0)
+// checkBudget compares what the user spent on AI today with the budget of
+// their tier. A spend that cannot be read is logged and does not hold the
+// user back.
+func (h *Handler) checkBudget(ctx context.Context, userID string, output *Output) {
+	output.WithinBudget = true
+	budget, ok := h.config.Budgets.For(userID, output.TierLevel)
+	if !ok || h.config.Spend == nil {
 		return
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
-	defer cancel()
-
-	output, err := h.execute(ctx, &input)
+	spend, err := h.config.Spend.Spent(ctx, userID, usage.StartOfDay(time.Now()))
 	if err != nil {
-		errorCode := "UNKNOWN_ERROR"
-		retries := int32(0)
-		if errors.Is(err, ErrSubscriptionInvalid) || errors.Is(err, ErrSubscriptionExpired) {
-			errorCode = err.Error()
-			retries = 0
-		} else if errors.Is(err, ErrSubscriptionCheckFailed) {
-			errorCode = "SUBSCRIPTION_CHECK_FAILED"
-			retries = 3
-		}
-		h.failJob(client, job, errorCode, err.Error(), retries)
+		h.logger.Warn("failed to read AI usage, budget not enforced", map[string]interface{}{
+			"userId": userID,
+			"error":  err.Error(),
+		})
 		return
 	}
-
-	h.completeJob(client, job, output)
+	output.WithinBudget = !budget.Exceeded(spend)
+	output.Budget = &BudgetStatus{
+		TokensUsed:   spend.Tokens,
+		TokensLimit:  budget.DailyTokens,
+		CostUsedUSD:  spend.CostUSD,
+		CostLimitUSD: budget.DailyCostUSD,
+	}
+	if !output.WithinBudget {
+		h.logger.Info("daily AI budget exceeded", map[string]interface{}{
+			"userId":     userID,
+			"tier":       output.TierLevel,
+			"tokensUsed": spend.Tokens,
+			"costUsed":   spend.CostUSD,
+		})
+	}
 }
 
-func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
+// validate looks the subscription up in the cache, then in Postgres.
+func (h *Handler) validate(ctx context.Context, input *Input) (*Output, error) {
 	cacheKey := "sub:" + input.UserID
 	if val, err := h.redis.Get(ctx, cacheKey).Result(); err == nil {
 		var sub Subscription
@@ -129,43 +141,6 @@ func (h *Handler) execute(ctx context.Context, input *Input) (*Output, error) {
 	return &Output{IsValid: true, TierLevel: sub.Tier}, nil
 }
 
-func (h *Handler) completeJob(client worker.JobClient, job entities.Job, output *Output) {
-	cmd, err := client.NewCompleteJobCommand().
-		JobKey(job.Key).
-		VariablesFromObject(output)
-	if err != nil {
-		h.logger.Error("failed to create complete job command", map[string]interface{}{
-			"error": err.Error(),
-		})
-		return
-	}
-	_, err = cmd.Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to send complete job command", map[string]interface{}{
-			"error": err.Error(),
-		})
-	}
-}
-
-func (h *Handler) failJob(client worker.JobClient, job entities.Job, errorCode, errorMessage string, _ int32) {
-	h.logger.Error("job failed", map[string]interface{}{
-		"jobKey":       job.Key,
-		"errorCode":    errorCode,
-		"errorMessage": errorMessage,
-	})
-
-	_, err := client.NewThrowErrorCommand().
-		JobKey(job.Key).
-		ErrorCode(errorCode).
-		ErrorMessage(errorMessage).
-		Send(context.Background())
-	if err != nil {
-		h.logger.Error("failed to throw error", map[string]interface{}{
-			"error": err.Error(),
-		})
-	}
-}
-
 func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
 	return h.execute(ctx, input)
 }
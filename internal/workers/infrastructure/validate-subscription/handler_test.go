@@ -10,7 +10,9 @@ import (
 	"testing"
 	"time"
 
+	"camunda-workers/internal/common/config"
 	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/usage"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/go-redis/redismock/v9"
@@ -216,6 +218,134 @@ func TestHandler_Execute_CacheHit(t *testing.T) {
 	})
 }
 
+// ==========================
+// AI Budget Tests
+// ==========================
+
+// spendStore reports a fixed spend for every user.
+type spendStore struct {
+	spend usage.Spend
+	err   error
+	since time.Time
+}
+
+func (s *spendStore) Insert(ctx context.Context, r usage.Record) error { return nil }
+
+func (s *spendStore) Spent(ctx context.Context, userID string, since time.Time) (usage.Spend, error) {
+	s.since = since
+	return s.spend, s.err
+}
+
+// cachedHandler answers from the cache for userID, with the given budgets.
+func cachedHandler(t *testing.T, userID, tier string, budgets *usage.Budgets, spend usage.Store) *Handler {
+	t.Helper()
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	redisClient, redisMock := redismock.NewClientMock()
+	cached, _ := json.Marshal(createSubscription(userID, tier, true, ""))
+	redisMock.ExpectGet("sub:" + userID).SetVal(string(cached))
+
+	conf := createTestConfig()
+	conf.Budgets = budgets
+	conf.Spend = spend
+	return createTestHandler(t, db, redisClient, conf)
+}
+
+func TestHandler_Budget(t *testing.T) {
+	var cfg config.UsageConfig
+	cfg.Budgets.Tiers = map[string]config.BudgetConfig{
+		"free":    {DailyTokens: 1000},
+		"premium": {DailyTokens: 100000, DailyCostUSD: 2},
+	}
+	cfg.Budgets.Users = map[string]config.BudgetConfig{
+		"VIP-1": {DailyTokens: 5000},
+	}
+	budgets := usage.NewBudgets(cfg)
+
+	tests := []struct {
+		name         string
+		userID, tier string
+		spend        usage.Spend
+		withinBudget bool
+		tokensLimit  int
+		noStatus     bool
+	}{
+		{name: "under tier budget", userID: "user-1", tier: "free", spend: usage.Spend{Tokens: 999}, withinBudget: true, tokensLimit: 1000},
+		{name: "tokens used up", userID: "user-1", tier: "free", spend: usage.Spend{Tokens: 1000}, withinBudget: false, tokensLimit: 1000},
+		{name: "cost used up", userID: "user-2", tier: "premium", spend: usage.Spend{Tokens: 10, CostUSD: 2.5}, withinBudget: false, tokensLimit: 100000},
+		{name: "user budget replaces tier", userID: "vip-1", tier: "free", spend: usage.Spend{Tokens: 4000}, withinBudget: true, tokensLimit: 5000},
+		{name: "tier without budget", userID: "user-3", tier: "enterprise", spend: usage.Spend{Tokens: 1 << 30}, withinBudget: true, noStatus: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &spendStore{spend: tt.spend}
+			handler := cachedHandler(t, tt.userID, tt.tier, budgets, store)
+
+			output, err := handler.Execute(context.Background(), createInput(tt.userID, tt.tier))
+			require.NoError(t, err)
+			assert.True(t, output.IsValid)
+			assert.Equal(t, tt.withinBudget, output.WithinBudget)
+			if tt.noStatus {
+				assert.Nil(t, output.Budget)
+				return
+			}
+			require.NotNil(t, output.Budget)
+			assert.Equal(t, tt.spend.Tokens, output.Budget.TokensUsed)
+			assert.Equal(t, tt.tokensLimit, output.Budget.TokensLimit)
+			assert.Equal(t, usage.StartOfDay(time.Now()), store.since)
+		})
+	}
+}
+
+func TestHandler_Budget_WithoutBudgetsAlwaysWithin(t *testing.T) {
+	handler := cachedHandler(t, "user-1", "free", nil, &spendStore{spend: usage.Spend{Tokens: 1 << 30}})
+
+	output, err := handler.Execute(context.Background(), createInput("user-1", "free"))
+	require.NoError(t, err)
+	assert.True(t, output.WithinBudget)
+	assert.Nil(t, output.Budget)
+}
+
+func TestHandler_Budget_UnreadableSpendDoesNotBlock(t *testing.T) {
+	budgets := &usage.Budgets{Tiers: map[string]usage.Budget{"free": {DailyTokens: 1}}}
+	handler := cachedHandler(t, "user-1", "free", budgets, &spendStore{err: errors.New("connection refused")})
+
+	output, err := handler.Execute(context.Background(), createInput("user-1", "free"))
+	require.NoError(t, err)
+	assert.True(t, output.WithinBudget)
+	assert.Nil(t, output.Budget)
+}
+
+func TestHandler_Budget_SumsAIUsageTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	redisClient, redisMock := redismock.NewClientMock()
+
+	redisMock.ExpectGet("sub:user-1").RedisNil()
+	mock.ExpectQuery("SELECT user_id, tier, expires_at, is_valid FROM user_subscriptions").
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "tier", "expires_at", "is_valid"}).
+			AddRow("user-1", "basic", "", true))
+	redisMock.Regexp().ExpectSet("sub:user-1", `.*`, 5*time.Minute).SetVal("OK")
+	mock.ExpectQuery("FROM ai_usage WHERE user_id = \\$1 AND created_at >= \\$2").
+		WithArgs("user-1", usage.StartOfDay(time.Now())).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "cost"}).AddRow(12000, 0.42))
+
+	conf := createTestConfig()
+	conf.Budgets = &usage.Budgets{Tiers: map[string]usage.Budget{"basic": {DailyTokens: 10000}}}
+	conf.Spend = usage.NewPostgresStore(db)
+	handler := createTestHandler(t, db, redisClient, conf)
+
+	output, err := handler.Execute(context.Background(), createInput("user-1", "basic"))
+	require.NoError(t, err)
+	assert.False(t, output.WithinBudget)
+	assert.Equal(t, &BudgetStatus{TokensUsed: 12000, TokensLimit: 10000, CostUsedUSD: 0.42}, output.Budget)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.NoError(t, redisMock.ExpectationsWereMet())
+}
+
 func TestHandler_Execute_ValidationErrors(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -11,6 +11,19 @@ type Output struct {
 	IsValid     bool     `json:"isValid"`
 	TierLevel   string   `json:"tierLevel"`
 	Permissions []string `json:"permissions,omitempty"`
+	// WithinBudget is false once the user has used up the daily AI budget
+	// of their tier; ai_query.bpmn stops before parsing the question.
+	WithinBudget bool          `json:"withinBudget"`
+	Budget       *BudgetStatus `json:"budget,omitempty"`
+}
+
+// BudgetStatus is what the user spent today against their daily AI budget.
+// A zero limit is unlimited.
+type BudgetStatus struct {
+	TokensUsed   int     `json:"tokensUsed"`
+	TokensLimit  int     `json:"tokensLimit"`
+	CostUsedUSD  float64 `json:"costUsedUsd"`
+	CostLimitUSD float64 `json:"costLimitUsd"`
 }
 
 // Subscription represents a user subscription record
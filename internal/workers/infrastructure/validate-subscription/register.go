@@ -0,0 +1,49 @@
+// internal/workers/infrastructure/validate-subscription/register.go
+package validatesubscription
+
+import (
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/usage"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrSubscriptionInvalid, Code: "SUBSCRIPTION_INVALID"},
+	{Err: ErrSubscriptionExpired, Code: "SUBSCRIPTION_EXPIRED"},
+	{Err: ErrSubscriptionCheckFailed, Code: "SUBSCRIPTION_CHECK_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		budgets, spend := newBudgets(deps)
+		handler := NewHandler(
+			&Config{
+				Timeout: config.GetDuration(deps.WorkerConfig(TaskType).Timeout),
+				Budgets: budgets,
+				Spend:   spend,
+			},
+			deps.Postgres.DB, deps.Redis.Client, deps.Logger,
+		)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("UNKNOWN_ERROR", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
+
+// newBudgets returns the budgets of usage.budgets and the ai_usage table
+// they are checked against. Budgets need the calls recorded in Postgres;
+// without usage.store postgres they are not enforced.
+func newBudgets(deps *camunda.Dependencies) (*usage.Budgets, usage.Store) {
+	cfg := deps.Config.Usage
+	budgets := usage.NewBudgets(cfg)
+	if budgets == nil {
+		return nil, nil
+	}
+	if !cfg.Enabled || cfg.Store != "postgres" || deps.Postgres == nil {
+		deps.Logger.Warn("AI budgets need usage enabled with store postgres, budgets not enforced", map[string]interface{}{
+			"store": cfg.Store,
+		})
+		return nil, nil
+	}
+	return budgets, usage.NewPostgresStore(deps.Postgres.DB)
+}
@@ -0,0 +1,16 @@
+// internal/workers/user/user-data-anonymize/config.go
+package userdataanonymize
+
+import "time"
+
+type Config struct {
+	AnonymizedEmailDomain string
+	Timeout               time.Duration
+}
+
+func LoadConfig() *Config {
+	return &Config{
+		AnonymizedEmailDomain: "anonymized.invalid",
+		Timeout:               30 * time.Second,
+	}
+}
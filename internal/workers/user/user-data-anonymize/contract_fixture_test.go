@@ -0,0 +1,38 @@
+// internal/workers/user/user-data-anonymize/contract_fixture_test.go
+package userdataanonymize
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"camunda-workers/internal/common/contract"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newContractFixture wires the handler to a mocked database for the
+// generated contract tests.
+func newContractFixture(t *testing.T) *contract.Fixture[Input, *Output] {
+	return &contract.Fixture[Input, *Output]{
+		Execute: func(t *testing.T, ctx context.Context, input *Input) (*Output, error) {
+			db, mock := setupMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(deleteQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(auditQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			return createTestHandler(t, db).Execute(ctx, input)
+		},
+		ErrorCases: map[string]func(t *testing.T, ctx context.Context, input *Input) error{
+			"ANONYMIZATION_FAILED": func(t *testing.T, ctx context.Context, input *Input) error {
+				db, mock := setupMockDB(t)
+				mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+				_, err := createTestHandler(t, db).Execute(ctx, input)
+				return err
+			},
+		},
+		Fallback: "ANONYMIZATION_FAILED",
+		Mappings: errorMappings,
+	}
+}
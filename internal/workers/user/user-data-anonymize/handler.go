@@ -0,0 +1,105 @@
+// internal/workers/user/user-data-anonymize/handler.go
+package userdataanonymize
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"camunda-workers/internal/common/logger"
+)
+
+const (
+	TaskType = "user-data-anonymize"
+)
+
+var (
+	ErrUserNotFound        = errors.New("USER_NOT_FOUND")
+	ErrAnonymizationFailed = errors.New("ANONYMIZATION_FAILED")
+)
+
+type Handler struct {
+	config *Config
+	db     *sql.DB
+	logger logger.Logger
+}
+
+func NewHandler(config *Config, db *sql.DB, log logger.Logger) *Handler {
+	return &Handler{
+		config: config,
+		db:     db,
+		logger: log.WithFields(map[string]interface{}{"taskType": TaskType}),
+	}
+}
+
+// Execute strips personal data from the user's row, revokes their sessions
+// and records the anonymization in the audit log, all in one transaction.
+// The row itself is kept so that applications and audit entries still
+// resolve. Running it again for the same user is harmless.
+func (h *Handler) Execute(ctx context.Context, input *Input) (*Output, error) {
+	if input.UserId == "" {
+		return nil, fmt.Errorf("%w: userId is required", ErrUserNotFound)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: begin transaction: %v", ErrAnonymizationFailed, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE users SET email = $2, phone = NULL, capital_available = NULL, location_preferences = NULL, interests = NULL, industry_experience = NULL WHERE id = $1`,
+		input.UserId, h.anonymizedEmail(input.UserId),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: anonymize user: %v", ErrAnonymizationFailed, err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, input.UserId)
+	}
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM auth_sessions WHERE user_id = $1`, input.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: revoke sessions: %v", ErrAnonymizationFailed, err)
+	}
+	sessionsRevoked, _ := result.RowsAffected()
+
+	anonymizedAt := time.Now().UTC().Format(time.RFC3339)
+	details, _ := json.Marshal(map[string]interface{}{
+		"sessionsRevoked": sessionsRevoked,
+		"anonymizedAt":    anonymizedAt,
+	})
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (event_type, resource_type, resource_id, details) VALUES ($1, $2, $3, $4)`,
+		"USER_DATA_ANONYMIZED", "user", input.UserId, details,
+	); err != nil {
+		return nil, fmt.Errorf("%w: write audit log: %v", ErrAnonymizationFailed, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%w: commit: %v", ErrAnonymizationFailed, err)
+	}
+
+	h.logger.Info("user data anonymized", map[string]interface{}{
+		"userId":          input.UserId,
+		"sessionsRevoked": sessionsRevoked,
+	})
+
+	return &Output{
+		DataAnonymized:  true,
+		SessionsRevoked: sessionsRevoked,
+		AnonymizedAt:    anonymizedAt,
+	}, nil
+}
+
+// anonymizedEmail derives a stable placeholder address, since users.email is
+// NOT NULL and UNIQUE.
+func (h *Handler) anonymizedEmail(userId string) string {
+	sum := sha256.Sum256([]byte(userId))
+	return fmt.Sprintf("deleted-%s@%s", hex.EncodeToString(sum[:8]), h.config.AnonymizedEmailDomain)
+}
@@ -0,0 +1,148 @@
+// internal/workers/user/user-data-anonymize/handler_test.go
+package userdataanonymize
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"camunda-workers/internal/common/logger"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	updateQuery = `UPDATE users SET email = \$2, phone = NULL, capital_available = NULL, location_preferences = NULL, interests = NULL, industry_experience = NULL WHERE id = \$1`
+	deleteQuery = `DELETE FROM auth_sessions WHERE user_id = \$1`
+	auditQuery  = `INSERT INTO audit_log \(event_type, resource_type, resource_id, details\) VALUES \(\$1, \$2, \$3, \$4\)`
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func createTestHandler(t *testing.T, db *sql.DB) *Handler {
+	return NewHandler(LoadConfig(), db, logger.NewTestLogger(t))
+}
+
+// ==========================
+// Core Functionality Tests
+// ==========================
+
+func TestHandler_Execute_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	handler := createTestHandler(t, db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(updateQuery).
+		WithArgs("user-1", handler.anonymizedEmail("user-1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(deleteQuery).
+		WithArgs("user-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(auditQuery).
+		WithArgs("USER_DATA_ANONYMIZED", "user", "user-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	output, err := handler.Execute(context.Background(), &Input{UserId: "user-1"})
+
+	require.NoError(t, err)
+	assert.True(t, output.DataAnonymized)
+	assert.Equal(t, int64(2), output.SessionsRevoked)
+	assert.NotEmpty(t, output.AnonymizedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandler_AnonymizedEmail(t *testing.T) {
+	handler := createTestHandler(t, nil)
+
+	email := handler.anonymizedEmail("user-1")
+
+	assert.Equal(t, email, handler.anonymizedEmail("user-1"), "must be stable across retries")
+	assert.NotEqual(t, email, handler.anonymizedEmail("user-2"))
+	assert.True(t, strings.HasSuffix(email, "@anonymized.invalid"))
+	assert.NotContains(t, email, "user-1")
+}
+
+// ==========================
+// Error Handling Tests
+// ==========================
+
+func TestHandler_Execute_UserNotFound(t *testing.T) {
+	db, mock := setupMockDB(t)
+	handler := createTestHandler(t, db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	output, err := handler.Execute(context.Background(), &Input{UserId: "missing"})
+
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHandler_Execute_MissingUserId(t *testing.T) {
+	output, err := createTestHandler(t, nil).Execute(context.Background(), &Input{})
+
+	assert.Nil(t, output)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestHandler_Execute_DatabaseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "begin fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+			},
+		},
+		{
+			name: "update fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateQuery).WillReturnError(errors.New("deadlock detected"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "audit insert fails",
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(deleteQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(auditQuery).WillReturnError(errors.New("relation does not exist"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupMockDB(t)
+			tt.setup(mock)
+
+			output, err := createTestHandler(t, db).Execute(context.Background(), &Input{UserId: "user-1"})
+
+			assert.Nil(t, output)
+			assert.ErrorIs(t, err, ErrAnonymizationFailed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// internal/workers/user/user-data-anonymize/models.go
+package userdataanonymize
+
+type Input struct {
+	UserId string `json:"userId"`
+}
+
+type Output struct {
+	DataAnonymized  bool   `json:"dataAnonymized"`
+	SessionsRevoked int64  `json:"sessionsRevoked"`
+	AnonymizedAt    string `json:"anonymizedAt"`
+}
@@ -0,0 +1,21 @@
+// internal/workers/user/user-data-anonymize/register.go
+package userdataanonymize
+
+import (
+	"camunda-workers/internal/common/camunda"
+)
+
+// errorMappings assigns BPMN error codes to the sentinel errors returned by the handler.
+// Both map to ANONYMIZATION_FAILED, the only error the deletion process catches.
+var errorMappings = []camunda.ErrorMapping{
+	{Err: ErrUserNotFound, Code: "ANONYMIZATION_FAILED"},
+	{Err: ErrAnonymizationFailed, Code: "ANONYMIZATION_FAILED", Retryable: true},
+}
+
+func init() {
+	camunda.RegisterWorker(TaskType, func(deps *camunda.Dependencies) (camunda.Worker, error) {
+		handler := NewHandler(LoadConfig(), deps.Postgres.DB, deps.Logger)
+		pipeline := deps.Pipeline(TaskType, camunda.MapErrors("ANONYMIZATION_FAILED", errorMappings...))
+		return camunda.NewHandlerWorker(TaskType, pipeline.Handler(camunda.Typed(handler.Execute))), nil
+	})
+}
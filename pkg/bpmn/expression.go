@@ -0,0 +1,82 @@
+// pkg/bpmn/expression.go
+package bpmn
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// feelKeywords are names that appear in conditions but are not variables.
+var feelKeywords = map[string]bool{
+	"true": true, "false": true, "null": true,
+	"and": true, "or": true, "not": true,
+	"in": true, "between": true, "instance": true, "of": true,
+	"if": true, "then": true, "else": true,
+	"for": true, "return": true, "some": true, "every": true, "satisfies": true,
+}
+
+// ConditionBody strips the expression markers from a condition: the FEEL
+// "=" prefix and the legacy "${...}" wrapper, which some models combine.
+func ConditionBody(expr string) string {
+	body := strings.TrimSpace(expr)
+	body = strings.TrimSpace(strings.TrimPrefix(body, "="))
+	if strings.HasPrefix(body, "${") && strings.HasSuffix(body, "}") {
+		body = strings.TrimSpace(body[2 : len(body)-1])
+	}
+	return body
+}
+
+// ConditionVariables returns the root variable names a condition reads, in
+// sorted order. Keywords, literals, function names and property accesses
+// (the "b" in "a.b") are not reported.
+func ConditionVariables(expr string) []string {
+	body := []rune(ConditionBody(expr))
+	seen := make(map[string]bool)
+	var names []string
+
+	for i := 0; i < len(body); {
+		r := body[i]
+		switch {
+		case r == '"' || r == '\'':
+			// Skip string literals, honouring backslash escapes.
+			i++
+			for i < len(body) && body[i] != r {
+				if body[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(body) && (unicode.IsLetter(body[i]) || unicode.IsDigit(body[i]) || body[i] == '_') {
+				i++
+			}
+			name := string(body[start:i])
+
+			if start > 0 && body[start-1] == '.' {
+				continue
+			}
+			next := i
+			for next < len(body) && body[next] == ' ' {
+				next++
+			}
+			if next < len(body) && body[next] == '(' {
+				continue
+			}
+			if feelKeywords[name] || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+
+		default:
+			i++
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
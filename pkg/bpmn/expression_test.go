@@ -0,0 +1,30 @@
+// pkg/bpmn/expression_test.go
+package bpmn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionVariables(t *testing.T) {
+	tests := []struct {
+		condition string
+		expected  []string
+	}{
+		{`=score > 50`, []string{"score"}},
+		{`${isValid == true}`, []string{"isValid"}},
+		{`=${tier = "premium"}`, []string{"tier"}},
+		{`=priority = "high" and score >= threshold`, []string{"priority", "score", "threshold"}},
+		{`=subscription.tier = 'free' or not(subscription.active)`, []string{"subscription"}},
+		{`=count(results) > 0 and results[1].score != null`, []string{"results"}},
+		{`=message = "say \"hi\" to score"`, []string{"message"}},
+		{`=true`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ConditionVariables(tt.condition))
+		})
+	}
+}
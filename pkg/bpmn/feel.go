@@ -0,0 +1,561 @@
+// pkg/bpmn/feel.go
+package bpmn
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Evaluate computes a FEEL-lite expression against process variables. It
+// covers what the models use in conditions and variable mappings:
+//
+//   - literals: numbers, "strings" with backslash escapes, true, false, null
+//     and lists [a, b]
+//   - variables and property paths (a.b.c); unknown names are null
+//   - comparison with = != < <= > >=, plus x in list
+//   - and, or and not(x)
+//   - + - * / on numbers, + on strings, unary minus and parentheses
+//   - count(list)
+//
+// As in FEEL, comparing or combining null yields null, and a boolean
+// operator with a null operand is null unless the other decides the result.
+// The "=" prefix is stripped first. JUEL syntax - the ${...} wrapper and the
+// ==, &&, || and ! operators - is a parse error, as it is in Zeebe.
+func Evaluate(expr string, variables map[string]interface{}) (interface{}, error) {
+	body := strings.TrimPrefix(strings.TrimSpace(expr), "=")
+	toks, err := tokenize(body)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", expr, err)
+	}
+	p := &feelParser{toks: toks, vars: variables}
+	v, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", expr, err)
+	}
+	return v, nil
+}
+
+// EvaluateCondition evaluates a sequence flow condition. A null result is
+// false, so conditions on variables that are not set do not match; any
+// other non-boolean result is an error.
+func EvaluateCondition(expr string, variables map[string]interface{}) (bool, error) {
+	v, err := Evaluate(expr, variables)
+	if err != nil {
+		return false, err
+	}
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	}
+	return false, fmt.Errorf("condition %q evaluated to %T, not a boolean", expr, v)
+}
+
+type tokenKind int
+
+const (
+	tokNumber tokenKind = iota
+	tokString
+	tokName
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// feelOps lists the operators, longest first so that "<=" wins over "<".
+var feelOps = []string{"!=", "<=", ">=", "=", "<", ">", "+", "-", "*", "/", "(", ")", "[", "]", ",", "."}
+
+// juelOps maps the JUEL operators that are not FEEL to their FEEL spelling.
+// "!" is checked after "!=", which is FEEL.
+var juelOps = []struct{ op, feel string }{
+	{"==", "="},
+	{"&&", "and"},
+	{"||", "or"},
+	{"!", "not()"},
+}
+
+func tokenize(s string) ([]token, error) {
+	var toks []token
+	rs := []rune(s)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			i++
+			for ; i < len(rs) && rs[i] != r; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+					switch rs[i] {
+					case 'n':
+						sb.WriteRune('\n')
+					case 't':
+						sb.WriteRune('\t')
+					default:
+						sb.WriteRune(rs[i])
+					}
+					continue
+				}
+				sb.WriteRune(rs[i])
+			}
+			if i >= len(rs) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			i++
+			toks = append(toks, token{tokString, sb.String()})
+
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, string(rs[start:i])})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_') {
+				i++
+			}
+			toks = append(toks, token{tokName, string(rs[start:i])})
+
+		case r == '$':
+			return nil, fmt.Errorf("the JUEL ${...} wrapper is not FEEL")
+
+		default:
+			for _, j := range juelOps {
+				rest := string(rs[i:])
+				if strings.HasPrefix(rest, j.op) && !strings.HasPrefix(rest, "!=") {
+					return nil, fmt.Errorf("%q is not FEEL, use %s", j.op, j.feel)
+				}
+			}
+			matched := false
+			for _, op := range feelOps {
+				if strings.HasPrefix(string(rs[i:]), op) {
+					toks = append(toks, token{tokOp, op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return toks, nil
+}
+
+// feelParser evaluates while it parses; expressions are short and are
+// evaluated once.
+type feelParser struct {
+	toks []token
+	pos  int
+	vars map[string]interface{}
+}
+
+func (p *feelParser) done() bool { return p.pos >= len(p.toks) }
+
+func (p *feelParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.toks[p.pos]
+}
+
+// accept consumes the next token if it is one of the given operators or
+// keywords and returns it.
+func (p *feelParser) accept(texts ...string) (string, bool) {
+	if p.done() {
+		return "", false
+	}
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokName {
+		return "", false
+	}
+	for _, text := range texts {
+		if t.text == text {
+			p.pos++
+			return text, true
+		}
+	}
+	return "", false
+}
+
+func (p *feelParser) expect(text string) error {
+	if _, ok := p.accept(text); !ok {
+		if p.done() {
+			return fmt.Errorf("expected %q at end of expression", text)
+		}
+		return fmt.Errorf("expected %q, found %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *feelParser) parseOr() (interface{}, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+}
+
+func (p *feelParser) parseAnd() (interface{}, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+}
+
+func (p *feelParser) parseComparison() (interface{}, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("=", "!=", "<", "<=", ">", ">=", "in")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return compare(op, left, right)
+}
+
+func (p *feelParser) parseAdditive() (interface{}, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, left, right)
+	}
+}
+
+func (p *feelParser) parseMultiplicative() (interface{}, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, left, right)
+	}
+}
+
+func (p *feelParser) parseUnary() (interface{}, error) {
+	if _, ok := p.accept("-"); ok {
+		v, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return arithmetic("-", 0.0, v), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *feelParser) parsePrimary() (interface{}, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return n, nil
+
+	case tokString:
+		return t.text, nil
+
+	case tokName:
+		switch t.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t.text)
+		}
+		v := p.vars[t.text]
+		for {
+			if _, ok := p.accept("."); !ok {
+				return v, nil
+			}
+			prop := p.peek()
+			if prop.kind != tokName {
+				return nil, fmt.Errorf("expected property name after %q", ".")
+			}
+			p.pos++
+			obj, _ := v.(map[string]interface{})
+			v = obj[prop.text]
+		}
+	}
+
+	switch t.text {
+	case "(":
+		v, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return v, p.expect(")")
+
+	case "[":
+		list := []interface{}{}
+		if _, ok := p.accept("]"); ok {
+			return list, nil
+		}
+		for {
+			v, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			return list, p.expect("]")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func (p *feelParser) parseCall(name string) (interface{}, error) {
+	var args []interface{}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			v, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	switch name {
+	case "not":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("not() takes one argument")
+		}
+		return not(args[0]), nil
+	case "count":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("count() takes one argument")
+		}
+		if list, ok := args[0].([]interface{}); ok {
+			return float64(len(list)), nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown function %s()", name)
+}
+
+func and(a, b interface{}) interface{} {
+	ab, aok := a.(bool)
+	bb, bok := b.(bool)
+	switch {
+	case (aok && !ab) || (bok && !bb):
+		return false
+	case aok && bok:
+		return true
+	}
+	return nil
+}
+
+func or(a, b interface{}) interface{} {
+	ab, aok := a.(bool)
+	bb, bok := b.(bool)
+	switch {
+	case (aok && ab) || (bok && bb):
+		return true
+	case aok && bok:
+		return false
+	}
+	return nil
+}
+
+func not(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		return !b
+	}
+	return nil
+}
+
+func compare(op string, a, b interface{}) (interface{}, error) {
+	switch op {
+	case "=":
+		return equal(a, b), nil
+	case "!=":
+		return !equal(a, b), nil
+	case "in":
+		if list, ok := b.([]interface{}); ok {
+			for _, item := range list {
+				if equal(a, item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return equal(a, b), nil
+	}
+
+	if a == nil || b == nil {
+		return nil, nil
+	}
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		if !ok {
+			return nil, nil
+		}
+		return ordered(op, compareFloat(x, y)), nil
+	}
+	if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		if !ok {
+			return nil, nil
+		}
+		return ordered(op, strings.Compare(x, y)), nil
+	}
+	return nil, nil
+}
+
+func compareFloat(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func ordered(op string, c int) bool {
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func arithmetic(op string, a, b interface{}) interface{} {
+	if op == "+" {
+		if x, ok := a.(string); ok {
+			if y, ok := b.(string); ok {
+				return x + y
+			}
+			return nil
+		}
+	}
+	x, ok := toNumber(a)
+	if !ok {
+		return nil
+	}
+	y, ok := toNumber(b)
+	if !ok {
+		return nil
+	}
+	switch op {
+	case "+":
+		return x + y
+	case "-":
+		return x - y
+	case "*":
+		return x * y
+	}
+	if y == 0 {
+		return nil
+	}
+	return x / y
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,155 @@
+// pkg/bpmn/feel_test.go
+package bpmn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feelVariables() map[string]interface{} {
+	return map[string]interface{}{
+		"score":   75,
+		"tier":    "premium",
+		"valid":   true,
+		"results": []interface{}{"a", "b"},
+		"subscription": map[string]interface{}{
+			"tier":   "free",
+			"limits": map[string]interface{}{"queries": 10.0},
+		},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		// literals
+		{`=42`, 42.0},
+		{`=1.5`, 1.5},
+		{`="say \"hi\"\n"`, "say \"hi\"\n"},
+		{`='single'`, "single"},
+		{`=true`, true},
+		{`=null`, nil},
+		{`=[1, "a", [] ]`, []interface{}{1.0, "a", []interface{}{}}},
+
+		// variables and paths
+		{`=tier`, "premium"},
+		{`=subscription.limits.queries`, 10.0},
+		{`=subscription.missing.deeper`, nil},
+		{`=unknown`, nil},
+		{`tier`, "premium"},
+
+		// comparison
+		{`=score = 75`, true},
+		{`=score != 75`, false},
+		{`=score >= 75`, true},
+		{`=score < 50`, false},
+		{`=tier = "premium"`, true},
+		{`=tier > "basic"`, true},
+		{`=unknown = null`, true},
+		{`=unknown != "x"`, true},
+		{`=unknown > 1`, nil},
+		{`=score > "high"`, nil},
+		{`=tier in ["basic", "premium"]`, true},
+		{`=tier in "premium"`, true},
+		{`=subscription.tier in []`, false},
+		{`=tier = "a != b"`, false},
+
+		// boolean logic with FEEL null semantics
+		{`=valid and score > 50`, true},
+		{`=valid and not(valid)`, false},
+		{`=not(valid) or tier = "premium"`, true},
+		{`=unknown and false`, false},
+		{`=unknown and true`, nil},
+		{`=unknown or true`, true},
+		{`=unknown or false`, nil},
+		{`=not(unknown)`, nil},
+
+		// arithmetic
+		{`=score + 5 * 2`, 85.0},
+		{`=(score + 5) * 2`, 160.0},
+		{`=-score / 3`, -25.0},
+		{`=score / 0`, nil},
+		{`="a" + tier`, "apremium"},
+		{`="a" + 1`, nil},
+		{`=unknown + 1`, nil},
+
+		// functions
+		{`=count(results)`, 2.0},
+		{`=count(unknown)`, nil},
+		{`=count(results) > 0 and results != null`, true},
+	}
+
+	vars := feelVariables()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			v, err := Evaluate(tt.expr, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestEvaluate_ParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		// JUEL is not FEEL
+		{`${valid}`, "JUEL ${...} wrapper"},
+		{`=${tier = "premium"}`, "JUEL ${...} wrapper"},
+		{`=score == 75`, `"==" is not FEEL, use =`},
+		{`=valid && score > 50`, `"&&" is not FEEL, use and`},
+		{`=valid || score > 50`, `"||" is not FEEL, use or`},
+		{`=!valid`, `"!" is not FEEL, use not()`},
+
+		// malformed FEEL
+		{`=tier = "premium`, "unterminated string"},
+		{`=score >`, "unexpected end of expression"},
+		{`=(score > 1`, `expected ")" at end of expression`},
+		{`=[1, 2`, `expected "]" at end of expression`},
+		{`=score 75`, `unexpected "75"`},
+		{`=subscription.`, "expected property name"},
+		{`=matches(tier, "p")`, "unknown function matches()"},
+		{`=not(valid, tier)`, "not() takes one argument"},
+		{`=count()`, "count() takes one argument"},
+		{`=1.2.3`, `invalid number "1.2.3"`},
+		{`=score # 2`, `unexpected character '#'`},
+	}
+
+	vars := feelVariables()
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Evaluate(tt.expr, vars)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+			assert.Contains(t, err.Error(), fmt.Sprintf("expression %q", tt.expr))
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	vars := feelVariables()
+
+	ok, err := EvaluateCondition(`=tier = "premium" and score >= 75`, vars)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = EvaluateCondition(`=unknown = true`, vars)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = EvaluateCondition(`=unknown > 1`, vars)
+	require.NoError(t, err)
+	assert.False(t, ok, "a null result does not match")
+
+	_, err = EvaluateCondition(`=score + 1`, vars)
+	assert.ErrorContains(t, err, "evaluated to float64, not a boolean")
+
+	_, err = EvaluateCondition(`${valid == true}`, vars)
+	assert.ErrorContains(t, err, "JUEL")
+}
@@ -0,0 +1,417 @@
+// pkg/bpmn/model.go
+package bpmn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Element kinds, named after their BPMN XML elements.
+const (
+	KindStartEvent             = "startEvent"
+	KindEndEvent               = "endEvent"
+	KindServiceTask            = "serviceTask"
+	KindUserTask               = "userTask"
+	KindCallActivity           = "callActivity"
+	KindExclusiveGateway       = "exclusiveGateway"
+	KindParallelGateway        = "parallelGateway"
+	KindEventBasedGateway      = "eventBasedGateway"
+	KindBoundaryEvent          = "boundaryEvent"
+	KindIntermediateCatchEvent = "intermediateCatchEvent"
+	KindIntermediateThrowEvent = "intermediateThrowEvent"
+)
+
+// Event definition kinds carried by events.
+const (
+	EventError   = "error"
+	EventTimer   = "timer"
+	EventMessage = "message"
+)
+
+// flowNodeKinds are the elements parsed into a process's Elements.
+var flowNodeKinds = map[string]bool{
+	KindStartEvent:             true,
+	KindEndEvent:               true,
+	KindServiceTask:            true,
+	KindUserTask:               true,
+	KindCallActivity:           true,
+	KindExclusiveGateway:       true,
+	KindParallelGateway:        true,
+	KindEventBasedGateway:      true,
+	KindBoundaryEvent:          true,
+	KindIntermediateCatchEvent: true,
+	KindIntermediateThrowEvent: true,
+	"task":                     true,
+	"scriptTask":               true,
+	"sendTask":                 true,
+	"receiveTask":              true,
+	"businessRuleTask":         true,
+	"inclusiveGateway":         true,
+	"subProcess":               true,
+}
+
+// Definitions is a parsed BPMN document.
+type Definitions struct {
+	File      string
+	Errors    map[string]*Error // by ID
+	Messages  map[string]string // message ID to name
+	Processes []*Process
+}
+
+// Error is a bpmn:error definition.
+type Error struct {
+	ID   string
+	Name string
+	Code string // errorCode attribute, falling back to the name
+}
+
+// Process is an executable bpmn:process.
+type Process struct {
+	ID       string
+	Name     string
+	Elements map[string]*Element
+	Flows    map[string]*SequenceFlow
+
+	// Order lists element IDs in document order, for stable reports.
+	Order []string
+}
+
+// Mapping is a zeebe:input or zeebe:output variable mapping.
+type Mapping struct {
+	Source string
+	Target string
+}
+
+// Element is a flow node: event, task, gateway or call activity.
+type Element struct {
+	ID   string
+	Name string
+	Kind string
+
+	Incoming []string
+	Outgoing []string
+
+	// Service tasks
+	TaskType string
+	Retries  int // 0 when not set in the model
+	Headers  map[string]string
+
+	// Tasks and call activities
+	Inputs  []Mapping
+	Outputs []Mapping
+
+	// Call activities
+	CalledProcessID string
+	// PropagateParent and PropagateChild default to true, as in Zeebe.
+	PropagateParent bool
+	PropagateChild  bool
+
+	// Gateways
+	DefaultFlow string
+
+	// Events
+	EventKind      string // error, timer, message or "" for none events
+	ErrorRef       string
+	MessageRef     string
+	TimerDuration  string
+	AttachedTo     string
+	CancelActivity bool
+}
+
+// SequenceFlow connects two elements, optionally guarded by a condition.
+type SequenceFlow struct {
+	ID        string
+	Source    string
+	Target    string
+	Condition string
+}
+
+// Label returns the element's name, or its ID when it has none.
+func (e *Element) Label() string {
+	if e.Name != "" {
+		return fmt.Sprintf("%s (%s)", e.ID, e.Name)
+	}
+	return e.ID
+}
+
+// Boundaries returns the boundary events attached to the element with the given ID.
+func (p *Process) Boundaries(id string) []*Element {
+	var events []*Element
+	for _, eid := range p.Order {
+		if el := p.Elements[eid]; el.Kind == KindBoundaryEvent && el.AttachedTo == id {
+			events = append(events, el)
+		}
+	}
+	return events
+}
+
+// FlowIDs returns the IDs of all sequence flows in sorted order.
+func (p *Process) FlowIDs() []string {
+	ids := make([]string, 0, len(p.Flows))
+	for id := range p.Flows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// OutgoingFlows returns the sequence flows leaving the element with the
+// given ID, ordered by flow ID.
+func (p *Process) OutgoingFlows(id string) []*SequenceFlow {
+	var flows []*SequenceFlow
+	for _, fid := range p.FlowIDs() {
+		if flow := p.Flows[fid]; flow.Source == id {
+			flows = append(flows, flow)
+		}
+	}
+	return flows
+}
+
+// Process returns the process with the given ID.
+func (d *Definitions) Process(id string) (*Process, bool) {
+	for _, p := range d.Processes {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// ParseFile parses the BPMN file at path.
+func ParseFile(path string) (*Definitions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	defs, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	defs.File = filepath.Base(path)
+	return defs, nil
+}
+
+// ParseDir parses every *.bpmn file in dir.
+func ParseDir(dir string) ([]*Definitions, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.bpmn"))
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]*Definitions, 0, len(files))
+	for _, path := range files {
+		defs, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, defs)
+	}
+	return all, nil
+}
+
+// Parse reads a BPMN document. Elements of embedded sub-processes are
+// flattened into their enclosing process.
+func Parse(r io.Reader) (*Definitions, error) {
+	root, err := readTree(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := &Definitions{
+		Errors:   make(map[string]*Error),
+		Messages: make(map[string]string),
+	}
+	for _, child := range root.children {
+		switch child.local() {
+		case "error":
+			e := &Error{ID: child.attr("id"), Name: child.attr("name"), Code: child.attr("errorCode")}
+			if e.Code == "" {
+				e.Code = e.Name
+			}
+			defs.Errors[e.ID] = e
+		case "message":
+			defs.Messages[child.attr("id")] = child.attr("name")
+		case "process":
+			p := &Process{
+				ID:       child.attr("id"),
+				Name:     child.attr("name"),
+				Elements: make(map[string]*Element),
+				Flows:    make(map[string]*SequenceFlow),
+			}
+			p.collect(child)
+			defs.Processes = append(defs.Processes, p)
+		}
+	}
+	return defs, nil
+}
+
+func (p *Process) collect(parent *node) {
+	for _, n := range parent.children {
+		kind := n.local()
+		switch {
+		case kind == "sequenceFlow":
+			flow := &SequenceFlow{
+				ID:     n.attr("id"),
+				Source: n.attr("sourceRef"),
+				Target: n.attr("targetRef"),
+			}
+			if cond := n.child("conditionExpression"); cond != nil {
+				flow.Condition = strings.TrimSpace(cond.text)
+			}
+			p.Flows[flow.ID] = flow
+
+		case flowNodeKinds[kind]:
+			el := parseElement(n)
+			p.Elements[el.ID] = el
+			p.Order = append(p.Order, el.ID)
+			if kind == "subProcess" {
+				p.collect(n)
+			}
+		}
+	}
+}
+
+func parseElement(n *node) *Element {
+	el := &Element{
+		ID:             n.attr("id"),
+		Name:           n.attr("name"),
+		Kind:           n.local(),
+		DefaultFlow:    n.attr("default"),
+		AttachedTo:     n.attr("attachedToRef"),
+		CancelActivity: n.attr("cancelActivity") != "false",
+		Headers:        make(map[string]string),
+	}
+
+	for _, c := range n.children {
+		switch c.local() {
+		case "incoming":
+			el.Incoming = append(el.Incoming, strings.TrimSpace(c.text))
+		case "outgoing":
+			el.Outgoing = append(el.Outgoing, strings.TrimSpace(c.text))
+		case "extensionElements":
+			el.parseExtensions(c)
+		case "errorEventDefinition":
+			el.EventKind = EventError
+			el.ErrorRef = c.attr("errorRef")
+		case "messageEventDefinition":
+			el.EventKind = EventMessage
+			el.MessageRef = c.attr("messageRef")
+		case "timerEventDefinition":
+			el.EventKind = EventTimer
+			if d := c.child("timeDuration"); d != nil {
+				el.TimerDuration = strings.TrimSpace(d.text)
+			}
+		}
+	}
+	return el
+}
+
+func (el *Element) parseExtensions(ext *node) {
+	for _, c := range ext.children {
+		if c.name.Space != ZeebeNamespace {
+			continue
+		}
+		switch c.local() {
+		case "taskDefinition":
+			el.TaskType = strings.TrimSpace(c.attr("type"))
+			if retries, err := strconv.Atoi(c.attr("retries")); err == nil {
+				el.Retries = retries
+			}
+		case "taskHeaders":
+			for _, h := range c.children {
+				el.Headers[h.attr("key")] = h.attr("value")
+			}
+		case "ioMapping":
+			for _, m := range c.children {
+				mapping := Mapping{Source: m.attr("source"), Target: m.attr("target")}
+				if m.local() == "input" {
+					el.Inputs = append(el.Inputs, mapping)
+				} else if m.local() == "output" {
+					el.Outputs = append(el.Outputs, mapping)
+				}
+			}
+		case "calledElement":
+			el.CalledProcessID = c.attr("processId")
+			el.PropagateParent = c.attr("propagateAllParentVariables") != "false"
+			el.PropagateChild = c.attr("propagateAllChildVariables") != "false"
+		}
+	}
+}
+
+// node is a generic XML element used while building the model.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+}
+
+func (n *node) local() string {
+	return n.name.Local
+}
+
+func (n *node) attr(local string) string {
+	for _, a := range n.attrs {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *node) child(local string) *node {
+	for _, c := range n.children {
+		if c.local() == local {
+			return c
+		}
+	}
+	return nil
+}
+
+// readTree decodes the document into a node tree rooted at bpmn:definitions.
+func readTree(r io.Reader) (*node, error) {
+	dec := xml.NewDecoder(r)
+	var stack []*node
+	var root *node
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+
+	if root == nil || root.local() != "definitions" {
+		return nil, fmt.Errorf("not a BPMN document: missing definitions element")
+	}
+	return root, nil
+}
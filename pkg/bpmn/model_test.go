@@ -0,0 +1,128 @@
+// pkg/bpmn/model_test.go
+package bpmn
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T) (*Definitions, *Process) {
+	defs, err := ParseFile(filepath.Join("testdata", "model.bpmn"))
+	require.NoError(t, err)
+	require.Len(t, defs.Processes, 1)
+	return defs, defs.Processes[0]
+}
+
+func TestParseFile_Definitions(t *testing.T) {
+	defs, p := parseFixture(t)
+
+	assert.Equal(t, "model.bpmn", defs.File)
+	assert.Equal(t, &Error{ID: "Error_Search", Name: "Search Failed", Code: "SEARCH_FAILED"}, defs.Errors["Error_Search"])
+	assert.Equal(t, "NAME_ONLY", defs.Errors["Error_NameOnly"].Code, "the name stands in for a missing errorCode")
+	assert.Equal(t, map[string]string{"Message_Cancel": "application-cancelled"}, defs.Messages)
+
+	assert.Equal(t, "model", p.ID)
+	assert.Equal(t, "Model Process", p.Name)
+	found, ok := defs.Process("model")
+	assert.True(t, ok)
+	assert.Same(t, p, found)
+	_, ok = defs.Process("missing")
+	assert.False(t, ok)
+}
+
+func TestParseFile_Elements(t *testing.T) {
+	_, p := parseFixture(t)
+
+	assert.Equal(t, []string{
+		"Start", "Search", "Search_Failed", "Search_Slow", "HasResults",
+		"Review", "Apply", "Cancelled", "End",
+	}, p.Order, "sub-process elements are flattened in document order")
+
+	search := p.Elements["Search"]
+	assert.Equal(t, KindServiceTask, search.Kind)
+	assert.Equal(t, "query-elasticsearch", search.TaskType, "only zeebe:taskDefinition counts, trimmed")
+	assert.Equal(t, 4, search.Retries)
+	assert.Equal(t, map[string]string{"timeout": "PT15S", "index": "franchises"}, search.Headers)
+	assert.Equal(t, []Mapping{{Source: "=filters", Target: "query"}}, search.Inputs)
+	assert.Equal(t, []Mapping{{Source: "=results", Target: "franchises"}}, search.Outputs)
+	assert.Equal(t, []string{"Flow_ToSearch"}, search.Incoming)
+	assert.Equal(t, []string{"Flow_ToGateway"}, search.Outgoing)
+	assert.Equal(t, "Search (Search)", search.Label())
+
+	failed := p.Elements["Search_Failed"]
+	assert.Equal(t, KindBoundaryEvent, failed.Kind)
+	assert.Equal(t, EventError, failed.EventKind)
+	assert.Equal(t, "Error_Search", failed.ErrorRef)
+	assert.Equal(t, "Search", failed.AttachedTo)
+	assert.True(t, failed.CancelActivity)
+	assert.Equal(t, "Search_Failed", failed.Label())
+
+	slow := p.Elements["Search_Slow"]
+	assert.Equal(t, EventTimer, slow.EventKind)
+	assert.Equal(t, "PT30S", slow.TimerDuration)
+	assert.False(t, slow.CancelActivity)
+
+	assert.Equal(t, "Flow_NoResults", p.Elements["HasResults"].DefaultFlow)
+
+	apply := p.Elements["Apply"]
+	assert.Equal(t, KindCallActivity, apply.Kind)
+	assert.Equal(t, "application-processing", apply.CalledProcessID)
+	assert.True(t, apply.PropagateParent, "propagation defaults to true")
+	assert.False(t, apply.PropagateChild)
+
+	cancelled := p.Elements["Cancelled"]
+	assert.Equal(t, EventMessage, cancelled.EventKind)
+	assert.Equal(t, "Message_Cancel", cancelled.MessageRef)
+	assert.Equal(t, 0, p.Elements["Start"].Retries)
+}
+
+func TestParseFile_Flows(t *testing.T) {
+	_, p := parseFixture(t)
+
+	assert.Equal(t, []string{
+		"Flow_FailedToEnd", "Flow_NoResults", "Flow_Results", "Flow_ReviewToEnd",
+		"Flow_SlowToEnd", "Flow_ToGateway", "Flow_ToSearch",
+	}, p.FlowIDs())
+	assert.Equal(t, &SequenceFlow{
+		ID:        "Flow_Results",
+		Source:    "HasResults",
+		Target:    "Review",
+		Condition: "=count(franchises) > 0",
+	}, p.Flows["Flow_Results"])
+
+	var outgoing []string
+	for _, flow := range p.OutgoingFlows("HasResults") {
+		outgoing = append(outgoing, flow.ID)
+	}
+	assert.Equal(t, []string{"Flow_NoResults", "Flow_Results"}, outgoing)
+
+	var boundaries []string
+	for _, el := range p.Boundaries("Search") {
+		boundaries = append(boundaries, el.ID)
+	}
+	assert.Equal(t, []string{"Search_Failed", "Search_Slow"}, boundaries)
+	assert.Empty(t, p.Boundaries("End"))
+}
+
+func TestParse_NotBPMN(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<?xml version="1.0"?><html><body/></html>`))
+	assert.ErrorContains(t, err, "missing definitions element")
+
+	_, err = Parse(strings.NewReader(`<bpmn:definitions`))
+	assert.Error(t, err)
+}
+
+func TestParseDir(t *testing.T) {
+	all, err := ParseDir("testdata")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "model.bpmn", all[0].File)
+
+	all, err = ParseDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -0,0 +1,80 @@
+// pkg/bpmn/tasktypes.go
+package bpmn
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ZeebeNamespace is the XML namespace of Zeebe's BPMN extension elements.
+const ZeebeNamespace = "http://camunda.org/schema/zeebe/1.0"
+
+// TaskTypes reads every zeebe:taskDefinition type in a BPMN document.
+// Task types given as FEEL expressions (starting with "=") are skipped
+// because they are only known at runtime.
+func TaskTypes(r io.Reader) ([]string, error) {
+	seen := make(map[string]bool)
+	var taskTypes []string
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		el, ok := tok.(xml.StartElement)
+		if !ok || el.Name.Space != ZeebeNamespace || el.Name.Local != "taskDefinition" {
+			continue
+		}
+		for _, attr := range el.Attr {
+			if attr.Name.Local != "type" {
+				continue
+			}
+			taskType := strings.TrimSpace(attr.Value)
+			if taskType == "" || strings.HasPrefix(taskType, "=") || seen[taskType] {
+				continue
+			}
+			seen[taskType] = true
+			taskTypes = append(taskTypes, taskType)
+		}
+	}
+
+	sort.Strings(taskTypes)
+	return taskTypes, nil
+}
+
+// ScanTaskTypes reads every *.bpmn file in dir and returns the task types
+// they use, each mapped to the files that reference it.
+func ScanTaskTypes(dir string) (map[string][]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.bpmn"))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string][]string)
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		taskTypes, err := TaskTypes(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		for _, taskType := range taskTypes {
+			usage[taskType] = append(usage[taskType], filepath.Base(path))
+		}
+	}
+	return usage, nil
+}
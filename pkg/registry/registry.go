@@ -15,3 +15,50 @@ func LoadRegistry(path string) (*ActivityRegistry, error) {
 	err = json.Unmarshal(data, &reg)
 	return &reg, err
 }
+
+// JobTypes returns every task type name the activity answers to: its ID, its
+// declared task type and its aliases, without duplicates.
+func (a Activity) JobTypes() []string {
+	seen := make(map[string]bool, len(a.Aliases)+2)
+	var jobTypes []string
+	for _, name := range append([]string{a.ID, a.TaskType}, a.Aliases...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		jobTypes = append(jobTypes, name)
+	}
+	return jobTypes
+}
+
+// Resolve returns the activity serving a BPMN task type, matched against
+// activity IDs, declared task types and aliases.
+func (r *ActivityRegistry) Resolve(taskType string) (Activity, bool) {
+	for _, activity := range r.Activities {
+		for _, name := range activity.JobTypes() {
+			if name == taskType {
+				return activity, true
+			}
+		}
+	}
+	return Activity{}, false
+}
+
+// DuplicateJobTypes returns the task type names claimed by more than one
+// activity, each mapped to the IDs of the activities claiming it.
+func (r *ActivityRegistry) DuplicateJobTypes() map[string][]string {
+	owners := make(map[string][]string)
+	for _, activity := range r.Activities {
+		for _, name := range activity.JobTypes() {
+			owners[name] = append(owners[name], activity.ID)
+		}
+	}
+
+	duplicates := make(map[string][]string)
+	for name, ids := range owners {
+		if len(ids) > 1 {
+			duplicates[name] = ids
+		}
+	}
+	return duplicates
+}
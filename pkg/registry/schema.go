@@ -14,6 +14,7 @@ type Activity struct {
 	Category             string                 `json:"category"`
 	Version              string                 `json:"version"`
 	TaskType             string                 `json:"taskType"`
+	Aliases              []string               `json:"aliases,omitempty"` // other BPMN task types served by this activity
 	ImplementationStatus string                 `json:"implementationStatus"`
 	InputSchema          map[string]interface{} `json:"inputSchema"`
 	OutputSchema         map[string]interface{} `json:"outputSchema"`
@@ -0,0 +1,276 @@
+// test/flows/ai_query_test.go
+package flows
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"camunda-workers/internal/common/camunda/testkit"
+
+	buildresponse "camunda-workers/internal/workers/infrastructure/build-response"
+	callbacktobff "camunda-workers/internal/workers/infrastructure/callback-to-bff"
+	selecttemplate "camunda-workers/internal/workers/infrastructure/select-template"
+
+	"github.com/camunda/zeebe/clients/go/v8/pkg/entities"
+	"github.com/camunda/zeebe/clients/go/v8/pkg/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+const (
+	aiQueryProcess = "ai-query-workflow"
+	guardedProcess = "ai-query-guarded"
+)
+
+// synthesis is what llm-synthesis completes with. The CheckLLMSuccess gateway
+// routes on llmResponse: an empty answer takes the LLM timeout path.
+func synthesis(answer string) map[string]interface{} {
+	return map[string]interface{}{
+		"llmResponse": answer,
+		"confidence":  0.9,
+		"sources":     []interface{}{"internal:fr-1"},
+	}
+}
+
+// subscription is what validate-subscription completes with.
+func subscription(withinBudget bool) map[string]interface{} {
+	return map[string]interface{}{
+		"isValid":      true,
+		"tierLevel":    "premium",
+		"withinBudget": withinBudget,
+	}
+}
+
+// newAIQueryEngine deploys ai_query.bpmn and the guarded wrapper, stubs the
+// subscription check and the AI tasks and runs select-template, build-response and callback-to-bff as
+// the real workers.
+func newAIQueryEngine(t *testing.T, bffURL string) *testkit.Engine {
+	t.Helper()
+
+	engine := testkit.NewEngine()
+	require.NoError(t, engine.DeployFiles("../../bpmn/ai_query.bpmn", "testdata/ai_query_guarded.bpmn"))
+
+	engine.Register("validate-subscription", testkit.Completes(subscription(true)))
+	engine.Register("parse-user-intent", testkit.Completes(map[string]interface{}{
+		"intentAnalysis": map[string]interface{}{"primaryIntent": "investment", "confidence": 0.95},
+		"dataSources":    []interface{}{"internal", "web"},
+		"entities":       []interface{}{map[string]interface{}{"type": "franchise_name", "value": "Bean There"}},
+	}))
+	engine.Register("query-internal-data", testkit.Completes(map[string]interface{}{
+		"internalData": map[string]interface{}{"franchises": []interface{}{"fr-1"}},
+	}))
+	engine.Register("enrich-web-search", testkit.Completes(map[string]interface{}{
+		"webData": map[string]interface{}{"sources": []interface{}{}, "summary": "no web results"},
+	}))
+	engine.Register("llm-synthesis", testkit.Completes(synthesis("Bean There needs $120k to start.")))
+
+	for _, w := range buildWorkers(t, bffURL, selecttemplate.TaskType, buildresponse.TaskType, callbacktobff.TaskType) {
+		engine.RegisterWorker(w)
+	}
+	return engine
+}
+
+// aiQueryVariables are the start variables sent by the BFF. templateId and
+// data stand in for the mappings ai_query.bpmn does not define between
+// select-template, llm-synthesis and build-response.
+func aiQueryVariables() map[string]interface{} {
+	return map[string]interface{}{
+		"requestId":        "req-ai-1",
+		"userId":           "user-1",
+		"question":         "How much does Bean There cost?",
+		"subscriptionTier": "premium",
+		"templateType":     "ai-response",
+		"templateId":       "ai-detailed",
+		"data": map[string]interface{}{
+			"answer":  "Bean There needs $120k to start.",
+			"sources": []interface{}{"internal:fr-1"},
+		},
+	}
+}
+
+func newBFF(t *testing.T, received *callbacktobff.CallbackPayload) *httptest.Server {
+	t.Helper()
+	bff := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if received == nil {
+			t.Error("BFF must not be called")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(bff.Close)
+	return bff
+}
+
+// ==========================
+// Replay Tests
+// ==========================
+
+func TestAIQuery_BuildsResponseEndToEnd(t *testing.T) {
+	var received callbacktobff.CallbackPayload
+	engine := newAIQueryEngine(t, newBFF(t, &received).URL)
+
+	inst, err := engine.Run(aiQueryProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State, "incident: %+v", inst.Incident)
+
+	// Both parallel branches ran before the join released LLMSynthesis.
+	assert.True(t, inst.Visited("QueryInternalData"))
+	assert.True(t, inst.Visited("EnrichWithWebSearch"))
+	assert.True(t, inst.Visited("AIQueryComplete"))
+	assert.False(t, inst.Visited("LLMTimeoutEnd"))
+
+	selected, ok := inst.Step("SelectResponseTemplate")
+	require.True(t, ok)
+	assert.Equal(t, "ai-detailed", selected.Commands[0].Variables["selectedTemplateId"])
+
+	build, ok := inst.Step("BuildResponse")
+	require.True(t, ok)
+	require.Equal(t, testkit.Complete, build.Commands[0].Kind)
+	response, _ := build.Commands[0].Variables["response"].(map[string]interface{})
+	assert.Equal(t, "req-ai-1", response["requestId"])
+	assert.Equal(t, "success", response["status"])
+	data, _ := response["data"].(map[string]interface{})
+	assert.Equal(t, "Bean There needs $120k to start.", data["answer"])
+
+	assert.Equal(t, "req-ai-1", received.RequestId)
+	assert.Equal(t, true, inst.Variables["callbackDelivered"])
+}
+
+func TestAIQuery_ExceededBudgetStopsBeforeParsingIntent(t *testing.T) {
+	engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+	engine.Register("validate-subscription", testkit.Completes(subscription(false)))
+
+	// Inside ai_query.bpmn nothing catches the error end event.
+	inst, err := engine.Run(aiQueryProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateIncident, inst.State)
+	assert.Equal(t, "AIBudgetExceededEnd", inst.Incident.ElementID)
+	assert.True(t, inst.Visited("ValidateSubscription"))
+	assert.False(t, inst.Visited("ParseUserIntent"))
+	assert.False(t, inst.Visited("LLMSynthesis"))
+}
+
+func TestAIQuery_InvalidSubscriptionIsRethrownToCaller(t *testing.T) {
+	engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+	engine.Register("validate-subscription", testkit.Throws("SUBSCRIPTION_INVALID", "no subscription for user-1"))
+
+	inst, err := engine.Run(guardedProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State, "incident: %+v", inst.Incident)
+	assert.Equal(t, []string{"Start", "CallAIQuery", "CatchSubscriptionInvalid", "SubscriptionRejected"}, inst.Path)
+
+	require.Len(t, inst.Children, 1)
+	child := inst.Children[0]
+	assert.True(t, child.Visited("CatchSubscriptionInvalid"))
+	assert.True(t, child.Visited("SubscriptionInvalidEnd"))
+	assert.False(t, child.Visited("ParseUserIntent"))
+}
+
+func TestAIQuery_ExpiredSubscriptionEndsInSubscriptionError(t *testing.T) {
+	engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+	engine.Register("validate-subscription", testkit.Throws("SUBSCRIPTION_EXPIRED", "subscription of user-1 expired"))
+
+	// The task's boundary catches the error; nothing above ai_query.bpmn
+	// catches the rethrown one.
+	inst, err := engine.Run(aiQueryProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateIncident, inst.State)
+	assert.Equal(t, "SubscriptionExpiredEnd", inst.Incident.ElementID)
+	assert.True(t, inst.Visited("CatchSubscriptionExpired"))
+	assert.False(t, inst.Visited("CheckAIBudget"))
+	assert.False(t, inst.Visited("ParseUserIntent"))
+}
+
+func TestAIQuery_LLMTimeoutTakesBoundaryPath(t *testing.T) {
+	engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+	engine.Register("llm-synthesis", testkit.Throws("LLM_TIMEOUT", "GenAI did not answer"))
+
+	inst, err := engine.Run(guardedProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State, "incident: %+v", inst.Incident)
+	assert.Equal(t, []string{"Start", "CallAIQuery", "CatchLLMTimeout", "LLMTimedOut"}, inst.Path)
+
+	require.Len(t, inst.Children, 1)
+	child := inst.Children[0]
+	assert.Equal(t, testkit.StateTerminated, child.State)
+	assert.False(t, child.Visited("SelectResponseTemplate"))
+}
+
+func TestAIQuery_UnsuccessfulSynthesisEndsInLLMTimeoutError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output map[string]interface{}
+	}{
+		{"empty answer", synthesis("")},
+		{"no answer", map[string]interface{}{"confidence": 0.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+			engine.Register("llm-synthesis", testkit.Completes(tt.output))
+
+			// Inside ai_query.bpmn nothing catches the error end event.
+			inst, err := engine.Run(aiQueryProcess, aiQueryVariables())
+			require.NoError(t, err)
+			require.Equal(t, testkit.StateIncident, inst.State)
+			assert.Equal(t, "LLMTimeoutEnd", inst.Incident.ElementID)
+
+			// Called from the wrapper, the error reaches its boundary event.
+			inst, err = engine.Run(guardedProcess, aiQueryVariables())
+			require.NoError(t, err)
+			require.Equal(t, testkit.StateCompleted, inst.State)
+			assert.True(t, inst.Visited("LLMTimedOut"))
+			assert.True(t, inst.Children[0].Visited("LLMTimeoutEnd"))
+		})
+	}
+}
+
+func TestAIQuery_SlowSynthesisHitsDeadlineTimer(t *testing.T) {
+	engine := newAIQueryEngine(t, newBFF(t, nil).URL)
+	engine.SetLatency("llm-synthesis", 90*time.Second)
+	start := engine.Clock.Now()
+
+	inst, err := engine.Run(guardedProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State)
+	assert.True(t, inst.Visited("DeadlineExceeded"))
+	assert.False(t, inst.Visited("Answered"))
+	assert.Equal(t, testkit.StateTerminated, inst.Children[0].State)
+	assert.Equal(t, 90*time.Second, engine.Clock.Now().Sub(start))
+}
+
+func TestAIQuery_FailedJobIsRetried(t *testing.T) {
+	var received callbacktobff.CallbackPayload
+	engine := newAIQueryEngine(t, newBFF(t, &received).URL)
+
+	attempts := 0
+	fail := testkit.Fails("web search unavailable")
+	complete := testkit.Completes(map[string]interface{}{"webData": map[string]interface{}{}})
+	engine.Register("enrich-web-search", func(client worker.JobClient, job entities.Job) {
+		attempts++
+		if attempts == 1 {
+			fail(client, job)
+			return
+		}
+		complete(client, job)
+	})
+
+	inst, err := engine.Run(aiQueryProcess, aiQueryVariables())
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State)
+
+	step, ok := inst.Step("EnrichWithWebSearch")
+	require.True(t, ok)
+	assert.Len(t, step.Jobs, 2)
+	assert.Equal(t, int32(testkit.DefaultRetries-1), step.Jobs[1].Retries)
+}
@@ -0,0 +1,222 @@
+// test/flows/discovery_test.go
+package flows
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/logger"
+	"camunda-workers/internal/common/validation"
+	"camunda-workers/pkg/registry"
+
+	buildresponse "camunda-workers/internal/workers/infrastructure/build-response"
+	callbacktobff "camunda-workers/internal/workers/infrastructure/callback-to-bff"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+// discoveryTail is the end of discovery.bpmn: the response is built from the
+// ranked results and delivered to the BFF.
+var discoveryTail = []string{buildresponse.TaskType, callbacktobff.TaskType}
+
+func writeTemplates(t *testing.T) string {
+	t.Helper()
+	templates := map[string]interface{}{
+		"templates": []map[string]interface{}{{
+			"id":      "discovery-results",
+			"type":    "franchise-list",
+			"version": "1.0.0",
+			"template": map[string]interface{}{
+				"franchises": "{{rankedFranchises}}",
+				"query":      "{{query}}",
+			},
+		}, {
+			"id":      "ai-detailed",
+			"type":    "ai-response",
+			"version": "1.0.0",
+			"template": map[string]interface{}{
+				"answer":  "{{answer}}",
+				"sources": "{{sources}}",
+			},
+		}},
+	}
+	data, err := json.Marshal(templates)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "templates.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+// testConfig is the worker configuration shared by the flow tests.
+func testConfig(t *testing.T, bffURL string) *config.Config {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.App.Version = "test"
+	cfg.Template.RegistryPath = writeTemplates(t)
+	cfg.APIs.BFF.CallbackURL = bffURL
+	cfg.APIs.BFF.Timeout = 2000
+	cfg.Registry.InputValidation = string(validation.ModeStrict)
+	cfg.Registry.OutputValidation = string(validation.ModeStrict)
+	return cfg
+}
+
+// buildWorkers builds the real workers through their registered factories,
+// with schema validation from the activity registry.
+func buildWorkers(t *testing.T, bffURL string, taskTypes ...string) []camunda.Worker {
+	t.Helper()
+	return buildWorkersWithConfig(t, testConfig(t, bffURL), taskTypes...)
+}
+
+func buildWorkersWithConfig(t *testing.T, cfg *config.Config, taskTypes ...string) []camunda.Worker {
+	t.Helper()
+	return buildWorkersWithDeps(t, testDeps(t, cfg), taskTypes...)
+}
+
+// testDeps are the dependencies the flow tests build workers with: no
+// database connections, and the activity registry for validation.
+func testDeps(t *testing.T, cfg *config.Config) *camunda.Dependencies {
+	t.Helper()
+
+	activities, err := registry.LoadRegistry("../../configs/activity-registry.json")
+	require.NoError(t, err)
+
+	return &camunda.Dependencies{
+		Config:   cfg,
+		Logger:   logger.NewTestLogger(t),
+		Registry: activities,
+	}
+}
+
+func buildWorkersWithDeps(t *testing.T, deps *camunda.Dependencies, taskTypes ...string) []camunda.Worker {
+	t.Helper()
+
+	workers := make([]camunda.Worker, 0, len(taskTypes))
+	for _, taskType := range taskTypes {
+		w, err := camunda.BuildWorker(taskType, deps)
+		require.NoError(t, err)
+		workers = append(workers, w)
+	}
+	return workers
+}
+
+// newRunner registers the workers of the discovery tail on a testkit runner.
+func newRunner(t *testing.T, bffURL string) *testkit.Runner {
+	t.Helper()
+
+	runner := testkit.NewRunner()
+	runner.ProcessID = "discovery"
+	for _, w := range buildWorkers(t, bffURL, discoveryTail...) {
+		runner.RegisterWorker(w)
+	}
+	return runner
+}
+
+func discoveryVariables() map[string]interface{} {
+	return map[string]interface{}{
+		"requestId":  "req-123",
+		"templateId": "discovery-results",
+		"query":      "coffee",
+		"data": map[string]interface{}{
+			"rankedFranchises": []interface{}{
+				map[string]interface{}{"id": "fr-1", "name": "Bean There", "finalScore": 0.92},
+			},
+			"query": "coffee",
+		},
+	}
+}
+
+// ==========================
+// Flow Tests
+// ==========================
+
+func TestDiscovery_ResponseDeliveredToBFF(t *testing.T) {
+	var received callbacktobff.CallbackPayload
+	bff := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer bff.Close()
+
+	result, err := newRunner(t, bff.URL).Run(discoveryVariables(), discoveryTail...)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident=%+v thrown=%+v", result.Incident, result.Thrown)
+	require.Len(t, result.Steps, 2)
+
+	// build-response's output reached callback-to-bff through the process variables.
+	assert.Equal(t, "req-123", received.RequestId)
+	assert.Equal(t, "success", received.Response["status"])
+	data, _ := received.Response["data"].(map[string]interface{})
+	assert.Equal(t, "coffee", data["query"])
+
+	assert.Equal(t, true, result.Variables["callbackDelivered"])
+	assert.Contains(t, result.Variables, "response")
+	assert.Equal(t, "coffee", result.Variables["query"], "untouched variables survive the merge")
+}
+
+func TestDiscovery_BFFUnavailableIsRetried(t *testing.T) {
+	var calls int32
+	bff := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer bff.Close()
+
+	result, err := newRunner(t, bff.URL).Run(discoveryVariables(), discoveryTail...)
+	require.NoError(t, err)
+	require.True(t, result.Completed())
+
+	callback := result.Steps[1]
+	require.Len(t, callback.Commands, 2)
+	assert.Equal(t, testkit.Fail, callback.Commands[0].Kind)
+	assert.Equal(t, int32(testkit.DefaultRetries-1), callback.Commands[0].Retries)
+	assert.Positive(t, callback.Backoff)
+	assert.Equal(t, testkit.Complete, callback.Commands[1].Kind)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDiscovery_BFFRejectionThrowsBPMNError(t *testing.T) {
+	bff := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer bff.Close()
+
+	result, err := newRunner(t, bff.URL).Run(discoveryVariables(), discoveryTail...)
+	require.NoError(t, err)
+	require.NotNil(t, result.Thrown)
+	assert.Equal(t, "BFF_CALLBACK_REJECTED", result.Thrown.ErrorCode)
+	assert.NotContains(t, result.Variables, "callbackDelivered")
+}
+
+func TestDiscovery_UnknownTemplateThrowsBeforeCallback(t *testing.T) {
+	bff := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("BFF must not be called when the response cannot be built")
+	}))
+	defer bff.Close()
+
+	vars := discoveryVariables()
+	vars["templateId"] = "no-such-template"
+
+	result, err := newRunner(t, bff.URL).Run(vars, discoveryTail...)
+	require.NoError(t, err)
+	require.NotNil(t, result.Thrown)
+	assert.Equal(t, "TEMPLATE_NOT_FOUND", result.Thrown.ErrorCode)
+	assert.Len(t, result.Steps, 1)
+}
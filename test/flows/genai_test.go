@@ -0,0 +1,541 @@
+// test/flows/genai_test.go
+package flows
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"camunda-workers/internal/common/camunda"
+	"camunda-workers/internal/common/camunda/testkit"
+	"camunda-workers/internal/common/config"
+	"camunda-workers/internal/common/conversation"
+	"camunda-workers/internal/common/database"
+	"camunda-workers/internal/common/genaistub"
+	"camunda-workers/internal/common/retrieval"
+
+	enrichwebsearch "camunda-workers/internal/workers/ai-conversation/enrich-web-search"
+	llmsynthesis "camunda-workers/internal/workers/ai-conversation/llm-synthesis"
+	parseuserintent "camunda-workers/internal/workers/ai-conversation/parse-user-intent"
+	buildresponse "camunda-workers/internal/workers/infrastructure/build-response"
+	callbacktobff "camunda-workers/internal/workers/infrastructure/callback-to-bff"
+	selecttemplate "camunda-workers/internal/workers/infrastructure/select-template"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================
+// Test Helper Functions
+// ==========================
+
+const stubQuestion = `How much does "Bean There" cost in Austin?`
+
+func newGenAIStub(t *testing.T, cfg genaistub.Config) (*genaistub.Server, string) {
+	t.Helper()
+	if cfg.Seed == 0 {
+		cfg.Seed = 1
+	}
+	stub := genaistub.New(cfg)
+	srv := httptest.NewServer(stub.Handler())
+	t.Cleanup(srv.Close)
+	return stub, srv.URL
+}
+
+// genaiConfig points the AI workers at the stub. A non-zero timeoutMs bounds
+// each job through the pipeline's timeout middleware.
+func genaiConfig(t *testing.T, genaiURL, bffURL string, timeoutMs int) *config.Config {
+	t.Helper()
+	cfg := testConfig(t, bffURL)
+	cfg.APIs.GenAI.BaseURL = genaiURL
+	if timeoutMs > 0 {
+		cfg.Workers = map[string]config.WorkerConfig{
+			parseuserintent.TaskType: {Timeout: timeoutMs},
+			llmsynthesis.TaskType:    {Timeout: timeoutMs},
+		}
+	}
+	return cfg
+}
+
+func newGenAIRunner(t *testing.T, cfg *config.Config) *testkit.Runner {
+	t.Helper()
+	runner := testkit.NewRunner()
+	runner.ProcessID = aiQueryProcess
+	for _, w := range buildWorkersWithConfig(t, cfg, parseuserintent.TaskType, llmsynthesis.TaskType) {
+		runner.RegisterWorker(w)
+	}
+	return runner
+}
+
+func synthesisVariables() map[string]interface{} {
+	return map[string]interface{}{
+		"question": stubQuestion,
+		"intent":   map[string]interface{}{"primaryIntent": "cost_inquiry", "confidence": 0.9},
+		"internalData": map[string]interface{}{
+			"franchise":  "Bean There",
+			"investment": "$120k",
+		},
+		"webData": map[string]interface{}{
+			"sources": []interface{}{
+				map[string]interface{}{"title": "Bean There FDD 2024", "url": "https://example.com/fdd"},
+			},
+		},
+	}
+}
+
+// ==========================
+// Rule-Based Response Tests
+// ==========================
+
+func TestGenAIStub_ParseIntentFromKeywordRules(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(map[string]interface{}{"question": stubQuestion}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	analysis, _ := result.Variables["intentAnalysis"].(map[string]interface{})
+	assert.Equal(t, "cost_inquiry", analysis["primaryIntent"])
+	assert.Equal(t, 0.9, analysis["confidence"])
+	assert.ElementsMatch(t, []interface{}{
+		map[string]interface{}{"type": "franchise_name", "value": "Bean There"},
+		map[string]interface{}{"type": "location", "value": "austin"},
+	}, result.Variables["entities"])
+	// The stub leaves dataSources to the worker, which derives them.
+	assert.Equal(t, []interface{}{"internal_db", "search_index"}, result.Variables["dataSources"])
+}
+
+func TestGenAIStub_SynthesisEchoesInternalData(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	text, _ := result.Variables["llmResponse"].(string)
+	assert.Contains(t, text, stubQuestion)
+	assert.Contains(t, text, "investment=$120k")
+	assert.Contains(t, text, "Bean There FDD 2024")
+	assert.Equal(t, 0.85, result.Variables["confidence"])
+	assert.Equal(t, []interface{}{"internal:franchise", "internal:investment", "https://example.com/fdd"}, result.Variables["sources"])
+
+	requests := stub.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, genaistub.PathGenerate, requests[0].Path)
+}
+
+func TestGenAIStub_SynthesisCitesRetrievedPassages(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	vars := synthesisVariables()
+	vars["passages"] = []interface{}{
+		map[string]interface{}{"id": "fdd:7#2", "franchiseId": "f-1", "title": "Bean There FDD 2024", "text": "The franchise fee is $30,000."},
+	}
+	result, err := runner.Run(vars, llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	text, _ := result.Variables["llmResponse"].(string)
+	assert.Contains(t, text, "[fdd:7#2] The franchise fee is $30,000.")
+	assert.Equal(t, []interface{}{"internal:franchise", "internal:investment", "fdd:7#2", "https://example.com/fdd"}, result.Variables["sources"])
+}
+
+func TestGenAIStub_GroundedSynthesisCitesInternalData(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{})
+	cfg := genaiConfig(t, url, "", 0)
+	cfg.APIs.LLM.Grounding = true
+	runner := newGenAIRunner(t, cfg)
+
+	result, err := runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	// The stub's echo of its input grounds its answer fully; the web source
+	// is cited for naming Bean There too.
+	assert.Equal(t, 0.85, result.Variables["confidence"])
+	assert.Equal(t, []interface{}{"internal:franchise", "https://example.com/fdd", "internal:investment"}, result.Variables["sources"])
+	grounding, _ := result.Variables["grounding"].(map[string]interface{})
+	assert.Equal(t, float64(1), grounding["score"])
+}
+
+func TestGenAIStub_GuardrailsKeepPIIFromTheService(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{})
+	cfg := genaiConfig(t, url, "", 0)
+	cfg.Guardrails.Enabled = true
+	runner := newGenAIRunner(t, cfg)
+
+	vars := synthesisVariables()
+	vars["question"] = "I'm jane.doe@example.com, what does Bean There cost?"
+	result, err := runner.Run(vars, parseuserintent.TaskType, llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	// The stub restates the question it received.
+	text, _ := result.Variables["llmResponse"].(string)
+	assert.Contains(t, text, "I'm [REDACTED_EMAIL], what does Bean There cost?")
+	requests := stub.Requests()
+	require.Len(t, requests, 2)
+	for _, r := range requests {
+		body, _ := json.Marshal(r.Body)
+		assert.NotContains(t, string(body), "jane.doe", r.Path)
+	}
+	decisions, _ := result.Variables["guardrails"].([]interface{})
+	assert.NotEmpty(t, decisions)
+}
+
+func TestGenAIStub_EmbedsForRetrieval(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{})
+	embedder, err := retrieval.NewEmbedder(genaiConfig(t, url, "", 0))
+	require.NoError(t, err)
+
+	texts := []string{"Bean There franchise fee", "bean there FRANCHISE fee", "Outlets in Austin"}
+	vectors, err := embedder.Embed(context.Background(), texts)
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	assert.Len(t, vectors[0], genaistub.EmbeddingDims)
+	assert.Equal(t, vectors[0], vectors[1], "embeddings ignore case")
+	assert.NotEqual(t, vectors[0], vectors[2])
+
+	requests := stub.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, genaistub.PathEmbed, requests[0].Path)
+}
+
+func TestGenAIStub_ScriptedResponseWins(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{Script: []genaistub.ScriptedResponse{{
+		Path:  genaistub.PathParseIntent,
+		Match: "bean there",
+		Body:  []byte(`{"intent":"franchise_inquiry","confidence":0.99,"entities":[],"dataSources":["internal_db"]}`),
+	}}})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(map[string]interface{}{"question": stubQuestion}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	analysis, _ := result.Variables["intentAnalysis"].(map[string]interface{})
+	assert.Equal(t, "franchise_inquiry", analysis["primaryIntent"])
+	assert.Equal(t, []interface{}{"internal_db"}, result.Variables["dataSources"])
+}
+
+// ==========================
+// Fault Injection Tests
+// ==========================
+
+func TestGenAIStub_ParseIntentRetriesInjectedError(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{Script: []genaistub.ScriptedResponse{{
+		Path:   genaistub.PathParseIntent,
+		Status: http.StatusServiceUnavailable,
+		Body:   []byte(`{"error":"overloaded"}`),
+		Times:  1,
+	}}})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(map[string]interface{}{"question": stubQuestion}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	// The worker retried within the same job.
+	require.Len(t, result.Steps[0].Jobs, 1)
+	requests := stub.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, http.StatusServiceUnavailable, requests[0].Status)
+	assert.Equal(t, stubQuestion, requests[1].Body["query"])
+}
+
+func TestGenAIStub_MalformedJSONFailsJobs(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{MalformedRate: 1}})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(map[string]interface{}{"question": stubQuestion}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	require.NotNil(t, result.Incident)
+	assert.Contains(t, result.Incident.ErrorMessage, "INTENT_PARSING_FAILED")
+	assert.Len(t, result.Steps[0].Jobs, testkit.DefaultRetries)
+
+	// An undecodable answer will not decode on a retry either.
+	result, err = runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.NotNil(t, result.Thrown)
+	assert.Equal(t, "LLM_SYNTHESIS_FAILED", result.Thrown.ErrorCode)
+	assert.Len(t, result.Steps[0].Jobs, 1)
+}
+
+func TestGenAIStub_RejectedSynthesisThrowsWithoutRetries(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusUnprocessableEntity} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			stub, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{ErrorRate: 1, ErrorStatus: status}})
+			runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+			result, err := runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+			require.NoError(t, err)
+			require.NotNil(t, result.Thrown)
+			assert.Equal(t, "LLM_SYNTHESIS_FAILED", result.Thrown.ErrorCode)
+			assert.Len(t, result.Steps[0].Jobs, 1, "the job is not retried")
+			assert.Len(t, stub.Requests(), 1, "nor is the request")
+		})
+	}
+}
+
+func TestGenAIStub_UnavailableSynthesisIsRetried(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable}})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 0))
+
+	result, err := runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.NotNil(t, result.Incident)
+	assert.Contains(t, result.Incident.ErrorMessage, "LLM_SYNTHESIS_FAILED")
+	assert.Len(t, result.Steps[0].Jobs, testkit.DefaultRetries)
+}
+
+func TestGenAIStub_LatencyTimesOutJobs(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{LatencyMs: 2000}})
+	runner := newGenAIRunner(t, genaiConfig(t, url, "", 100))
+	runner.MaxAttempts = 1
+
+	result, err := runner.Run(map[string]interface{}{"question": stubQuestion}, parseuserintent.TaskType)
+	require.Error(t, err, "the job is failed with retries left")
+	cmd := result.Steps[0].Commands[0]
+	require.Equal(t, testkit.Fail, cmd.Kind)
+	assert.Contains(t, cmd.ErrorMessage, "INTENT_API_TIMEOUT")
+
+	result, err = runner.Run(synthesisVariables(), llmsynthesis.TaskType)
+	require.Error(t, err)
+	cmd = result.Steps[0].Commands[0]
+	require.Equal(t, testkit.Fail, cmd.Kind)
+	assert.Contains(t, cmd.ErrorMessage, "LLM_TIMEOUT")
+}
+
+// ==========================
+// Replay Tests
+// ==========================
+
+func TestGenAIStub_AIQueryRunsOffline(t *testing.T) {
+	_, genaiURL := newGenAIStub(t, genaistub.Config{})
+	var received callbacktobff.CallbackPayload
+	cfg := genaiConfig(t, genaiURL, newBFF(t, &received).URL, 0)
+
+	engine := testkit.NewEngine()
+	require.NoError(t, engine.DeployFiles("../../bpmn/ai_query.bpmn"))
+	engine.Register("validate-subscription", testkit.Completes(subscription(true)))
+	engine.Register("query-internal-data", testkit.Completes(map[string]interface{}{
+		"internalData": map[string]interface{}{"franchise": "Bean There", "investment": "$120k"},
+	}))
+	engine.Register("enrich-web-search", testkit.Completes(map[string]interface{}{
+		"webData": map[string]interface{}{"sources": []interface{}{}, "summary": ""},
+	}))
+	for _, w := range buildWorkersWithConfig(t, cfg,
+		parseuserintent.TaskType, llmsynthesis.TaskType,
+		selecttemplate.TaskType, buildresponse.TaskType, callbacktobff.TaskType,
+	) {
+		engine.RegisterWorker(w)
+	}
+
+	// intent stands in for a mapping ai_query.bpmn lacks.
+	vars := aiQueryVariables()
+	vars["question"] = stubQuestion
+	vars["intent"] = map[string]interface{}{"primaryIntent": "cost_inquiry"}
+
+	inst, err := engine.Run(aiQueryProcess, vars)
+	require.NoError(t, err)
+	require.Equal(t, testkit.StateCompleted, inst.State, "incident: %+v", inst.Incident)
+
+	analysis, _ := inst.Variables["intentAnalysis"].(map[string]interface{})
+	assert.Equal(t, "cost_inquiry", analysis["primaryIntent"])
+	assert.Contains(t, inst.Variables["llmResponse"], "investment=$120k")
+	assert.Equal(t, 0.6, inst.Variables["confidence"])
+	assert.Equal(t, "req-ai-1", received.RequestId)
+}
+
+// ==========================
+// Conversation Memory Tests
+// ==========================
+
+func TestGenAIStub_FollowUpKeepsConversationContext(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{})
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	cfg := genaiConfig(t, url, "", 0)
+	cfg.Conversation.Store = conversation.StoreRedis
+	cfg.Conversation.HistoryTurns = 6
+	cfg.Conversation.TokenBudget = 2000
+	deps := testDeps(t, cfg)
+	deps.Redis = &database.RedisClient{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+
+	runner := testkit.NewRunner()
+	runner.ProcessID = aiQueryProcess
+	for _, w := range buildWorkersWithDeps(t, deps, parseuserintent.TaskType, llmsynthesis.TaskType) {
+		runner.RegisterWorker(w)
+	}
+
+	first := synthesisVariables()
+	first["conversationId"] = "conv-1"
+	result, err := runner.Run(first, parseuserintent.TaskType, llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	followUp := synthesisVariables()
+	followUp["conversationId"] = "conv-1"
+	followUp["question"] = "What about in Texas?"
+	result, err = runner.Run(followUp, parseuserintent.TaskType, llmsynthesis.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	// The follow-up names no franchise; the remembered one is resolved in.
+	assert.ElementsMatch(t, []interface{}{
+		map[string]interface{}{"type": "franchise_name", "value": "Bean There"},
+		map[string]interface{}{"type": "location", "value": "texas"},
+	}, result.Variables["entities"])
+
+	requests := stub.Requests()
+	require.Len(t, requests, 4)
+	last := requests[3]
+	assert.Equal(t, genaistub.PathGenerate, last.Path)
+	history, _ := last.Body["history"].([]interface{})
+	require.Len(t, history, 2)
+	assert.Equal(t, map[string]interface{}{"role": "user", "content": stubQuestion}, history[0])
+	assert.Contains(t, history[1].(map[string]interface{})["content"], "Re: "+stubQuestion)
+}
+
+// ==========================
+// Web Search Tests
+// ==========================
+
+func TestGenAIStub_WebSearchThroughSearXNGIsCachedAndFiltered(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{})
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+
+	cfg := genaiConfig(t, url, "", 0)
+	cfg.APIs.WebSearch.Provider = "searxng"
+	cfg.APIs.WebSearch.BaseURL = url + genaistub.PathSearch
+	cfg.APIs.WebSearch.CacheTTL = 60
+	cfg.APIs.WebSearch.DenyDomains = []string{"example.net"}
+	cfg.APIs.WebSearch.Recency = map[string]string{"market_research": "week"}
+	deps := testDeps(t, cfg)
+	deps.Redis = &database.RedisClient{Client: redis.NewClient(&redis.Options{Addr: mr.Addr()})}
+
+	runner := testkit.NewRunner()
+	runner.ProcessID = aiQueryProcess
+	for _, w := range buildWorkersWithDeps(t, deps, enrichwebsearch.TaskType) {
+		runner.RegisterWorker(w)
+	}
+
+	vars := map[string]interface{}{
+		"question": "Coffee franchise market trends",
+		"entities": []interface{}{},
+		"intent":   map[string]interface{}{"primaryIntent": "market_research"},
+	}
+	var urls []interface{}
+	for i := 0; i < 2; i++ {
+		result, err := runner.Run(vars, enrichwebsearch.TaskType)
+		require.NoError(t, err)
+		require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+		webData, _ := result.Variables["webData"].(map[string]interface{})
+		sources, _ := webData["sources"].([]interface{})
+		urls = urls[:0]
+		for _, src := range sources {
+			urls = append(urls, src.(map[string]interface{})["url"])
+		}
+		assert.Contains(t, webData["summary"], "from the past week")
+	}
+
+	// The directory listing on the denied domain is dropped.
+	assert.ElementsMatch(t, []interface{}{
+		"https://example.com/coffee-franchise-market-trends",
+		"https://www.franchise.org/buying-a-franchise",
+	}, urls)
+
+	// The second run is served from the cache.
+	requests := stub.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, genaistub.PathSearch, requests[0].Path)
+	assert.Equal(t, "Coffee franchise market trends", requests[0].Body["q"])
+	assert.Equal(t, "week", requests[0].Body["time_range"])
+}
+
+// ==========================
+// Intent Fallback Tests
+// ==========================
+
+// fallbackDeps enables the intent fallback, with the franchise catalog read
+// from a mock franchises table.
+func fallbackDeps(t *testing.T, cfg *config.Config) *camunda.Dependencies {
+	t.Helper()
+	cfg.APIs.GenAI.IntentFallback.Enabled = true
+	cfg.APIs.GenAI.IntentFallback.TrainingPath = "../../configs/intent-training.jsonl"
+	cfg.APIs.GenAI.IntentFallback.GazetteerPath = "../../configs/us-gazetteer.json"
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	mock.ExpectQuery("SELECT name, COALESCE\\(category, ''\\) FROM franchises").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "category"}).
+			AddRow("Bean There", "Coffee").
+			AddRow("Texas Roadhouse", "Restaurant"))
+
+	deps := testDeps(t, cfg)
+	deps.Postgres = &database.PostgresClient{DB: db}
+	return deps
+}
+
+func TestGenAIStub_IntentFallbackWhenParseIntentFails(t *testing.T) {
+	stub, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable}})
+	runner := testkit.NewRunner()
+	runner.ProcessID = aiQueryProcess
+	for _, w := range buildWorkersWithDeps(t, fallbackDeps(t, genaiConfig(t, url, "", 0)), parseuserintent.TaskType) {
+		runner.RegisterWorker(w)
+	}
+
+	result, err := runner.Run(map[string]interface{}{"question": "How much does Bean There cost in Austin? I have $250k."}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	// The API was tried with its two retries, then the job completed locally.
+	require.Len(t, result.Steps[0].Jobs, 1)
+	assert.Len(t, stub.Requests(), 3)
+	assert.Equal(t, parseuserintent.SourceFallback, result.Variables["source"])
+	analysis, _ := result.Variables["intentAnalysis"].(map[string]interface{})
+	assert.Equal(t, "cost_inquiry", analysis["primaryIntent"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "franchise_name", "value": "Bean There"},
+		map[string]interface{}{"type": "investment_amount", "value": "250000"},
+		map[string]interface{}{"type": "location", "value": "Austin"},
+	}, result.Variables["entities"])
+	assert.Equal(t, []interface{}{"internal_db", "search_index"}, result.Variables["dataSources"])
+}
+
+func TestGenAIStub_IntentFallbackBeatsSlowParseIntent(t *testing.T) {
+	_, url := newGenAIStub(t, genaistub.Config{Faults: genaistub.Faults{LatencyMs: 2000}})
+	cfg := genaiConfig(t, url, "", 1000)
+	cfg.APIs.GenAI.IntentFallback.After = 200
+	runner := testkit.NewRunner()
+	runner.ProcessID = aiQueryProcess
+	runner.MaxAttempts = 1
+	for _, w := range buildWorkersWithDeps(t, fallbackDeps(t, cfg), parseuserintent.TaskType) {
+		runner.RegisterWorker(w)
+	}
+
+	result, err := runner.Run(map[string]interface{}{"question": "Is the coffee market growing in Texas?"}, parseuserintent.TaskType)
+	require.NoError(t, err)
+	require.True(t, result.Completed(), "incident: %+v", result.Incident)
+
+	assert.Equal(t, parseuserintent.SourceFallback, result.Variables["source"])
+	analysis, _ := result.Variables["intentAnalysis"].(map[string]interface{})
+	assert.Equal(t, "market_research", analysis["primaryIntent"])
+	// The catalog spelling of the category wins over the built-in one.
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "location", "value": "Texas"},
+		map[string]interface{}{"type": "category", "value": "Coffee"},
+	}, result.Variables["entities"])
+}